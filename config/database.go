@@ -25,6 +25,20 @@ var MatchesCollection *mongo.Collection
 var UserTicketsCollection *mongo.Collection
 var TicketsCollection *mongo.Collection
 var TransactionsCollection *mongo.Collection
+var PlayerStatsCollection *mongo.Collection
+var RegistrationsCollection *mongo.Collection
+var PlayerLinksCollection *mongo.Collection
+var TeamInvitationsCollection *mongo.Collection
+var TournamentRostersCollection *mongo.Collection
+var TeamMembershipsCollection *mongo.Collection
+var ArchiveCollection *mongo.Collection
+var AuditLogsCollection *mongo.Collection
+var SessionsCollection *mongo.Collection
+var UserTokensCollection *mongo.Collection
+var MailOutboxCollection *mongo.Collection
+var LoginThrottlesCollection *mongo.Collection
+var SecurityEventsCollection *mongo.Collection
+var RoleAssignmentsCollection *mongo.Collection
 
 // MongoConnect establishes connection to MongoDB and returns database instance
 func MongoConnect(dbname string) (db *mongo.Database) {
@@ -55,6 +69,20 @@ func MongoConnect(dbname string) (db *mongo.Database) {
 	UserTicketsCollection = DB.Collection("user_tickets")
 	TicketsCollection = DB.Collection("tickets")
 	TransactionsCollection = DB.Collection("transactions")
+	PlayerStatsCollection = DB.Collection("player_stats")
+	RegistrationsCollection = DB.Collection("tournament_registrations")
+	PlayerLinksCollection = DB.Collection("player_links")
+	TeamInvitationsCollection = DB.Collection("team_invitations")
+	TournamentRostersCollection = DB.Collection("tournament_rosters")
+	TeamMembershipsCollection = DB.Collection("team_memberships")
+	ArchiveCollection = DB.Collection("archive")
+	AuditLogsCollection = DB.Collection("audit_logs")
+	SessionsCollection = DB.Collection("sessions")
+	UserTokensCollection = DB.Collection("user_tokens")
+	MailOutboxCollection = DB.Collection("mail_outbox")
+	LoginThrottlesCollection = DB.Collection("login_throttles")
+	SecurityEventsCollection = DB.Collection("security_events")
+	RoleAssignmentsCollection = DB.Collection("role_assignments")
 
 	return DB
 }
@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+// TeamInvitationTTL returns how long team invitations and join requests stay open
+// before they expire (TEAM_INVITATION_TTL, e.g. "72h")
+func TeamInvitationTTL() time.Duration {
+	return durationFromEnv("TEAM_INVITATION_TTL", 7*24*time.Hour)
+}
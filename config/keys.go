@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+)
+
+// PasetoKeyID returns the ID of the key that signs new tokens (PASETO_KEY_ID)
+func PasetoKeyID() string {
+	return stringFromEnv("PASETO_KEY_ID", "v1")
+}
+
+// PasetoPrivateKey returns the hex private key that signs new tokens (PASETO_PRIVATE_KEY),
+// falling back to PRIVATE_KEY from before keys had IDs
+func PasetoPrivateKey() string {
+	return stringFromEnv("PASETO_PRIVATE_KEY", os.Getenv("PRIVATE_KEY"))
+}
+
+// PasetoPublicKeys returns earlier keys that still verify tokens (PASETO_PUBLIC_KEYS), as
+// comma-separated "kid=hex" pairs with an optional "@RFC3339" retirement time
+func PasetoPublicKeys() string {
+	return os.Getenv("PASETO_PUBLIC_KEYS")
+}
+
+// PasetoLegacyPublicKey returns the key verifying tokens issued before keys had IDs (PUBLIC_KEY).
+// Remove it once those tokens have expired
+func PasetoLegacyPublicKey() string {
+	return os.Getenv("PUBLIC_KEY")
+}
+
+// PasetoLegacyRetireAt returns when the legacy PUBLIC_KEY stops verifying tokens
+// (PUBLIC_KEY_RETIRE_AT, RFC 3339). It is required with PUBLIC_KEY; set it to when the last
+// token signed before keys had IDs expires
+func PasetoLegacyRetireAt() string {
+	return os.Getenv("PUBLIC_KEY_RETIRE_AT")
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// LoginMaxFailures returns how many failed logins lock an account (LOGIN_MAX_FAILURES)
+func LoginMaxFailures() int {
+	return intFromEnv("LOGIN_MAX_FAILURES", 5)
+}
+
+// LoginIPMaxFailures returns how many failed logins, on any account, lock out a client IP
+// (LOGIN_IP_MAX_FAILURES)
+func LoginIPMaxFailures() int {
+	return intFromEnv("LOGIN_IP_MAX_FAILURES", 20)
+}
+
+// LoginFailureWindow returns how long a failed login keeps counting towards a lockout
+// (LOGIN_FAILURE_WINDOW, e.g. "15m")
+func LoginFailureWindow() time.Duration {
+	return durationFromEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute)
+}
+
+// LoginLockDuration returns how long a lockout lasts (LOGIN_LOCK_DURATION, e.g. "15m")
+func LoginLockDuration() time.Duration {
+	return durationFromEnv("LOGIN_LOCK_DURATION", 15*time.Minute)
+}
+
+// LoginBaseDelay returns the wait after the first failed login, doubled on every further
+// failure (LOGIN_BASE_DELAY, e.g. "1s")
+func LoginBaseDelay() time.Duration {
+	return durationFromEnv("LOGIN_BASE_DELAY", time.Second)
+}
+
+// LoginMaxDelay caps the wait between failed logins (LOGIN_MAX_DELAY, e.g. "30s")
+func LoginMaxDelay() time.Duration {
+	return durationFromEnv("LOGIN_MAX_DELAY", 30*time.Second)
+}
+
+func intFromEnv(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// MailerDriver returns how email is delivered (MAILER): "smtp" sends through SMTP_HOST,
+// anything else stores messages in the mail_outbox collection
+func MailerDriver() string {
+	return os.Getenv("MAILER")
+}
+
+// SMTPHost returns the SMTP server host (SMTP_HOST)
+func SMTPHost() string {
+	return stringFromEnv("SMTP_HOST", "localhost")
+}
+
+// SMTPPort returns the SMTP server port (SMTP_PORT); the default suits local test servers
+func SMTPPort() string {
+	return stringFromEnv("SMTP_PORT", "1025")
+}
+
+// SMTPUsername returns the SMTP username (SMTP_USERNAME); empty disables authentication
+func SMTPUsername() string {
+	return os.Getenv("SMTP_USERNAME")
+}
+
+// SMTPPassword returns the SMTP password (SMTP_PASSWORD)
+func SMTPPassword() string {
+	return os.Getenv("SMTP_PASSWORD")
+}
+
+// MailFrom returns the sender address of outgoing email (MAIL_FROM)
+func MailFrom() string {
+	return stringFromEnv("MAIL_FROM", "no-reply@embeck.local")
+}
+
+// AppBaseURL returns the frontend URL used in email links (APP_BASE_URL)
+func AppBaseURL() string {
+	return stringFromEnv("APP_BASE_URL", "http://localhost:5173")
+}
+
+// EmailVerificationTTL returns how long email verification links stay valid
+// (EMAIL_VERIFICATION_TTL, e.g. "48h")
+func EmailVerificationTTL() time.Duration {
+	return durationFromEnv("EMAIL_VERIFICATION_TTL", 48*time.Hour)
+}
+
+// PasswordResetTTL returns how long password reset links stay valid (PASSWORD_RESET_TTL, e.g. "1h")
+func PasswordResetTTL() time.Duration {
+	return durationFromEnv("PASSWORD_RESET_TTL", time.Hour)
+}
+
+// RequireVerifiedEmail reports whether login is refused until the email is verified.
+// Set REQUIRE_VERIFIED_EMAIL=true to turn it on
+func RequireVerifiedEmail() bool {
+	return os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true"
+}
+
+func stringFromEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
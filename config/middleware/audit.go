@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"embeck/model"
+	"embeck/pkg/audit"
+	"embeck/repository"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditMiddleware records every successful create, update or delete under /api/admin in the
+// audit log with the actor from the token claims, the targeted entity, the changed fields and
+// the request metadata. It has to run after AuthMiddleware
+func AuditMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		method := c.Method()
+		if method != fiber.MethodPost && method != fiber.MethodPut && method != fiber.MethodPatch && method != fiber.MethodDelete {
+			return c.Next()
+		}
+
+		parts := strings.SplitN(c.Path(), "/admin/", 2)
+		if len(parts) != 2 {
+			return c.Next()
+		}
+		target, ok := audit.Resolve(method, strings.Split(strings.Trim(parts[1], "/"), "/"))
+		if !ok {
+			return c.Next()
+		}
+
+		var before bson.M
+		if target.EntityID != "" {
+			before, _ = repository.GetAuditSnapshot(c.Context(), target.EntityType, target.EntityID)
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		status := c.Response().StatusCode()
+		if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+			return nil
+		}
+
+		if target.EntityID == "" {
+			target.EntityID = createdID(c.Response().Body(), target.EntityType)
+		}
+		var after bson.M
+		if target.EntityID != "" {
+			after, _ = repository.GetAuditSnapshot(c.Context(), target.EntityType, target.EntityID)
+		}
+
+		log := model.AuditLog{
+			Action:     target.Action,
+			EntityType: target.EntityType,
+			EntityID:   target.EntityID,
+			Changes:    audit.Diff(before, after),
+			Request: model.AuditRequest{
+				Method:    method,
+				Path:      c.Path(),
+				Query:     string(c.Request().URI().QueryString()),
+				Status:    status,
+				IP:        c.IP(),
+				UserAgent: c.Get(fiber.HeaderUserAgent),
+			},
+			CreatedAt: time.Now(),
+		}
+		if claims, ok := c.Locals("claims").(*model.TokenClaims); ok && claims != nil {
+			log.ActorID, _ = primitive.ObjectIDFromHex(claims.UserID)
+			log.ActorUsername = claims.Username
+			log.ActorRole = claims.Role
+		}
+
+		// A failed audit write is logged by the repository and does not undo the response
+		_ = repository.InsertAuditLog(c.Context(), log)
+		return nil
+	}
+}
+
+// createdID reads the ID of a created entity from a response body such as {"team_id": "..."}
+func createdID(body []byte, entityType string) string {
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	for _, key := range []string{entityType + "_id", "id", "_id"} {
+		if id, ok := response[key].(string); ok && primitive.IsValidObjectID(id) {
+			return id
+		}
+	}
+	return ""
+}
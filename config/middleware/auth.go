@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"embeck/config"
 	auth "embeck/pkg/auth"
+	"embeck/repository"
+	"errors"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -42,26 +45,33 @@ func AuthMiddleware() fiber.Handler {
 			})
 		}
 
+		// Reject tokens whose session was revoked or whose user changed role since issuance
+		user, err := repository.CheckSession(c.Context(), claims)
+		if err != nil {
+			if errors.Is(err, repository.ErrInvalidSession) || errors.Is(err, repository.ErrRoleChanged) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to verify session",
+			})
+		}
+
 		// Store user info in context
 		c.Locals("claims", claims)
 		c.Locals("user_id", claims.UserID)
 		c.Locals("username", claims.Username)
 		c.Locals("email", claims.Email)
 		c.Locals("role", claims.Role)
+		c.Locals("session_id", claims.SessionID)
+		c.Locals("two_factor", user.TwoFactor != nil && user.TwoFactor.Enabled)
 
 		return c.Next()
 	}
 }
 
-// AdminMiddleware checks if user has admin role
-func AdminMiddleware() fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		role := c.Locals("role")
-		if role != "admin" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error": "Admin access required",
-			})
-		}
-		return c.Next()
-	}
+// adminTwoFactorMet reports whether the admin 2FA policy, if enabled, is met by the logged-in user
+func adminTwoFactorMet(c *fiber.Ctx) bool {
+	return !config.RequireAdminTwoFactor() || c.Locals("two_factor") == true
 }
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"embeck/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TeamCaptainMiddleware checks that the logged-in user is linked to the captain of the team in
+// the :id route parameter. Admins meeting the admin 2FA policy pass without the check. The team
+// is stored in c.Locals("team")
+func TeamCaptainMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		team, err := repository.GetTeamByID(c.Context(), c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if team == nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Team not found",
+			})
+		}
+
+		if c.Locals("role") != "admin" || !adminTwoFactorMet(c) {
+			userID, _ := c.Locals("user_id").(string)
+			isCaptain, err := repository.IsTeamCaptain(c.Context(), userID, *team)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to verify team ownership",
+				})
+			}
+			if !isCaptain {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Only the verified captain of this team can manage it",
+				})
+			}
+		}
+
+		c.Locals("team", team)
+		return c.Next()
+	}
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"embeck/model"
+	"embeck/pkg/permission"
+	"embeck/repository"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TournamentScope resolves the tournament a request acts on. A nil tournament means the request
+// is not about one specific tournament and only unscoped role assignments apply
+type TournamentScope func(c *fiber.Ctx) (*primitive.ObjectID, error)
+
+// TournamentParam scopes a request to the tournament in the :id route parameter
+func TournamentParam(c *fiber.Ctx) (*primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, nil
+	}
+	return &objID, nil
+}
+
+// TournamentBody scopes a request to the tournament_id in its JSON body, e.g. when creating a match
+func TournamentBody(c *fiber.Ctx) (*primitive.ObjectID, error) {
+	var body struct {
+		TournamentID string `json:"tournament_id"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return nil, nil
+	}
+	objID, err := primitive.ObjectIDFromHex(body.TournamentID)
+	if err != nil {
+		return nil, nil
+	}
+	return &objID, nil
+}
+
+// TournamentQuery scopes a list request to the tournament_id query parameter, e.g. when listing
+// the matches of one tournament
+func TournamentQuery(c *fiber.Ctx) (*primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(c.Query("tournament_id"))
+	if err != nil {
+		return nil, nil
+	}
+	return &objID, nil
+}
+
+// TournamentOf scopes a request to the tournament of the match, registration or ticket in the
+// :id route parameter
+func TournamentOf(entityType string) TournamentScope {
+	return func(c *fiber.Ctx) (*primitive.ObjectID, error) {
+		return repository.TournamentOf(c.Context(), entityType, c.Params("id"))
+	}
+}
+
+// RequirePermission lets a request through when the logged-in user holds the permission through
+// a staff role. With a scope, roles assigned for the resolved tournament count as well. Admins
+// hold every permission but must meet the admin 2FA policy, if enabled
+func RequirePermission(perm string, scopes ...TournamentScope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Locals("role") == permission.Admin {
+			if !adminTwoFactorMet(c) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Two-factor authentication is required for admins, enable it via /api/auth/2fa/setup",
+				})
+			}
+			return c.Next()
+		}
+
+		assignments, status, message := userAssignments(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": message})
+		}
+
+		// Unscoped roles decide on their own; the tournament is only looked up when needed
+		allowed := permission.Allows(assignments, perm, nil)
+		for _, scope := range scopes {
+			if allowed || len(assignments) == 0 {
+				break
+			}
+			tournamentID, err := scope(c)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to verify permissions",
+				})
+			}
+			allowed = permission.Allows(assignments, perm, tournamentID)
+		}
+
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Permission " + perm + " required",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// RequirePermissionInAnyTournament lets a list request through when the logged-in user holds the
+// permission unscoped or for at least one tournament. In the latter case the tournaments are
+// stored in c.Locals("tournament_ids") and the handler lists only those
+func RequirePermissionInAnyTournament(perm string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Locals("role") == permission.Admin {
+			if !adminTwoFactorMet(c) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Two-factor authentication is required for admins, enable it via /api/auth/2fa/setup",
+				})
+			}
+			return c.Next()
+		}
+
+		assignments, status, message := userAssignments(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": message})
+		}
+
+		tournamentIDs, all := permission.Tournaments(assignments, perm)
+		if !all && len(tournamentIDs) == 0 {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Permission " + perm + " required",
+			})
+		}
+		if !all {
+			c.Locals("tournament_ids", tournamentIDs)
+		}
+		return c.Next()
+	}
+}
+
+// userAssignments loads the staff role assignments of the logged-in user. On failure it returns
+// the HTTP status and error message to respond with
+func userAssignments(c *fiber.Ctx) ([]model.RoleAssignment, int, string) {
+	claims, ok := c.Locals("claims").(*model.TokenClaims)
+	if !ok || claims == nil {
+		return nil, fiber.StatusUnauthorized, "Invalid or missing token claims"
+	}
+	userID, err := primitive.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		return nil, fiber.StatusUnauthorized, "Invalid or missing token claims"
+	}
+
+	assignments, err := repository.GetUserRoleAssignments(c.Context(), userID)
+	if err != nil {
+		return nil, fiber.StatusInternalServerError, "Failed to verify permissions"
+	}
+	return assignments, 0, ""
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TrustedProxies returns the addresses or CIDR ranges of the reverse proxies in front of the API
+// (TRUSTED_PROXIES, comma-separated). Only requests from them may set the client IP through
+// X-Forwarded-For; without any, every client behind a proxy shares the proxy's address
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// LoginIPThrottle reports whether failed logins are also throttled per client IP
+// (LOGIN_IP_THROTTLE). That needs the real client IP, so it defaults to on only when trusted
+// proxies are configured; a server taking client connections directly can turn it on
+func LoginIPThrottle() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("LOGIN_IP_THROTTLE"))
+	if err != nil {
+		return len(TrustedProxies()) > 0
+	}
+	return enabled
+}
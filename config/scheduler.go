@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// SchedulerEnabled reports whether the background status scheduler should run.
+// Set SCHEDULER_ENABLED=false to turn it off, e.g. when running several instances.
+func SchedulerEnabled() bool {
+	return os.Getenv("SCHEDULER_ENABLED") != "false"
+}
+
+// SchedulerInterval returns how often the scheduler runs (SCHEDULER_INTERVAL, e.g. "1m")
+func SchedulerInterval() time.Duration {
+	return durationFromEnv("SCHEDULER_INTERVAL", time.Minute)
+}
+
+// MatchOverdueGrace returns how long after its start time a match that has not started
+// is flagged as overdue (MATCH_OVERDUE_GRACE, e.g. "30m")
+func MatchOverdueGrace() time.Duration {
+	return durationFromEnv("MATCH_OVERDUE_GRACE", 30*time.Minute)
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
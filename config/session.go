@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+// AccessTokenTTL returns how long an access token stays valid (ACCESS_TOKEN_TTL, e.g. "15m")
+func AccessTokenTTL() time.Duration {
+	return durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+}
+
+// RefreshTokenTTL returns how long a login session lasts without being refreshed
+// (REFRESH_TOKEN_TTL, e.g. "720h")
+func RefreshTokenTTL() time.Duration {
+	return durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
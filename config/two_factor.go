@@ -0,0 +1,22 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// TOTPIssuer returns the issuer name shown in authenticator apps (TOTP_ISSUER)
+func TOTPIssuer() string {
+	return stringFromEnv("TOTP_ISSUER", "Embeck")
+}
+
+// RequireAdminTwoFactor reports whether admins must enable 2FA before using admin endpoints.
+// Set REQUIRE_ADMIN_2FA=true to turn it on
+func RequireAdminTwoFactor() bool {
+	return os.Getenv("REQUIRE_ADMIN_2FA") == "true"
+}
+
+// TwoFactorChallengeTTL returns how long the second login step stays open (TWO_FACTOR_CHALLENGE_TTL)
+func TwoFactorChallengeTTL() time.Duration {
+	return durationFromEnv("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute)
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/mailer"
+	"embeck/pkg/password"
+	"embeck/repository"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Mailer delivers account emails. main swaps in SMTP when MAILER=smtp
+var Mailer mailer.Mailer = repository.OutboxMailer{}
+
+// sendVerificationEmail issues a verification token for the user and emails the link
+func sendVerificationEmail(ctx context.Context, user model.User) error {
+	ttl := config.EmailVerificationTTL()
+	token, err := repository.IssueUserToken(ctx, user, model.TokenEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+	link := mailer.Link(config.AppBaseURL(), "/verify-email", token)
+	return Mailer.Send(ctx, mailer.VerificationEmail(user.Email, user.Username, link, ttl))
+}
+
+// VerifyEmail godoc
+// @Summary Verify Email
+// @Description Memverifikasi email user dengan token dari email verifikasi. Token hanya dapat dipakai sekali
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.VerifyEmailRequest true "Token verifikasi"
+// @Success 200 {object} map[string]interface{} "Email berhasil diverifikasi"
+// @Failure 400 {object} map[string]interface{} "Token tidak valid, sudah dipakai atau kedaluwarsa"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/auth/verify-email [post]
+func VerifyEmail(c *fiber.Ctx) error {
+	var req model.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "token is required",
+		})
+	}
+
+	err := repository.VerifyUserEmail(c.Context(), req.Token)
+	if errors.Is(err, repository.ErrInvalidUserToken) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to verify email",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerification godoc
+// @Summary Resend Verification Email
+// @Description Mengirim ulang email verifikasi. Respons selalu sama agar tidak membocorkan email yang terdaftar
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.EmailRequest true "Email akun"
+// @Success 200 {object} map[string]interface{} "Email verifikasi dikirim jika akun ada dan belum diverifikasi"
+// @Failure 400 {object} map[string]interface{} "Request data tidak valid"
+// @Router /api/auth/resend-verification [post]
+func ResendVerification(c *fiber.Ctx) error {
+	var req model.EmailRequest
+	if err := c.BodyParser(&req); err != nil || req.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "email is required",
+		})
+	}
+
+	user, err := repository.GetUserByEmail(c.Context(), strings.ToLower(req.Email))
+	if err != nil {
+		fmt.Printf("ResendVerification - Find User: %v\n", err)
+	}
+	if user != nil && user.EmailVerifiedAt == nil {
+		if err := sendVerificationEmail(c.Context(), *user); err != nil {
+			fmt.Printf("ResendVerification - Send: %v\n", err)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "If the account exists and is not verified yet, a verification email has been sent",
+	})
+}
+
+// ForgotPassword godoc
+// @Summary Forgot Password
+// @Description Mengirim tautan reset password ke email akun. Respons selalu sama agar tidak membocorkan email yang terdaftar
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.EmailRequest true "Email akun"
+// @Success 200 {object} map[string]interface{} "Email reset password dikirim jika akun ada"
+// @Failure 400 {object} map[string]interface{} "Request data tidak valid"
+// @Router /api/auth/forgot-password [post]
+func ForgotPassword(c *fiber.Ctx) error {
+	var req model.EmailRequest
+	if err := c.BodyParser(&req); err != nil || req.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "email is required",
+		})
+	}
+
+	user, err := repository.GetUserByEmail(c.Context(), strings.ToLower(req.Email))
+	if err != nil {
+		fmt.Printf("ForgotPassword - Find User: %v\n", err)
+	}
+	if user != nil {
+		ttl := config.PasswordResetTTL()
+		token, err := repository.IssueUserToken(c.Context(), *user, model.TokenPasswordReset, ttl)
+		if err == nil {
+			link := mailer.Link(config.AppBaseURL(), "/reset-password", token)
+			err = Mailer.Send(c.Context(), mailer.PasswordResetEmail(user.Email, user.Username, link, ttl))
+		}
+		if err != nil {
+			fmt.Printf("ForgotPassword - Send: %v\n", err)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "If the account exists, a password reset email has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset Password
+// @Description Mengganti password dengan token dari email reset password. Token hanya dapat dipakai sekali dan semua session user diakhiri
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.ResetPasswordRequest true "Token reset dan password baru"
+// @Success 200 {object} map[string]interface{} "Password berhasil diganti"
+// @Failure 400 {object} map[string]interface{} "Token tidak valid atau password terlalu pendek"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/auth/reset-password [post]
+func ResetPassword(c *fiber.Ctx) error {
+	var req model.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "token is required",
+		})
+	}
+	if len(req.Password) < 6 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Password must be at least 6 characters",
+		})
+	}
+
+	hashedPassword, err := password.HashPassword(req.Password)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process password",
+		})
+	}
+
+	err = repository.ResetUserPassword(c.Context(), req.Token, hashedPassword)
+	if errors.Is(err, repository.ErrInvalidUserToken) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to reset password",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Password reset successfully, please log in again",
+	})
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetAuditLogs godoc
+// @Summary Get Audit Logs
+// @Description Mendapatkan riwayat perubahan oleh admin (siapa, aksi, entitas, perubahan field, dan metadata request), terbaru lebih dulu
+// @Tags Audit (Admin)
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query string false "Filter user ID admin"
+// @Param entity_type query string false "Filter tipe entitas (player, team, tournament, match, user, registration, player_link, role_assignment, ticket)"
+// @Param entity_id query string false "Filter ID entitas"
+// @Param action query string false "Filter aksi (create, update, delete, purge, approve, ...)"
+// @Param from query string false "Mulai waktu (RFC3339)"
+// @Param to query string false "Sampai waktu (RFC3339)"
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah per halaman (default 50, maksimal 200)"
+// @Success 200 {object} model.AuditLogList
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/audit-logs [get]
+func GetAuditLogs(c *fiber.Ctx) error {
+	filter := model.AuditLogFilter{
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Action:     c.Query("action"),
+		Page:       c.QueryInt("page", 1),
+		Limit:      c.QueryInt("limit", 50),
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+
+	if actorID := c.Query("actor_id"); actorID != "" {
+		objID, err := primitive.ObjectIDFromHex(actorID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid actor_id format",
+			})
+		}
+		filter.ActorID = &objID
+	}
+
+	for _, bound := range []struct {
+		param  string
+		target **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_date",
+				Message: bound.param + " must be an RFC3339 timestamp",
+			})
+		}
+		*bound.target = &parsed
+	}
+
+	logs, err := repository.GetAuditLogs(c.Context(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve audit logs",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(logs)
+}
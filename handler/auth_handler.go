@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"embeck/config"
 	"embeck/model"
 	"embeck/pkg/password"
 	"embeck/repository"
+	"errors"
+	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"embeck/pkg/auth"
 
@@ -15,7 +21,7 @@ import (
 
 // Register godoc
 // @Summary Register New User
-// @Description Mendaftarkan user baru ke dalam sistem
+// @Description Mendaftarkan user baru ke dalam sistem dan mengirim email verifikasi
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -74,6 +80,12 @@ func Register(c *fiber.Ctx) error {
 	var userID string
 	if oid, ok := insertedID.(primitive.ObjectID); ok {
 		userID = oid.Hex()
+		user.ID = oid
+	}
+
+	// Registration succeeds even if the email cannot be sent; the user can ask for a new one
+	if err := sendVerificationEmail(c.Context(), user); err != nil {
+		fmt.Printf("Register - Send Verification: %v\n", err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(model.UserResponse{
@@ -82,16 +94,33 @@ func Register(c *fiber.Ctx) error {
 	})
 }
 
+// loginThrottled responds to a login attempt made before its throttle allows the next one
+func loginThrottled(c *fiber.Ctx, throttle *model.LoginThrottle) error {
+	retryAfter := int64(math.Ceil(time.Until(throttle.NextAttemptAt).Seconds()))
+	c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(retryAfter, 10))
+
+	message := "Too many failed login attempts, try again later"
+	if throttle.LockedUntil != nil && throttle.LockedUntil.After(time.Now()) {
+		message = "Login is temporarily locked after too many failed attempts"
+	}
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"error":       message,
+		"retry_after": retryAfter,
+	})
+}
+
 // Login godoc
 // @Summary User Login
-// @Description Login user dan mendapatkan PASETO token untuk autentikasi
+// @Description Login user dan mendapatkan PASETO access token berumur pendek beserta refresh token untuk autentikasi
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body model.LoginRequest true "Data login user"
-// @Success 200 {object} model.AuthResponse "Login berhasil dengan token"
+// @Success 200 {object} model.AuthResponse "Login berhasil dengan token, atau challenge_token jika akun memakai 2FA"
 // @Failure 400 {object} map[string]interface{} "Request data tidak valid"
 // @Failure 401 {object} map[string]interface{} "Kredensial tidak valid"
+// @Failure 403 {object} map[string]interface{} "Email belum diverifikasi (jika REQUIRE_VERIFIED_EMAIL=true)"
+// @Failure 429 {object} map[string]interface{} "Terlalu banyak percobaan gagal, coba lagi setelah header Retry-After"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/auth/login [post]
 func Login(c *fiber.Ctx) error {
@@ -109,27 +138,152 @@ func Login(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := repository.GetUserByEmail(c.Context(), strings.ToLower(req.Email))
+	email := strings.ToLower(req.Email)
+
+	// 🔒 Refuse attempts while the account or the client IP is delayed or locked out
+	throttle, err := repository.CheckLoginThrottle(c.Context(), email, c.IP())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to authenticate user",
 		})
 	}
+	if throttle != nil {
+		return loginThrottled(c, throttle)
+	}
 
-	if user == nil {
+	user, err := repository.GetUserByEmail(c.Context(), email)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to authenticate user",
+		})
+	}
+
+	if user == nil || password.CheckPassword(user.Password, req.Password) != nil {
+		var userID primitive.ObjectID
+		if user != nil {
+			userID = user.ID
+		}
+		if err := repository.RecordLoginFailure(c.Context(), email, userID, c.IP(), c.Get(fiber.HeaderUserAgent)); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to authenticate user",
+			})
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Invalid credentials",
 		})
 	}
 
-	if err := password.CheckPassword(user.Password, req.Password); err != nil {
+	if config.RequireVerifiedEmail() && user.EmailVerifiedAt == nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Email has not been verified, check your inbox or request a new verification email",
+		})
+	}
+
+	// Accounts with 2FA finish logging in at /api/auth/login/2fa
+	if user.TwoFactor != nil && user.TwoFactor.Enabled {
+		challenge, err := repository.IssueUserToken(c.Context(), *user, model.TokenTwoFactorLogin, config.TwoFactorChallengeTTL())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to start two-factor login",
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(model.AuthResponse{
+			Message:           "Two-factor authentication required",
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		})
+	}
+
+	// Failed attempts are only forgotten once the whole login has succeeded
+	_ = repository.ClearLoginFailures(c.Context(), email)
+
+	// 🔒 Start a session and issue a short-lived access token with its refresh token
+	session, refreshToken, err := repository.CreateSession(c.Context(), user.ID, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create session",
+		})
+	}
+
+	return authTokens(c, "Login successful", user, session, refreshToken)
+}
+
+// RefreshToken godoc
+// @Summary Refresh Access Token
+// @Description Menukar refresh token dengan access token baru. Refresh token ikut diganti dan yang lama tidak bisa dipakai lagi; memakai refresh token lama akan mengakhiri session
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.RefreshRequest true "Refresh token dari login atau refresh sebelumnya"
+// @Success 200 {object} model.AuthResponse "Token baru"
+// @Failure 400 {object} map[string]interface{} "Request data tidak valid"
+// @Failure 401 {object} map[string]interface{} "Refresh token tidak valid, sudah dipakai atau session sudah berakhir"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/auth/refresh [post]
+func RefreshToken(c *fiber.Ctx) error {
+	var req model.RefreshRequest
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "refresh_token is required",
+		})
+	}
+
+	session, user, refreshToken, err := repository.RefreshSession(c.Context(), req.RefreshToken)
+	if errors.Is(err, repository.ErrInvalidSession) {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid credentials",
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to refresh session",
+		})
+	}
+
+	return authTokens(c, "Token refreshed", user, session, refreshToken)
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Mengakhiri session dari refresh token sehingga access token dan refresh token-nya langsung tidak berlaku. Dengan all=true semua session user diakhiri
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.LogoutRequest true "Refresh token session yang diakhiri"
+// @Success 200 {object} map[string]interface{} "Logout berhasil"
+// @Failure 400 {object} map[string]interface{} "Request data tidak valid"
+// @Failure 401 {object} map[string]interface{} "Refresh token tidak valid atau session sudah berakhir"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/auth/logout [post]
+func Logout(c *fiber.Ctx) error {
+	var req model.LogoutRequest
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "refresh_token is required",
+		})
+	}
+
+	err := repository.RevokeSession(c.Context(), req.RefreshToken, req.All)
+	if errors.Is(err, repository.ErrInvalidSession) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
 		})
 	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to end session",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Logout successful",
+	})
+}
 
-	// 🔒 Generate PASETO token (valid for 24 hours as per auth pkg)
-	token, err := auth.GenerateToken(user)
+// authTokens answers a login or refresh with a new access token for the session
+func authTokens(c *fiber.Ctx, message string, user *model.User, session *model.Session, refreshToken string) error {
+	ttl := config.AccessTokenTTL()
+	token, err := auth.GenerateToken(user, session.ID.Hex(), ttl)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to generate token",
@@ -137,12 +291,16 @@ func Login(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{
-		Message:  "Login successful",
-		Token:    token,
-		Role:     user.Role,
-		UserID:   user.ID.Hex(),
-		Username: user.Username,
-		Email:    user.Email,
+		Message:      message,
+		Token:        token,
+		Role:         user.Role,
+		UserID:       user.ID.Hex(),
+		Username:     user.Username,
+		Email:        user.Email,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(ttl.Seconds()),
+		// Admins without 2FA can log in to enroll but cannot use admin endpoints yet
+		TwoFactorSetupRequired: config.RequireAdminTwoFactor() && user.Role == "admin" && (user.TwoFactor == nil || !user.TwoFactor.Enabled),
 	})
 }
 
@@ -154,6 +312,7 @@ func Login(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} model.UserProfile "Profil user"
+// @Header 200 {string} ETag "Versi user"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 404 {object} map[string]interface{} "User tidak ditemukan"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -183,14 +342,19 @@ func GetProfile(c *fiber.Ctx) error {
 
 	// Return user profile (without password)
 	profile := model.UserProfile{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		Role:             user.Role,
+		PlayerID:         user.PlayerID,
+		EmailVerified:    user.EmailVerifiedAt != nil,
+		TwoFactorEnabled: user.TwoFactor != nil && user.TwoFactor.Enabled,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+		Version:          user.Version,
 	}
 
+	setETag(c, user.Version)
 	return c.Status(fiber.StatusOK).JSON(profile)
 }
 
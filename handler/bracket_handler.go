@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/pkg/bracket"
+	"embeck/repository"
+	"fmt"
+	"math/rand"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GenerateBracket godoc
+// @Summary Generate Tournament Bracket
+// @Description Membuat semua pertandingan bracket (single atau double elimination sesuai bracket_format turnamen) dari teams_participating, termasuk bye
+// @Tags Tournament Management (Admin)
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID"
+// @Param request body model.BracketRequest true "Bracket options (seeding: manual atau random)"
+// @Success 201 {object} model.BracketResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/tournaments/{id}/bracket [post]
+func GenerateBracket(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req model.BracketRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	seeded, err := seedTeams(activeTeams(*tournament), req.Seeding, req.TeamIDs)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_seeding",
+			Message: err.Error(),
+		})
+	}
+
+	schedule := bracket.Schedule{
+		MatchDate: req.MatchDate,
+		MatchTime: req.MatchTime,
+		Location:  req.Location,
+	}
+	if schedule.MatchDate.IsZero() {
+		schedule.MatchDate = tournament.StartDate
+	}
+	if schedule.MatchTime == "" {
+		schedule.MatchTime = "TBD"
+	}
+
+	var matches []model.Match
+	switch tournament.BracketFormat {
+	case bracket.FormatDoubleElimination:
+		matches, err = bracket.DoubleElimination(tournament.ID, seeded, schedule, tournament.BracketReset)
+	default:
+		matches, err = bracket.SingleElimination(tournament.ID, seeded, schedule)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	}
+
+	insertedIDs, err := repository.CreateBracket(c.Context(), tournament.ID, matches)
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Error:   "db_conflict",
+			Message: fmt.Sprintf("Gagal membuat bracket: %v", err),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.BracketResponse{
+		Message:      "Bracket generated successfully",
+		TournamentID: tournament.ID.Hex(),
+		TotalMatches: len(insertedIDs),
+		MatchIDs:     hexIDs(insertedIDs),
+	})
+}
+
+// seedTeams orders the participating teams by seed, either in the given manual order
+// (defaulting to the order of teams_participating) or randomly
+func seedTeams(participating []primitive.ObjectID, seeding string, teamIDs []string) ([]primitive.ObjectID, error) {
+	switch seeding {
+	case "", "manual":
+		if len(teamIDs) == 0 {
+			return participating, nil
+		}
+
+		if len(teamIDs) != len(participating) {
+			return nil, fmt.Errorf("team_ids must list all %d participating teams", len(participating))
+		}
+
+		isParticipating := make(map[primitive.ObjectID]bool, len(participating))
+		for _, teamID := range participating {
+			isParticipating[teamID] = true
+		}
+
+		seeded := make([]primitive.ObjectID, 0, len(teamIDs))
+		for _, teamID := range teamIDs {
+			objID, err := primitive.ObjectIDFromHex(teamID)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid team ID: %s", teamID)
+			}
+			if !isParticipating[objID] {
+				return nil, fmt.Errorf("Team %s is not participating or listed twice", teamID)
+			}
+			delete(isParticipating, objID)
+			seeded = append(seeded, objID)
+		}
+		return seeded, nil
+	case "random":
+		seeded := make([]primitive.ObjectID, len(participating))
+		copy(seeded, participating)
+		rand.Shuffle(len(seeded), func(i, j int) {
+			seeded[i], seeded[j] = seeded[j], seeded[i]
+		})
+		return seeded, nil
+	default:
+		return nil, fmt.Errorf("Seeding must be 'manual' or 'random'")
+	}
+}
+
+// hexIDs converts ObjectIDs into their hex strings
+func hexIDs(ids []primitive.ObjectID) []string {
+	hex := make([]string, len(ids))
+	for i, id := range ids {
+		hex[i] = id.Hex()
+	}
+	return hex
+}
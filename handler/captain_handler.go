@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetCaptainTeam godoc
+// @Summary Get My Team (captain)
+// @Description Mendapatkan detail team yang dikelola oleh captain yang login
+// @Tags Captain
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} model.TeamWithDetails
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/captain/teams/{id} [get]
+func GetCaptainTeam(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+
+	details, err := repository.GetTeamByIDWithDetails(c.Context(), team.ID.Hex())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve team",
+		})
+	}
+	if details == nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Team not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(details)
+}
+
+// UpdateCaptainRoster godoc
+// @Summary Update Team Roster (captain)
+// @Description Mengatur role anggota (starter, substitute, coach) dan mengeluarkan anggota dari team; captain_id opsional untuk menyerahkan posisi captain ke anggota lain. Anggota baru ditambahkan melalui undangan team
+// @Tags Captain
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body model.CaptainRosterRequest true "Roster data"
+// @Success 200 {object} model.TeamResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/roster [put]
+func UpdateCaptainRoster(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+	var req model.CaptainRosterRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	if len(req.Roster) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "missing_fields",
+			Message: "roster is required",
+		})
+	}
+
+	members, err := parseTeamRoster(req.Roster)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: err.Error(),
+		})
+	}
+
+	captainID := team.CaptainID
+	if req.CaptainID != "" {
+		objID, err := primitive.ObjectIDFromHex(req.CaptainID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid captain_id format",
+			})
+		}
+		captainID = objID
+	}
+
+	if err := repository.UpdateTeamRoster(c.Context(), *team, members, captainID); err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TeamResponse{
+		Message: "Roster updated successfully",
+		TeamID:  team.ID.Hex(),
+	})
+}
+
+// UploadCaptainTeamLogo godoc
+// @Summary Upload Team Logo (captain)
+// @Description Upload logo team (PNG, JPG, JPEG) dan langsung memasangnya sebagai logo team
+// @Tags Captain
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param file formData file true "Team logo image file"
+// @Success 200 {object} model.UploadResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/logo [post]
+func UploadCaptainTeamLogo(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+
+	response, failure := saveTeamLogo(c)
+	if failure != nil {
+		return c.Status(failure.status).JSON(failure.response)
+	}
+
+	if err := repository.SetTeamLogo(c.Context(), team.ID, response.FileURL); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to update team logo",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ApplyForTournament godoc
+// @Summary Apply For Tournament (captain)
+// @Description Mendaftarkan team ke turnamen selama jendela registrasi dibuka. Pendaftaran masuk ke antrean persetujuan admin
+// @Tags Captain
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body model.RegistrationRequest true "Tournament to register for"
+// @Success 201 {object} model.RegistrationResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/registrations [post]
+func ApplyForTournament(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+	var req model.RegistrationRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	tournamentObjID, err := primitive.ObjectIDFromHex(req.TournamentID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament_id format",
+		})
+	}
+
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	insertedID, err := repository.ApplyForTournament(c.Context(), model.TournamentRegistration{
+		TournamentID: tournamentObjID,
+		TeamID:       team.ID,
+		AppliedBy:    userObjID,
+	})
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.RegistrationResponse{
+		Message:        "Registration submitted successfully",
+		RegistrationID: insertedID.(primitive.ObjectID).Hex(),
+		Status:         model.RegistrationPending,
+	})
+}
+
+// GetCaptainTeamRegistrations godoc
+// @Summary Get Team Registrations (captain)
+// @Description Mendapatkan semua pendaftaran turnamen milik team, termasuk status dan alasan penolakan
+// @Tags Captain
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {array} model.TournamentRegistration
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/registrations [get]
+func GetCaptainTeamRegistrations(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+
+	registrations, err := repository.GetTeamRegistrations(c.Context(), team.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve registrations",
+		})
+	}
+	if registrations == nil {
+		registrations = []model.TournamentRegistration{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(registrations)
+}
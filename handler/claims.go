@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// claimsUserID returns the ID of the user in the token claims stored by the auth middleware
+func claimsUserID(c *fiber.Ctx) (primitive.ObjectID, bool) {
+	claims, ok := c.Locals("claims").(*model.TokenClaims)
+	if !ok || claims == nil {
+		return primitive.NilObjectID, false
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return userObjID, true
+}
+
+// unauthorizedClaims responds to a request whose token claims are missing or invalid
+func unauthorizedClaims(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+		Error:   "unauthorized",
+		Message: "Invalid or missing token claims",
+	})
+}
+
+// claimsPlayerID returns the logged-in user and the player linked to their account.
+// The player ID is nil when the account is not linked to a verified player
+func claimsPlayerID(c *fiber.Ctx) (primitive.ObjectID, *primitive.ObjectID, error) {
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return primitive.NilObjectID, nil, fmt.Errorf("invalid token claims")
+	}
+
+	user, err := repository.GetUserByID(c.Context(), userObjID.Hex())
+	if err != nil {
+		return primitive.NilObjectID, nil, err
+	}
+	if user == nil {
+		return primitive.NilObjectID, nil, fmt.Errorf("user not found")
+	}
+	return userObjID, user.PlayerID, nil
+}
+
+// playerNotLinked responds to a player-only request from an account without a verified player link
+func playerNotLinked(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+		Error:   "player_not_linked",
+		Message: "Akun belum terhubung dengan player terverifikasi",
+	})
+}
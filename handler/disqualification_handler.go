@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DisqualifyTeam godoc
+// @Summary Disqualify Team
+// @Description Mendiskualifikasi team dari turnamen. Semua pertandingan team yang belum selesai otomatis dicatat sebagai forfeit (outcome disqualification) dengan skor default untuk lawan
+// @Tags Tournaments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID"
+// @Param request body model.DisqualifyTeamRequest true "Disqualification data"
+// @Success 200 {object} model.DisqualifyTeamResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/tournaments/{id}/disqualify [post]
+func DisqualifyTeam(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req model.DisqualifyTeamRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	if req.TeamID == "" || strings.TrimSpace(req.Reason) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "missing_fields",
+			Message: "team_id and reason are required",
+		})
+	}
+
+	teamObjID, err := primitive.ObjectIDFromHex(req.TeamID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid team_id format",
+		})
+	}
+
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	forfeited, err := repository.DisqualifyTeam(c.Context(), tournament.ID, model.TeamDisqualification{
+		TeamID:         teamObjID,
+		Reason:         req.Reason,
+		DisqualifiedBy: actor,
+		DisqualifiedAt: time.Now(),
+	})
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "tidak ditemukan"):
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Error: "not_found", Message: err.Error()})
+		case strings.Contains(err.Error(), "sudah didiskualifikasi"), strings.Contains(err.Error(), "tidak terdaftar"):
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Error: "conflict", Message: err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "disqualify_failed",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.DisqualifyTeamResponse{
+		Message:          "Team disqualified successfully",
+		TournamentID:     tournament.ID.Hex(),
+		TeamID:           teamObjID.Hex(),
+		ForfeitedMatches: hexIDs(forfeited),
+	})
+}
+
+// activeTeams returns the participating teams of a tournament that have not been disqualified
+func activeTeams(tournament model.Tournament) []primitive.ObjectID {
+	disqualified := make(map[primitive.ObjectID]bool, len(tournament.Disqualifications))
+	for _, dq := range tournament.Disqualifications {
+		disqualified[dq.TeamID] = true
+	}
+
+	teams := make([]primitive.ObjectID, 0, len(tournament.TeamsParticipating))
+	for _, teamID := range tournament.TeamsParticipating {
+		if !disqualified[teamID] {
+			teams = append(teams, teamID)
+		}
+	}
+	return teams
+}
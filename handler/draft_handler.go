@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/pkg/draft"
+	"embeck/repository"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SetGameDraft godoc
+// @Summary Set Game Draft
+// @Description Mencatat draft hero (ban dan pick per team secara berurutan) untuk satu game, termasuk player dan role yang memainkan hero
+// @Tags Matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param gameId path string true "Game ID"
+// @Param request body model.GameDraftRequest true "Draft data"
+// @Success 200 {object} model.MatchGameResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/games/{gameId}/draft [put]
+func SetGameDraft(c *fiber.Ctx) error {
+	id := c.Params("id")
+	gameID := c.Params("gameId")
+	var req model.GameDraftRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	gameDraft, err := parseGameDraftRequest(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: err.Error(),
+		})
+	}
+
+	if err := repository.SetGameDraft(c.Context(), id, gameID, gameDraft); err != nil {
+		return matchGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.MatchGameResponse{
+		Message: "Draft saved successfully",
+		MatchID: id,
+		GameID:  gameID,
+	})
+}
+
+// GetTournamentDraftStats godoc
+// @Summary Get Hero Pick/Ban Stats (public)
+// @Description Mendapatkan statistik pick, ban, presence dan win rate hero dari semua game dalam turnamen
+// @Tags Tournament Data (Public)
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} model.DraftStatsResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/tournaments/{id}/draft-stats [get]
+func GetTournamentDraftStats(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	matches, err := repository.GetTournamentDraftMatches(c.Context(), tournament.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve drafts",
+		})
+	}
+
+	totalGames, heroes := draft.Stats(matches)
+	if heroes == nil {
+		heroes = []model.HeroStat{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.DraftStatsResponse{
+		TournamentID: tournament.ID.Hex(),
+		TotalGames:   totalGames,
+		Heroes:       heroes,
+	})
+}
+
+// parseGameDraftRequest converts the string IDs of a draft request into a draft model
+func parseGameDraftRequest(req model.GameDraftRequest) (model.GameDraft, error) {
+	gameDraft := model.GameDraft{
+		Bans:  make([]model.DraftBan, 0, len(req.Bans)),
+		Picks: make([]model.DraftPick, 0, len(req.Picks)),
+	}
+
+	for _, ban := range req.Bans {
+		teamObjID, err := primitive.ObjectIDFromHex(ban.TeamID)
+		if err != nil {
+			return gameDraft, fmt.Errorf("Invalid team_id format on ban %d", ban.Order)
+		}
+		gameDraft.Bans = append(gameDraft.Bans, model.DraftBan{
+			Order:  ban.Order,
+			TeamID: teamObjID,
+			Hero:   ban.Hero,
+		})
+	}
+
+	for _, pick := range req.Picks {
+		teamObjID, err := primitive.ObjectIDFromHex(pick.TeamID)
+		if err != nil {
+			return gameDraft, fmt.Errorf("Invalid team_id format on pick %d", pick.Order)
+		}
+		draftPick := model.DraftPick{
+			Order:  pick.Order,
+			TeamID: teamObjID,
+			Hero:   pick.Hero,
+			Role:   pick.Role,
+		}
+		if pick.PlayerID != "" {
+			playerObjID, err := primitive.ObjectIDFromHex(pick.PlayerID)
+			if err != nil {
+				return gameDraft, fmt.Errorf("Invalid player_id format on pick %d", pick.Order)
+			}
+			draftPick.PlayerID = &playerObjID
+		}
+		gameDraft.Picks = append(gameDraft.Picks, draftPick)
+	}
+
+	return gameDraft, nil
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/pkg/etag"
+	"embeck/repository"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errIfMatchRequired is returned when an update does not say which version it was based on
+var errIfMatchRequired = errors.New("Header If-Match wajib diisi dengan ETag dari data yang akan diubah")
+
+// ifMatchVersion reads the entity version an update was based on from the If-Match header
+func ifMatchVersion(c *fiber.Ctx) (int64, error) {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		return 0, errIfMatchRequired
+	}
+	return etag.Parse(header)
+}
+
+// setETag exposes the version of the returned entity as its ETag
+func setETag(c *fiber.Ctx, version int64) {
+	c.Set(fiber.HeaderETag, etag.Format(version))
+}
+
+// versionError answers updates whose If-Match header is missing (428), malformed (400) or
+// refers to an outdated version (412)
+func versionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errIfMatchRequired):
+		return c.Status(fiber.StatusPreconditionRequired).JSON(model.ErrorResponse{
+			Error:   "precondition_required",
+			Message: err.Error(),
+		})
+	case errors.Is(err, repository.ErrStaleVersion):
+		return c.Status(fiber.StatusPreconditionFailed).JSON(model.ErrorResponse{
+			Error:   "stale_version",
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_if_match",
+			Message: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/pkg/groupstage"
+	"embeck/repository"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GenerateGroupStage godoc
+// @Summary Generate Group Stage
+// @Description Membagi teams_participating ke dalam beberapa group dan membuat semua pertandingan round-robin (single atau double)
+// @Tags Tournament Management (Admin)
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID"
+// @Param request body model.GroupStageRequest true "Group stage options"
+// @Success 201 {object} model.GroupStageResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/tournaments/{id}/groups [post]
+func GenerateGroupStage(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req model.GroupStageRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	if req.GroupCount == 0 {
+		req.GroupCount = 1
+	}
+
+	for _, tiebreaker := range req.Tiebreakers {
+		if !groupstage.ValidTiebreakers[tiebreaker] {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_tiebreaker",
+				Message: "Tiebreakers must be 'head_to_head', 'game_diff', or 'games_won'",
+			})
+		}
+	}
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	seeded, err := seedTeams(activeTeams(*tournament), req.Seeding, req.TeamIDs)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_seeding",
+			Message: err.Error(),
+		})
+	}
+
+	groups, err := groupstage.Distribute(seeded, req.GroupCount)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	}
+
+	stage := model.GroupStage{
+		Groups:           groups,
+		DoubleRoundRobin: req.DoubleRoundRobin,
+		PointsPerWin:     3,
+		PointsPerDraw:    1,
+		Tiebreakers:      req.Tiebreakers,
+	}
+	if req.PointsPerWin != nil {
+		stage.PointsPerWin = *req.PointsPerWin
+	}
+	if req.PointsPerDraw != nil {
+		stage.PointsPerDraw = *req.PointsPerDraw
+	}
+	if len(stage.Tiebreakers) == 0 {
+		stage.Tiebreakers = groupstage.DefaultTiebreakers
+	}
+
+	schedule := groupstage.Schedule{
+		MatchDate: req.MatchDate,
+		MatchTime: req.MatchTime,
+		Location:  req.Location,
+	}
+	if schedule.MatchDate.IsZero() {
+		schedule.MatchDate = tournament.StartDate
+	}
+	if schedule.MatchTime == "" {
+		schedule.MatchTime = "TBD"
+	}
+
+	matches := groupstage.RoundRobin(tournament.ID, groups, req.DoubleRoundRobin, schedule)
+
+	insertedCount, err := repository.CreateGroupStage(c.Context(), tournament.ID, stage, matches)
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Error:   "db_conflict",
+			Message: fmt.Sprintf("Gagal membuat group stage: %v", err),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.GroupStageResponse{
+		Message:      "Group stage generated successfully",
+		TournamentID: tournament.ID.Hex(),
+		Groups:       groups,
+		TotalMatches: insertedCount,
+	})
+}
+
+// GetTournamentStandings godoc
+// @Summary Get Tournament Standings (public)
+// @Description Mendapatkan klasemen setiap group yang dihitung dari pertandingan group stage yang sudah selesai
+// @Tags Tournament Data (Public)
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} model.StandingsResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/tournaments/{id}/standings [get]
+func GetTournamentStandings(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	if tournament.GroupStage == nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Tournament has no group stage",
+		})
+	}
+
+	matches, err := repository.GetGroupMatches(c.Context(), tournament.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve group matches",
+		})
+	}
+
+	groups := groupstage.Standings(*tournament.GroupStage, matches)
+
+	teams, err := repository.GetTeamsBasicInfoByIDs(c.Context(), tournament.TeamsParticipating)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve teams",
+		})
+	}
+	for g := range groups {
+		for i := range groups[g].Standings {
+			team := teams[groups[g].Standings[i].TeamID]
+			groups[g].Standings[i].TeamName = team.TeamName
+			groups[g].Standings[i].LogoURL = team.LogoURL
+		}
+	}
+
+	tiebreakers := tournament.GroupStage.Tiebreakers
+	if len(tiebreakers) == 0 {
+		tiebreakers = groupstage.DefaultTiebreakers
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.StandingsResponse{
+		TournamentID: tournament.ID.Hex(),
+		Tiebreakers:  tiebreakers,
+		Groups:       groups,
+	})
+}
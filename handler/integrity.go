@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// deleteError maps repository errors of delete operations to HTTP responses. Deletes refused
+// because of dependent records answer 409 with the list of blockers
+func deleteError(c *fiber.Ctx, err error) error {
+	var conflict *repository.DeleteConflictError
+	if errors.As(err, &conflict) {
+		return c.Status(fiber.StatusConflict).JSON(model.DeleteConflictResponse{
+			Error:    "delete_conflict",
+			Message:  conflict.Error() + "; gunakan ?cascade=true untuk membersihkan data terkait",
+			Blockers: conflict.Blockers,
+		})
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "tidak ditemukan"), strings.Contains(message, "tidak ada data yang dihapus"):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: message,
+		})
+	case strings.Contains(message, "invalid"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: message,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: message,
+		})
+	}
+}
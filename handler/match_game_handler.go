@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AddMatchGame godoc
+// @Summary Add Game To Match
+// @Description Mencatat satu game dalam series BO1/BO3/BO5/BO7; skor series dan pemenang dihitung otomatis
+// @Tags Matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param request body model.MatchGameRequest true "Game data"
+// @Success 201 {object} model.MatchGameResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse "Game lain dicatat bersamaan, muat ulang data match"
+// @Router /api/admin/matches/{id}/games [post]
+func AddMatchGame(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req model.MatchGameRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	if req.WinnerTeamID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "missing_fields",
+			Message: "winner_team_id is required",
+		})
+	}
+
+	game, err := parseMatchGameRequest(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: err.Error(),
+		})
+	}
+
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	added, match, err := repository.AddMatchGame(c.Context(), id, game, actor)
+	if err != nil {
+		return matchGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(matchGameResponse("Game added successfully", match, added.ID.Hex()))
+}
+
+// UpdateMatchGame godoc
+// @Summary Update Match Game
+// @Description Memperbarui data satu game dalam series; skor series dan pemenang dihitung ulang
+// @Tags Matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param gameId path string true "Game ID"
+// @Param request body model.MatchGameRequest true "Game data"
+// @Success 200 {object} model.MatchGameResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse "Game lain dicatat bersamaan, muat ulang data match"
+// @Router /api/admin/matches/{id}/games/{gameId} [put]
+func UpdateMatchGame(c *fiber.Ctx) error {
+	id := c.Params("id")
+	gameID := c.Params("gameId")
+	var req model.MatchGameRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	game, err := parseMatchGameRequest(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: err.Error(),
+		})
+	}
+
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	match, err := repository.UpdateMatchGame(c.Context(), id, gameID, game, actor)
+	if err != nil {
+		return matchGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(matchGameResponse("Game updated successfully", match, gameID))
+}
+
+// VoidMatchGame godoc
+// @Summary Void Match Game
+// @Description Membatalkan (void) satu game sehingga tidak dihitung dalam skor series
+// @Tags Matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param gameId path string true "Game ID"
+// @Param request body model.VoidMatchGameRequest false "Void reason"
+// @Success 200 {object} model.MatchGameResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse "Game lain dicatat bersamaan, muat ulang data match"
+// @Router /api/admin/matches/{id}/games/{gameId}/void [post]
+func VoidMatchGame(c *fiber.Ctx) error {
+	id := c.Params("id")
+	gameID := c.Params("gameId")
+	var req model.VoidMatchGameRequest
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid request data",
+			})
+		}
+	}
+
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	match, err := repository.VoidMatchGame(c.Context(), id, gameID, req.Reason, actor)
+	if err != nil {
+		return matchGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(matchGameResponse("Game voided successfully", match, gameID))
+}
+
+// parseMatchGameRequest converts the string IDs of a game request into a game model
+func parseMatchGameRequest(req model.MatchGameRequest) (model.MatchGame, error) {
+	game := model.MatchGame{DurationSeconds: req.DurationSeconds}
+
+	if req.WinnerTeamID != "" {
+		objID, err := primitive.ObjectIDFromHex(req.WinnerTeamID)
+		if err != nil {
+			return game, fmt.Errorf("Invalid winner_team_id format")
+		}
+		game.WinnerTeamID = objID
+	}
+
+	optionalIDs := []struct {
+		value  string
+		field  string
+		target **primitive.ObjectID
+	}{
+		{req.BlueTeamID, "blue_team_id", &game.BlueTeamID},
+		{req.RedTeamID, "red_team_id", &game.RedTeamID},
+		{req.MVPPlayerID, "mvp_player_id", &game.MVPPlayerID},
+	}
+	for _, optional := range optionalIDs {
+		if optional.value == "" {
+			continue
+		}
+		objID, err := primitive.ObjectIDFromHex(optional.value)
+		if err != nil {
+			return game, fmt.Errorf("Invalid %s format", optional.field)
+		}
+		*optional.target = &objID
+	}
+
+	return game, nil
+}
+
+// matchGameError maps repository errors of game operations to HTTP responses
+func matchGameError(c *fiber.Ctx, err error) error {
+	// Another game change landed between reading the match and writing this one
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Error:   "conflict",
+			Message: err.Error(),
+		})
+	}
+	if strings.Contains(err.Error(), "tidak ditemukan") {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+		Error:   "validation_error",
+		Message: err.Error(),
+	})
+}
+
+// matchGameResponse builds the response for game operations from the recalculated match
+func matchGameResponse(message string, match *model.Match, gameID string) model.MatchGameResponse {
+	response := model.MatchGameResponse{
+		Message: message,
+		MatchID: match.ID.Hex(),
+		GameID:  gameID,
+	}
+	if match.ResultTeamAScore != nil {
+		response.ResultTeamAScore = *match.ResultTeamAScore
+	}
+	if match.ResultTeamBScore != nil {
+		response.ResultTeamBScore = *match.ResultTeamBScore
+	}
+	if match.WinnerTeamID != nil {
+		response.WinnerTeamID = match.WinnerTeamID.Hex()
+	}
+	return response
+}
@@ -2,7 +2,10 @@ package handler
 
 import (
 	"embeck/model"
+	"embeck/pkg/matchstate"
+	"embeck/pkg/series"
 	"embeck/repository"
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
@@ -33,24 +36,37 @@ func CreateMatch(c *fiber.Ctx) error {
 	}
 
 	// Validation
-	if req.TournamentID == "" || req.TeamAID == "" || req.TeamBID == "" || req.MatchTime == "" || req.Round == "" || req.Status == "" {
+	if req.TournamentID == "" || req.TeamAID == "" || req.TeamBID == "" || req.MatchTime == "" || req.Round == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Error:   "missing_fields",
-			Message: "tournament_id, team_a_id, team_b_id, match_date, match_time, round, and status are required",
+			Message: "tournament_id, team_a_id, team_b_id, match_date, match_time, and round are required",
 		})
 	}
 
-	// Validate status
-	validStatuses := map[string]bool{
-		"scheduled": true,
-		"ongoing":   true,
-		"completed": true,
-		"cancelled": true,
+	// Validate status; later statuses are reached through the transition endpoints
+	if req.Status == "" {
+		req.Status = matchstate.Scheduled
 	}
-	if !validStatuses[req.Status] {
+	if !matchstate.InitialStatuses[req.Status] {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Error:   "invalid_status",
-			Message: "Status must be 'scheduled', 'ongoing', 'completed', or 'cancelled'",
+			Message: "A new match must be 'scheduled' or 'postponed'",
+		})
+	}
+
+	// Results are recorded when the match is completed
+	if req.ResultTeamAScore != nil || req.ResultTeamBScore != nil || req.WinnerTeamID != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Scores and winner are recorded through the complete endpoint",
+		})
+	}
+
+	// Validate series length
+	if req.BestOf != 0 && !series.ValidBestOf[req.BestOf] {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_best_of",
+			Message: "best_of must be 1, 2, 3, 5, or 7",
 		})
 	}
 
@@ -89,36 +105,15 @@ func CreateMatch(c *fiber.Ctx) error {
 
 	// Create match model
 	match := model.Match{
-		TournamentID:     tournamentObjID,
-		TeamAID:          teamAObjID,
-		TeamBID:          teamBObjID,
-		MatchDate:        req.MatchDate,
-		MatchTime:        req.MatchTime,
-		Location:         req.Location,
-		Round:            req.Round,
-		ResultTeamAScore: req.ResultTeamAScore,
-		ResultTeamBScore: req.ResultTeamBScore,
-		Status:           req.Status,
-	}
-
-	// Handle winner team ID if provided
-	if req.WinnerTeamID != "" {
-		winnerObjID, err := primitive.ObjectIDFromHex(req.WinnerTeamID)
-		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Error:   "invalid_id",
-				Message: "Invalid winner_team_id format",
-			})
-		}
-		match.WinnerTeamID = &winnerObjID
-
-		// Validate winner is either team A or team B
-		if req.WinnerTeamID != req.TeamAID && req.WinnerTeamID != req.TeamBID {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Error:   "validation_error",
-				Message: "Winner team must be either team A or team B",
-			})
-		}
+		TournamentID: tournamentObjID,
+		TeamAID:      teamAObjID,
+		TeamBID:      teamBObjID,
+		MatchDate:    req.MatchDate,
+		MatchTime:    req.MatchTime,
+		Location:     req.Location,
+		Round:        req.Round,
+		BestOf:       req.BestOf,
+		Status:       req.Status,
 	}
 
 	insertedID, err := repository.CreateMatch(c.Context(), match)
@@ -137,13 +132,14 @@ func CreateMatch(c *fiber.Ctx) error {
 
 // GetAllMatches godoc
 // @Summary Get All Matches
-// @Description Mendapatkan daftar semua pertandingan, bisa difilter berdasarkan tournament_id
+// @Description Mendapatkan daftar semua pertandingan, bisa difilter berdasarkan tournament_id. Staff yang role-nya terbatas pada satu turnamen wajib mengisi tournament_id
 // @Tags Matches
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param tournament_id query string false "Filter pertandingan berdasarkan ID turnamen"
 // @Success 200 {array} model.MatchWithDetails
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /api/admin/matches [get]
 func GetAllMatches(c *fiber.Ctx) error {
@@ -170,6 +166,7 @@ func GetAllMatches(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Match ID"
 // @Success 200 {object} model.Match
+// @Header 200 {string} ETag "Versi match untuk header If-Match"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Router /api/admin/matches/{id} [get]
@@ -191,21 +188,27 @@ func GetMatchByID(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, match.Version)
 	return c.Status(fiber.StatusOK).JSON(match)
 }
 
 // UpdateMatch godoc
 // @Summary Update Match
-// @Description Memperbarui detail pertandingan termasuk input skor
+// @Description Memperbarui detail pertandingan termasuk input skor. Match tidak dapat dipindahkan ke turnamen lain, status hanya dapat diubah melalui endpoint transisi, dan winner hanya dapat dikoreksi pada match yang sudah completed atau forfeited
 // @Tags Matches
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Match ID"
 // @Param request body model.MatchRequest true "Match data"
+// @Param If-Match header string true "ETag dari GET data yang akan diubah"
 // @Success 200 {object} model.MatchResponse
+// @Header 200 {string} ETag "Versi match setelah diupdate"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse "Data sudah diubah oleh request lain"
+// @Failure 428 {object} model.ErrorResponse "Header If-Match tidak dikirim"
 // @Router /api/admin/matches/{id} [put]
 func UpdateMatch(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -281,10 +284,16 @@ func UpdateMatch(c *fiber.Ctx) error {
 		update["winner_team_id"] = &winnerObjID
 	}
 
+	if req.BestOf != 0 {
+		if !series.ValidBestOf[req.BestOf] {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid_best_of", Message: "best_of must be 1, 2, 3, 5, or 7"})
+		}
+		update["best_of"] = req.BestOf
+	}
+
 	if req.Status != "" {
-		validStatuses := map[string]bool{"scheduled": true, "ongoing": true, "completed": true, "cancelled": true}
-		if !validStatuses[req.Status] {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid_status", Message: "Status must be 'scheduled', 'ongoing', 'completed', or 'cancelled'"})
+		if !matchstate.Valid(req.Status) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid_status", Message: "Status must be 'scheduled', 'checked_in', 'ongoing', 'completed', 'postponed', 'cancelled', or 'forfeited'"})
 		}
 		update["status"] = req.Status
 	}
@@ -293,22 +302,30 @@ func UpdateMatch(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "bad_request", Message: "No fields to update"})
 	}
 
-	_, err := repository.UpdateMatch(c.Context(), id, update)
+	version, err := ifMatchVersion(c)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
-			Error:   "update_failed",
-			Message: fmt.Sprintf("Error updating match %s: %v", id, err),
-		})
+		return versionError(c, err)
+	}
+
+	_, err = repository.UpdateMatch(c.Context(), id, update, version)
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return versionError(c, err)
+	}
+	if err != nil {
+		return matchStateError(c, err)
 	}
 
+	setETag(c, version+1)
 	return c.Status(fiber.StatusOK).JSON(model.MatchResponse{
 		Message: "Match updated successfully",
+		MatchID: id,
+		Version: version + 1,
 	})
 }
 
 // DeleteMatch godoc
 // @Summary Delete Match
-// @Description Menghapus pertandingan dari database
+// @Description Memindahkan pertandingan ke trash; dapat dipulihkan atau dihapus permanen lewat /api/admin/trash
 // @Tags Matches
 // @Accept json
 // @Produce json
@@ -320,12 +337,14 @@ func UpdateMatch(c *fiber.Ctx) error {
 func DeleteMatch(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	_, err := repository.DeleteMatch(c.Context(), id)
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	_, err := repository.DeleteMatch(c.Context(), id, actor)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
-			Error:   "not_found",
-			Message: fmt.Sprintf("Match dengan ID %s tidak ditemukan: %v", id, err),
-		})
+		return deleteError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.MatchResponse{
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"embeck/repository"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CheckInMatch godoc
+// @Summary Check In Match
+// @Description Menandai kedua team sudah hadir (scheduled → checked_in)
+// @Tags Match Status
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Success 200 {object} model.MatchTransitionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/check-in [post]
+func CheckInMatch(c *fiber.Ctx) error {
+	return transitionMatch(c, matchstate.CheckedIn, "Match checked in successfully")
+}
+
+// StartMatch godoc
+// @Summary Start Match
+// @Description Memulai pertandingan (checked_in → ongoing)
+// @Tags Match Status
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Success 200 {object} model.MatchTransitionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/start [post]
+func StartMatch(c *fiber.Ctx) error {
+	return transitionMatch(c, matchstate.Ongoing, "Match started successfully")
+}
+
+// CompleteMatch godoc
+// @Summary Complete Match
+// @Description Menyelesaikan pertandingan (ongoing → completed). Skor kedua team dan winner wajib ada dan harus konsisten; untuk match best-of-N yang memiliki game, skor diambil dari game jika tidak dikirim
+// @Tags Match Status
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param request body model.MatchTransitionRequest false "Final result"
+// @Success 200 {object} model.MatchTransitionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/complete [post]
+func CompleteMatch(c *fiber.Ctx) error {
+	return transitionMatch(c, matchstate.Completed, "Match completed successfully")
+}
+
+// PostponeMatch godoc
+// @Summary Postpone Match
+// @Description Menunda pertandingan (scheduled/checked_in/ongoing → postponed). Alasan wajib diisi
+// @Tags Match Status
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param request body model.MatchTransitionRequest true "Postpone reason"
+// @Success 200 {object} model.MatchTransitionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/postpone [post]
+func PostponeMatch(c *fiber.Ctx) error {
+	return transitionMatch(c, matchstate.Postponed, "Match postponed successfully")
+}
+
+// RescheduleMatch godoc
+// @Summary Reschedule Match
+// @Description Menjadwalkan ulang pertandingan yang ditunda (postponed → scheduled). match_date baru wajib diisi
+// @Tags Match Status
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param request body model.MatchTransitionRequest true "New schedule"
+// @Success 200 {object} model.MatchTransitionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/reschedule [post]
+func RescheduleMatch(c *fiber.Ctx) error {
+	return transitionMatch(c, matchstate.Scheduled, "Match rescheduled successfully")
+}
+
+// CancelMatch godoc
+// @Summary Cancel Match
+// @Description Membatalkan pertandingan yang belum selesai. Alasan wajib diisi
+// @Tags Match Status
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param request body model.MatchTransitionRequest true "Cancel reason"
+// @Success 200 {object} model.MatchTransitionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/cancel [post]
+func CancelMatch(c *fiber.Ctx) error {
+	return transitionMatch(c, matchstate.Cancelled, "Match cancelled successfully")
+}
+
+// ForfeitMatch godoc
+// @Summary Forfeit Match
+// @Description Mencatat forfeit atau walkover pada pertandingan yang belum selesai. Alasan dan forfeiting_team_id (atau winner_team_id) wajib diisi. Tanpa skor, team pemenang mendapat skor default (jumlah kemenangan yang dibutuhkan series, mis. 2-0 untuk BO3)
+// @Tags Match Status
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param request body model.MatchTransitionRequest true "Forfeit data"
+// @Success 200 {object} model.MatchTransitionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/forfeit [post]
+func ForfeitMatch(c *fiber.Ctx) error {
+	return transitionMatch(c, matchstate.Forfeited, "Match forfeited successfully")
+}
+
+// transitionMatch parses a transition request and moves the match to the given status
+func transitionMatch(c *fiber.Ctx, to, message string) error {
+	id := c.Params("id")
+	var req model.MatchTransitionRequest
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid request data",
+			})
+		}
+	}
+
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	transition := model.MatchTransition{
+		To:               to,
+		Reason:           req.Reason,
+		ChangedBy:        actor,
+		ResultTeamAScore: req.ResultTeamAScore,
+		ResultTeamBScore: req.ResultTeamBScore,
+		MatchDate:        req.MatchDate,
+		MatchTime:        req.MatchTime,
+	}
+	if req.WinnerTeamID != "" {
+		winnerObjID, err := primitive.ObjectIDFromHex(req.WinnerTeamID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid winner_team_id format",
+			})
+		}
+		transition.WinnerTeamID = &winnerObjID
+	}
+	if req.ForfeitingTeamID != "" {
+		forfeitingObjID, err := primitive.ObjectIDFromHex(req.ForfeitingTeamID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid forfeiting_team_id format",
+			})
+		}
+		transition.ForfeitingTeamID = &forfeitingObjID
+	}
+	if req.Outcome != "" {
+		if !matchstate.ManualOutcomes[req.Outcome] {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_outcome",
+				Message: "Outcome must be 'forfeit' or 'walkover'",
+			})
+		}
+		transition.Outcome = req.Outcome
+	}
+
+	match, err := repository.TransitionMatch(c.Context(), id, transition)
+	if err != nil {
+		return matchStateError(c, err)
+	}
+
+	history := match.StatusHistory[len(match.StatusHistory)-1]
+	return c.Status(fiber.StatusOK).JSON(model.MatchTransitionResponse{
+		Message: message,
+		MatchID: id,
+		From:    history.From,
+		Status:  match.Status,
+	})
+}
+
+// matchStateError maps repository errors of status changes to HTTP responses
+func matchStateError(c *fiber.Ctx, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "tidak ditemukan"):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	case strings.Contains(err.Error(), "tidak dapat diubah dari"),
+		strings.Contains(err.Error(), "sudah berubah"),
+		strings.Contains(err.Error(), "endpoint transisi"),
+		strings.Contains(err.Error(), "sudah dimulai"):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Error:   "invalid_transition",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+		Error:   "validation_error",
+		Message: err.Error(),
+	})
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"embeck/model"
 	"embeck/repository"
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
@@ -38,6 +39,7 @@ func GetAllPlayers(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Player ID" example("64f123abc456def789012345")
 // @Success 200 {object} model.Player "Detail pemain"
+// @Header 200 {string} ETag "Versi player untuk header If-Match"
 // @Failure 400 {object} map[string]interface{} "ID tidak valid"
 // @Failure 404 {object} map[string]interface{} "Player tidak ditemukan"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -58,6 +60,7 @@ func GetPlayerByID(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, player.Version)
 	return c.Status(fiber.StatusOK).JSON(player)
 }
 
@@ -122,9 +125,13 @@ func CreatePlayer(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Player ID" example("64f123abc456def789012345")
 // @Param request body model.PlayerRequest true "Player data"
+// @Param If-Match header string true "ETag dari GET data yang akan diubah"
 // @Success 200 {object} model.PlayerResponse "Player berhasil diupdate"
+// @Header 200 {string} ETag "Versi player setelah diupdate"
 // @Failure 400 {object} model.ErrorResponse "Request data tidak valid"
 // @Failure 404 {object} model.ErrorResponse "Player tidak ditemukan"
+// @Failure 412 {object} model.ErrorResponse "Data sudah diubah oleh request lain"
+// @Failure 428 {object} model.ErrorResponse "Header If-Match tidak dikirim"
 // @Router /api/admin/players/{id} [put]
 func UpdatePlayer(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -152,7 +159,15 @@ func UpdatePlayer(c *fiber.Ctx) error {
 		update.Status = req.Status
 	}
 
-	_, err := repository.UpdatePlayer(c.Context(), id, update)
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return versionError(c, err)
+	}
+
+	_, err = repository.UpdatePlayer(c.Context(), id, update, version)
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return versionError(c, err)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 			Error:   "update_failed",
@@ -160,14 +175,16 @@ func UpdatePlayer(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, version+1)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Player updated successfully",
+		"version": version + 1,
 	})
 }
 
 // DeletePlayer godoc
 // @Summary Delete Player
-// @Description Menghapus pemain dari database
+// @Description Memindahkan pemain ke trash; dapat dipulihkan atau dihapus permanen lewat /api/admin/trash
 // @Tags Players
 // @Accept json
 // @Produce json
@@ -181,11 +198,14 @@ func UpdatePlayer(c *fiber.Ctx) error {
 func DeletePlayer(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	_, err := repository.DeletePlayer(c.Context(), id)
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	_, err := repository.DeletePlayer(c.Context(), id, actor)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": fmt.Sprintf("Player dengan ID %s tidak ditemukan: %v", id, err),
-		})
+		return deleteError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
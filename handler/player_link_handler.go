@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RequestPlayerLink godoc
+// @Summary Request Player Link
+// @Description Mengajukan permintaan untuk menghubungkan akun user dengan data player berdasarkan ML ID. Link aktif setelah diverifikasi admin
+// @Tags Player Links
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.PlayerLinkRequest true "Player ML ID"
+// @Success 201 {object} model.PlayerLinkResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/me/player-link [post]
+func RequestPlayerLink(c *fiber.Ctx) error {
+	var req model.PlayerLinkRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	if strings.TrimSpace(req.MLID) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "missing_fields",
+			Message: "ml_id is required",
+		})
+	}
+
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	insertedID, err := repository.RequestPlayerLink(c.Context(), userObjID, strings.TrimSpace(req.MLID))
+	if err != nil {
+		return playerLinkError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.PlayerLinkResponse{
+		Message: "Player link requested successfully",
+		LinkID:  insertedID.(primitive.ObjectID).Hex(),
+		Status:  model.PlayerLinkPending,
+	})
+}
+
+// GetMyPlayerLinks godoc
+// @Summary Get My Player Links
+// @Description Mendapatkan riwayat permintaan link player milik user yang login, termasuk status dan alasan penolakan
+// @Tags Player Links
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.PlayerLink
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/me/player-link [get]
+func GetMyPlayerLinks(c *fiber.Ctx) error {
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	links, err := repository.GetUserPlayerLinks(c.Context(), userObjID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve player links",
+		})
+	}
+	if links == nil {
+		links = []model.PlayerLink{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(links)
+}
+
+// GetPlayerLinks godoc
+// @Summary Get Player Links
+// @Description Mendapatkan antrean permintaan link user ke player, bisa difilter berdasarkan status (pending, verified, rejected)
+// @Tags Player Links
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter status link"
+// @Success 200 {array} model.PlayerLink
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/player-links [get]
+func GetPlayerLinks(c *fiber.Ctx) error {
+	status := c.Query("status")
+
+	validStatuses := map[string]bool{
+		"":                       true,
+		model.PlayerLinkPending:  true,
+		model.PlayerLinkVerified: true,
+		model.PlayerLinkRejected: true,
+	}
+	if !validStatuses[status] {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be 'pending', 'verified', or 'rejected'",
+		})
+	}
+
+	links, err := repository.GetPlayerLinks(c.Context(), status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve player links",
+		})
+	}
+	if links == nil {
+		links = []model.PlayerLink{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(links)
+}
+
+// VerifyPlayerLink godoc
+// @Summary Verify Player Link
+// @Description Memverifikasi permintaan link sehingga akun user terhubung dengan player
+// @Tags Player Links
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Player Link ID"
+// @Success 200 {object} model.PlayerLinkResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/player-links/{id}/verify [post]
+func VerifyPlayerLink(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	reviewer, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	link, err := repository.VerifyPlayerLink(c.Context(), id, reviewer)
+	if err != nil {
+		return playerLinkError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.PlayerLinkResponse{
+		Message: "Player link verified successfully",
+		LinkID:  id,
+		Status:  link.Status,
+	})
+}
+
+// RejectPlayerLink godoc
+// @Summary Reject Player Link
+// @Description Menolak permintaan link dengan alasan yang dapat dilihat oleh user
+// @Tags Player Links
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Player Link ID"
+// @Param request body model.RejectPlayerLinkRequest true "Rejection reason"
+// @Success 200 {object} model.PlayerLinkResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/player-links/{id}/reject [post]
+func RejectPlayerLink(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req model.RejectPlayerLinkRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "missing_fields",
+			Message: "reason is required",
+		})
+	}
+
+	reviewer, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	link, err := repository.RejectPlayerLink(c.Context(), id, reviewer, req.Reason)
+	if err != nil {
+		return playerLinkError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.PlayerLinkResponse{
+		Message: "Player link rejected successfully",
+		LinkID:  id,
+		Status:  link.Status,
+	})
+}
+
+// UnlinkPlayer godoc
+// @Summary Unlink Player
+// @Description Melepas hubungan akun user dengan player
+// @Tags Player Links
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} model.PlayerLinkResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/admin/users/{id}/player-link [delete]
+func UnlinkPlayer(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := repository.UnlinkPlayer(c.Context(), id); err != nil {
+		return playerLinkError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.PlayerLinkResponse{
+		Message: "Player unlinked successfully",
+	})
+}
+
+// playerLinkError maps repository errors of player links to HTTP responses
+func playerLinkError(c *fiber.Ctx, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "tidak ditemukan"):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Error: "not_found", Message: err.Error()})
+	case strings.Contains(err.Error(), "invalid"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid_id", Message: err.Error()})
+	case strings.Contains(err.Error(), "sudah"):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Error: "conflict", Message: err.Error()})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "validation_error", Message: err.Error()})
+}
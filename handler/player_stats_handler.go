@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/pkg/playerstats"
+	"embeck/repository"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SubmitGameStats godoc
+// @Summary Submit Game Player Stats
+// @Description Mencatat stat per player (kill, death, assist, gold, hero damage, turret damage, MVP) untuk satu game. Stat yang dikirim menggantikan stat game tersebut sebelumnya
+// @Tags Matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param gameId path string true "Game ID"
+// @Param request body model.GameStatsRequest true "Player stat lines"
+// @Success 200 {object} model.MatchGameResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/admin/matches/{id}/games/{gameId}/stats [put]
+func SubmitGameStats(c *fiber.Ctx) error {
+	id := c.Params("id")
+	gameID := c.Params("gameId")
+	var req model.GameStatsRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	lines := make([]model.PlayerGameStat, 0, len(req.Stats))
+	for i, stat := range req.Stats {
+		playerObjID, err := primitive.ObjectIDFromHex(stat.PlayerID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: fmt.Sprintf("Invalid player_id format on stat line %d", i+1),
+			})
+		}
+		lines = append(lines, model.PlayerGameStat{
+			PlayerID:     playerObjID,
+			Hero:         stat.Hero,
+			Kills:        stat.Kills,
+			Deaths:       stat.Deaths,
+			Assists:      stat.Assists,
+			Gold:         stat.Gold,
+			HeroDamage:   stat.HeroDamage,
+			TurretDamage: stat.TurretDamage,
+			MVP:          stat.MVP,
+		})
+	}
+
+	if err := repository.ReplaceGameStats(c.Context(), id, gameID, lines); err != nil {
+		return matchGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.MatchGameResponse{
+		Message: "Player stats saved successfully",
+		MatchID: id,
+		GameID:  gameID,
+	})
+}
+
+// GetPlayerStats godoc
+// @Summary Get Player Stats (public)
+// @Description Mendapatkan statistik karier dan per turnamen seorang player (total, rata-rata, KDA, win rate, jumlah MVP). Game yang di-void tidak dihitung
+// @Tags Players (Public)
+// @Produce json
+// @Param id path string true "Player ID"
+// @Success 200 {object} model.PlayerStatsResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/players/{id}/stats [get]
+func GetPlayerStats(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	player, err := repository.GetPlayerByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid player ID format",
+		})
+	}
+	if player == nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Player not found",
+		})
+	}
+
+	tournaments, err := repository.GetPlayerTournamentStats(c.Context(), player.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve player stats",
+		})
+	}
+	if tournaments == nil {
+		tournaments = []model.TournamentPlayerStats{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.PlayerStatsResponse{
+		PlayerID:    player.ID,
+		MLNickname:  player.MLNickname,
+		Career:      playerstats.Career(tournaments),
+		Tournaments: tournaments,
+	})
+}
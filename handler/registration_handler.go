@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetMyRegistrations godoc
+// @Summary Get My Registrations
+// @Description Mendapatkan daftar pendaftaran turnamen yang diajukan oleh user yang login, termasuk status dan alasan penolakan
+// @Tags Registrations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.TournamentRegistration
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/me/registrations [get]
+func GetMyRegistrations(c *fiber.Ctx) error {
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	registrations, err := repository.GetUserRegistrations(c.Context(), userObjID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve registrations",
+		})
+	}
+	if registrations == nil {
+		registrations = []model.TournamentRegistration{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(registrations)
+}
+
+// GetTournamentRegistrations godoc
+// @Summary Get Tournament Registrations
+// @Description Mendapatkan antrean pendaftaran team untuk turnamen, bisa difilter berdasarkan status (pending, approved, rejected)
+// @Tags Registrations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID"
+// @Param status query string false "Filter status pendaftaran"
+// @Success 200 {array} model.TournamentRegistration
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/tournaments/{id}/registrations [get]
+func GetTournamentRegistrations(c *fiber.Ctx) error {
+	id := c.Params("id")
+	status := c.Query("status")
+
+	tournamentObjID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	validStatuses := map[string]bool{
+		"":                         true,
+		model.RegistrationPending:  true,
+		model.RegistrationApproved: true,
+		model.RegistrationRejected: true,
+	}
+	if !validStatuses[status] {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be 'pending', 'approved', or 'rejected'",
+		})
+	}
+
+	registrations, err := repository.GetTournamentRegistrations(c.Context(), tournamentObjID, status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve registrations",
+		})
+	}
+	if registrations == nil {
+		registrations = []model.TournamentRegistration{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(registrations)
+}
+
+// ApproveRegistration godoc
+// @Summary Approve Registration
+// @Description Menyetujui pendaftaran team; team otomatis ditambahkan ke teams_participating selama kuota masih tersedia
+// @Tags Registrations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Registration ID"
+// @Success 200 {object} model.RegistrationResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/registrations/{id}/approve [post]
+func ApproveRegistration(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	reviewer, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	registration, err := repository.ApproveRegistration(c.Context(), id, reviewer)
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.RegistrationResponse{
+		Message:        "Registration approved successfully",
+		RegistrationID: id,
+		Status:         registration.Status,
+	})
+}
+
+// RejectRegistration godoc
+// @Summary Reject Registration
+// @Description Menolak pendaftaran team dengan alasan yang dapat dilihat oleh pendaftar
+// @Tags Registrations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Registration ID"
+// @Param request body model.RejectRegistrationRequest true "Rejection reason"
+// @Success 200 {object} model.RegistrationResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/registrations/{id}/reject [post]
+func RejectRegistration(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req model.RejectRegistrationRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "missing_fields",
+			Message: "reason is required",
+		})
+	}
+
+	reviewer, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	registration, err := repository.RejectRegistration(c.Context(), id, reviewer, req.Reason)
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.RegistrationResponse{
+		Message:        "Registration rejected successfully",
+		RegistrationID: id,
+		Status:         registration.Status,
+	})
+}
+
+// validateRegistrationWindow checks the registration window of a tournament request
+func validateRegistrationWindow(window *model.RegistrationWindow) error {
+	if window == nil {
+		return nil
+	}
+	if window.OpensAt.IsZero() || window.ClosesAt.IsZero() {
+		return fmt.Errorf("registration opens_at and closes_at are required")
+	}
+	if !window.ClosesAt.After(window.OpensAt) {
+		return fmt.Errorf("registration closes_at must be after opens_at")
+	}
+	if window.MaxTeams < 0 || window.MinRosterSize < 0 {
+		return fmt.Errorf("max_teams and min_roster_size cannot be negative")
+	}
+	return nil
+}
+
+// registrationError maps repository errors of registrations to HTTP responses
+func registrationError(c *fiber.Ctx, err error) error {
+	switch {
+	case err == mongo.ErrNoDocuments, strings.Contains(err.Error(), "tidak ditemukan"):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Error: "not_found", Message: err.Error()})
+	case strings.Contains(err.Error(), "invalid"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid_id", Message: err.Error()})
+	case strings.Contains(err.Error(), "sudah"), strings.Contains(err.Error(), "belum dibuka"), strings.Contains(err.Error(), "tidak membuka"):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Error: "conflict", Message: err.Error()})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "validation_error", Message: err.Error()})
+}
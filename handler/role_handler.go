@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/pkg/permission"
+	"embeck/repository"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetRoles godoc
+// @Summary Get Staff Roles
+// @Description Mendapatkan daftar role staff yang dapat diberikan ke user beserta permission-nya. Role admin memiliki semua permission
+// @Tags Roles (Admin)
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.RoleInfo
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /api/admin/roles [get]
+func GetRoles(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(permission.Roles())
+}
+
+// GetRoleAssignments godoc
+// @Summary Get Role Assignments
+// @Description Mendapatkan daftar role staff yang diberikan ke user, terbaru lebih dulu
+// @Tags Roles (Admin)
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Filter user ID"
+// @Param tournament_id query string false "Filter tournament ID"
+// @Success 200 {array} model.RoleAssignment
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/role-assignments [get]
+func GetRoleAssignments(c *fiber.Ctx) error {
+	var userID, tournamentID *primitive.ObjectID
+	for _, filter := range []struct {
+		param  string
+		target **primitive.ObjectID
+	}{
+		{"user_id", &userID},
+		{"tournament_id", &tournamentID},
+	} {
+		value := c.Query(filter.param)
+		if value == "" {
+			continue
+		}
+		objID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid " + filter.param + " format",
+			})
+		}
+		*filter.target = &objID
+	}
+
+	assignments, err := repository.GetRoleAssignments(c.Context(), userID, tournamentID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve role assignments",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(assignments)
+}
+
+// CreateRoleAssignment godoc
+// @Summary Assign Staff Role
+// @Description Memberikan role staff ke user, untuk semua tournament atau hanya satu tournament jika tournament_id diisi. Berlaku langsung tanpa login ulang
+// @Tags Roles (Admin)
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.RoleAssignmentRequest true "User, role, dan tournament opsional"
+// @Success 201 {object} model.RoleAssignment
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse "User atau tournament tidak ditemukan"
+// @Failure 409 {object} model.ErrorResponse "User sudah memiliki role ini"
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/role-assignments [post]
+func CreateRoleAssignment(c *fiber.Ctx) error {
+	var req model.RoleAssignmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	if !permission.Valid(req.Role) {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_role",
+			Message: "Unknown role, see /api/admin/roles",
+		})
+	}
+
+	userID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid user_id format",
+		})
+	}
+
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	assignment := model.RoleAssignment{
+		UserID:    userID,
+		Role:      req.Role,
+		GrantedBy: actor,
+	}
+	if req.TournamentID != "" {
+		tournamentID, err := primitive.ObjectIDFromHex(req.TournamentID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid tournament_id format",
+			})
+		}
+		assignment.TournamentID = &tournamentID
+	}
+
+	created, err := repository.CreateRoleAssignment(c.Context(), assignment)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateRoleAssignment):
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Error:   "duplicate_role",
+				Message: err.Error(),
+			})
+		case strings.Contains(err.Error(), "tidak ditemukan"):
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to assign role",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// DeleteRoleAssignment godoc
+// @Summary Remove Staff Role
+// @Description Mencabut role staff dari user. Berlaku langsung untuk request berikutnya
+// @Tags Roles (Admin)
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role assignment ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/role-assignments/{id} [delete]
+func DeleteRoleAssignment(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := repository.DeleteRoleAssignment(c.Context(), id); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "invalid role assignment ID"):
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: err.Error(),
+			})
+		case strings.Contains(err.Error(), "tidak ditemukan"):
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to remove role assignment",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":            "Role assignment removed",
+		"role_assignment_id": id,
+	})
+}
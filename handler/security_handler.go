@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// adminTargetUser loads the user in the :id path parameter. It responds itself and returns
+// nil when that fails
+func adminTargetUser(c *fiber.Ctx) (*model.User, error) {
+	user, err := repository.GetUserByID(c.Context(), c.Params("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid user ID format") {
+			return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid user ID format",
+			})
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get user",
+		})
+	}
+	if user == nil {
+		return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	return user, nil
+}
+
+// UnlockUser godoc
+// @Summary Unlock User Login
+// @Description Membuka kunci login user yang terkunci karena terlalu banyak percobaan gagal dan menghapus jeda login-nya (Admin only)
+// @Tags Users Management
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID" example("64f123abc456def789012345")
+// @Success 200 {object} map[string]interface{} "Login user berhasil dibuka"
+// @Failure 400 {object} map[string]interface{} "ID tidak valid"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - Token tidak valid"
+// @Failure 403 {object} map[string]interface{} "Forbidden - Hanya admin yang dapat mengakses"
+// @Failure 404 {object} map[string]interface{} "User tidak ditemukan"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/users/{id}/unlock [post]
+func UnlockUser(c *fiber.Ctx) error {
+	user, err := adminTargetUser(c)
+	if user == nil {
+		return err
+	}
+
+	claims, ok := c.Locals("claims").(*model.TokenClaims)
+	if !ok || claims == nil {
+		return unauthorizedClaims(c)
+	}
+
+	if err := repository.UnlockAccount(c.Context(), *user, claims.Username); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to unlock user",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "User login unlocked",
+		"user_id": user.ID.Hex(),
+	})
+}
+
+// GetUserSecurityEvents godoc
+// @Summary Get User Security Events
+// @Description Mendapatkan riwayat kejadian keamanan user (login gagal, akun atau IP terkunci, pembukaan kunci, pemakaian ulang refresh token), terbaru lebih dulu (Admin only)
+// @Tags Users Management
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID" example("64f123abc456def789012345")
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah per halaman (default 50, maksimal 200)"
+// @Success 200 {object} model.SecurityEventList
+// @Failure 400 {object} map[string]interface{} "ID tidak valid"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - Token tidak valid"
+// @Failure 403 {object} map[string]interface{} "Forbidden - Hanya admin yang dapat mengakses"
+// @Failure 404 {object} map[string]interface{} "User tidak ditemukan"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/users/{id}/security-events [get]
+func GetUserSecurityEvents(c *fiber.Ctx) error {
+	user, err := adminTargetUser(c)
+	if user == nil {
+		return err
+	}
+
+	page := c.QueryInt("page", 1)
+	if page <= 0 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	events, err := repository.GetSecurityEvents(c.Context(), user.ID, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve security events",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(events)
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/pkg/swiss"
+	"embeck/repository"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GenerateSwissRound godoc
+// @Summary Generate Next Swiss Round
+// @Description Memasangkan team dengan rekor yang sama untuk round Swiss berikutnya, menghindari rematch dan memberikan bye bila jumlah team ganjil
+// @Tags Tournament Management (Admin)
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID"
+// @Param request body model.SwissRoundRequest true "Swiss round options (total_rounds dan seeding hanya dipakai pada round pertama)"
+// @Success 201 {object} model.SwissRoundResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/tournaments/{id}/swiss/next-round [post]
+func GenerateSwissRound(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req model.SwissRoundRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	// The first round fixes the seeding and the number of rounds
+	stage := tournament.Swiss
+	if stage == nil {
+		seeded, err := seedTeams(activeTeams(*tournament), req.Seeding, req.TeamIDs)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_seeding",
+				Message: err.Error(),
+			})
+		}
+		stage = &model.SwissStage{TotalRounds: req.TotalRounds, Seeds: seeded}
+		if stage.TotalRounds <= 0 {
+			stage.TotalRounds = swiss.Rounds(len(seeded))
+		}
+	}
+
+	if stage.CurrentRound >= stage.TotalRounds {
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Error:   "swiss_finished",
+			Message: fmt.Sprintf("All %d Swiss rounds have already been generated", stage.TotalRounds),
+		})
+	}
+
+	matches, err := repository.GetSwissMatches(c.Context(), tournament.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve Swiss matches",
+		})
+	}
+
+	// Every match of the current round needs a result before pairing the next one
+	for _, match := range matches {
+		if match.SwissRound != stage.CurrentRound || match.Status == "cancelled" {
+			continue
+		}
+		if _, ok := swiss.Winner(match); !ok && !match.IsBye {
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Error:   "round_in_progress",
+				Message: fmt.Sprintf("Swiss round %d still has matches without a result", stage.CurrentRound),
+			})
+		}
+	}
+
+	// Disqualified teams keep their record for Buchholz but are no longer paired
+	active := make(map[primitive.ObjectID]bool, len(tournament.TeamsParticipating))
+	for _, teamID := range activeTeams(*tournament) {
+		active[teamID] = true
+	}
+	pairable := make([]primitive.ObjectID, 0, len(stage.Seeds))
+	for _, teamID := range stage.Seeds {
+		if active[teamID] {
+			pairable = append(pairable, teamID)
+		}
+	}
+
+	records := swiss.Records(stage.Seeds, matches)
+	pairs, bye, err := swiss.Pair(pairable, records)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	}
+
+	matchDate := req.MatchDate
+	if matchDate.IsZero() {
+		matchDate = tournament.StartDate
+	}
+	matchTime := req.MatchTime
+	if matchTime == "" {
+		matchTime = "TBD"
+	}
+
+	stage.CurrentRound++
+	round := fmt.Sprintf("Swiss Round %d", stage.CurrentRound)
+
+	var newMatches []model.Match
+	for _, pair := range pairs {
+		newMatches = append(newMatches, model.Match{
+			TournamentID: tournament.ID,
+			TeamAID:      pair[0],
+			TeamBID:      pair[1],
+			MatchDate:    matchDate,
+			MatchTime:    matchTime,
+			Location:     req.Location,
+			Round:        round,
+			SwissRound:   stage.CurrentRound,
+			Status:       "scheduled",
+		})
+	}
+	if !bye.IsZero() {
+		winner := bye
+		newMatches = append(newMatches, model.Match{
+			TournamentID: tournament.ID,
+			TeamAID:      bye,
+			MatchDate:    matchDate,
+			MatchTime:    matchTime,
+			Round:        round,
+			SwissRound:   stage.CurrentRound,
+			IsBye:        true,
+			WinnerTeamID: &winner,
+			Status:       "completed",
+		})
+	}
+
+	insertedIDs, err := repository.CreateSwissRound(c.Context(), tournament.ID, *stage, newMatches)
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Error:   "db_conflict",
+			Message: fmt.Sprintf("Gagal membuat Swiss round: %v", err),
+		})
+	}
+
+	response := model.SwissRoundResponse{
+		Message:      "Swiss round generated successfully",
+		TournamentID: tournament.ID.Hex(),
+		Round:        stage.CurrentRound,
+		TotalRounds:  stage.TotalRounds,
+		MatchIDs:     hexIDs(insertedIDs),
+	}
+	if !bye.IsZero() {
+		response.ByeTeamID = bye.Hex()
+	}
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// GetSwissStandings godoc
+// @Summary Get Swiss Standings (public)
+// @Description Mendapatkan peringkat Swiss berdasarkan jumlah kemenangan, lalu Buchholz dan selisih game
+// @Tags Tournament Data (Public)
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} model.SwissStandingsResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/tournaments/{id}/swiss/standings [get]
+func GetSwissStandings(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	if tournament.Swiss == nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Tournament has no Swiss stage",
+		})
+	}
+
+	matches, err := repository.GetSwissMatches(c.Context(), tournament.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve Swiss matches",
+		})
+	}
+
+	teams, err := repository.GetTeamsBasicInfoByIDs(c.Context(), tournament.Swiss.Seeds)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve teams",
+		})
+	}
+
+	records := swiss.Records(tournament.Swiss.Seeds, matches)
+	ranked := swiss.Rank(tournament.Swiss.Seeds, records)
+
+	standings := make([]model.SwissStanding, len(ranked))
+	for i, teamID := range ranked {
+		record := records[teamID]
+		standings[i] = model.SwissStanding{
+			Rank:     i + 1,
+			TeamID:   teamID,
+			TeamName: teams[teamID].TeamName,
+			LogoURL:  teams[teamID].LogoURL,
+			Wins:     record.Wins,
+			Losses:   record.Losses,
+			Byes:     record.Byes,
+			Buchholz: swiss.Buchholz(teamID, records),
+			GameDiff: record.GamesWon - record.GamesLost,
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.SwissStandingsResponse{
+		TournamentID: tournament.ID.Hex(),
+		CurrentRound: tournament.Swiss.CurrentRound,
+		TotalRounds:  tournament.Swiss.TotalRounds,
+		Standings:    standings,
+	})
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"embeck/model"
+	"embeck/pkg/roster"
 	"embeck/repository"
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
@@ -39,6 +41,7 @@ func GetAllTeams(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Team ID"
 // @Success 200 {object} model.TeamWithDetails
+// @Header 200 {string} ETag "Versi team untuk header If-Match"
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /api/admin/teams/{id} [get]
@@ -58,12 +61,13 @@ func GetTeamByID(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, team.Version)
 	return c.Status(fiber.StatusOK).JSON(team)
 }
 
 // CreateTeam godoc
 // @Summary Create New Team
-// @Description Membuat tim baru dengan kapten dan anggota
+// @Description Membuat tim baru dengan kapten dan roster: 5 starter, maksimal 2 substitute dan 1 coach. Jika hanya members yang diisi, 5 member pertama menjadi starter
 // @Tags Teams
 // @Accept json
 // @Produce json
@@ -84,10 +88,10 @@ func CreateTeam(c *fiber.Ctx) error {
 	}
 
 	// Validation
-	if req.TeamName == "" || req.CaptainID == "" || len(req.Members) == 0 {
+	if req.TeamName == "" || req.CaptainID == "" || (len(req.Members) == 0 && len(req.Roster) == 0) {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Error:   "missing_fields",
-			Message: "team_name, captain_id, and members are required",
+			Message: "team_name, captain_id, and roster (or members) are required",
 		})
 	}
 
@@ -100,31 +104,20 @@ func CreateTeam(c *fiber.Ctx) error {
 		})
 	}
 
-	// Convert members string array to ObjectID array
-	var membersObjID []primitive.ObjectID
-	for _, memberID := range req.Members {
-		objID, err := primitive.ObjectIDFromHex(memberID)
-		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-				Error:   "invalid_id",
-				Message: fmt.Sprintf("Invalid member ID format: %s", memberID),
-			})
-		}
-		membersObjID = append(membersObjID, objID)
+	// Convert the roster, or the members list with the first five as starters
+	teamRoster, err := requestRoster(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: err.Error(),
+		})
 	}
 
-	// Validate captain is included in members
-	captainInMembers := false
-	for _, memberID := range membersObjID {
-		if memberID == captainObjID {
-			captainInMembers = true
-			break
-		}
-	}
-	if !captainInMembers {
+	// Validate roster roles, sizes and that the captain plays in the team
+	if err := roster.Validate(teamRoster, captainObjID, true); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Error:   "validation_error",
-			Message: "Captain must be included in members list",
+			Message: err.Error(),
 		})
 	}
 
@@ -132,7 +125,8 @@ func CreateTeam(c *fiber.Ctx) error {
 	team := model.Team{
 		TeamName:  req.TeamName,
 		CaptainID: captainObjID,
-		Members:   membersObjID,
+		Members:   roster.Players(teamRoster),
+		Roster:    teamRoster,
 		LogoURL:   req.LogoURL,
 	}
 
@@ -152,16 +146,20 @@ func CreateTeam(c *fiber.Ctx) error {
 
 // UpdateTeam godoc
 // @Summary Update Team
-// @Description Memperbarui detail tim
+// @Description Memperbarui detail tim. Roster (atau members) yang dikirim harus lengkap: 5 starter, maksimal 2 substitute dan 1 coach
 // @Tags Teams
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Team ID"
 // @Param request body model.TeamRequest true "Team data"
+// @Param If-Match header string true "ETag dari GET data yang akan diubah"
 // @Success 200 {object} model.TeamResponse
+// @Header 200 {string} ETag "Versi team setelah diupdate"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse "Data sudah diubah oleh request lain"
+// @Failure 428 {object} model.ErrorResponse "Header If-Match tidak dikirim"
 // @Router /api/admin/teams/{id} [put]
 func UpdateTeam(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -192,43 +190,31 @@ func UpdateTeam(c *fiber.Ctx) error {
 		update.CaptainID = captainObjID
 	}
 
-	if len(req.Members) > 0 {
-		var membersObjID []primitive.ObjectID
-		for _, memberID := range req.Members {
-			objID, err := primitive.ObjectIDFromHex(memberID)
-			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-					Error:   "invalid_id",
-					Message: fmt.Sprintf("Invalid member ID format: %s", memberID),
-				})
-			}
-			membersObjID = append(membersObjID, objID)
-		}
-		update.Members = membersObjID
-
-		// If both captain and members are provided, validate captain is in members
-		if req.CaptainID != "" {
-			captainInMembers := false
-			for _, memberID := range membersObjID {
-				if memberID == update.CaptainID {
-					captainInMembers = true
-					break
-				}
-			}
-			if !captainInMembers {
-				return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-					Error:   "validation_error",
-					Message: "Captain must be included in members list",
-				})
-			}
+	if len(req.Members) > 0 || len(req.Roster) > 0 {
+		teamRoster, err := requestRoster(req)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_id",
+				Message: err.Error(),
+			})
 		}
+		update.Roster = teamRoster
+		update.Members = roster.Players(teamRoster)
 	}
 
 	if req.LogoURL != "" {
 		update.LogoURL = req.LogoURL
 	}
 
-	_, err := repository.UpdateTeam(c.Context(), id, update)
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return versionError(c, err)
+	}
+
+	_, err = repository.UpdateTeam(c.Context(), id, update, version)
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return versionError(c, err)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 			Error:   "update_failed",
@@ -236,14 +222,16 @@ func UpdateTeam(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, version+1)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Team updated successfully",
+		"version": version + 1,
 	})
 }
 
 // DeleteTeam godoc
 // @Summary Delete Team
-// @Description Menghapus tim dari database
+// @Description Memindahkan tim ke trash; dapat dipulihkan atau dihapus permanen lewat /api/admin/trash
 // @Tags Teams
 // @Accept json
 // @Produce json
@@ -256,14 +244,48 @@ func UpdateTeam(c *fiber.Ctx) error {
 func DeleteTeam(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	_, err := repository.DeleteTeam(c.Context(), id)
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	_, err := repository.DeleteTeam(c.Context(), id, actor)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": fmt.Sprintf("Team dengan ID %s tidak ditemukan: %v", id, err),
-		})
+		return deleteError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Team deleted successfully",
 	})
 }
+
+// requestRoster converts the roster of a team request. Requests with only a members list
+// get the first five members as starters and the rest as substitutes
+func requestRoster(req model.TeamRequest) ([]model.TeamMember, error) {
+	if len(req.Roster) > 0 {
+		return parseTeamRoster(req.Roster)
+	}
+
+	members := make([]primitive.ObjectID, 0, len(req.Members))
+	for _, memberID := range req.Members {
+		objID, err := primitive.ObjectIDFromHex(memberID)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid member ID format: %s", memberID)
+		}
+		members = append(members, objID)
+	}
+	return roster.FromMembers(members), nil
+}
+
+// parseTeamRoster converts the string IDs of roster entries into team members
+func parseTeamRoster(entries []model.TeamMemberRequest) ([]model.TeamMember, error) {
+	members := make([]model.TeamMember, 0, len(entries))
+	for _, entry := range entries {
+		objID, err := primitive.ObjectIDFromHex(entry.PlayerID)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid player_id format: %s", entry.PlayerID)
+		}
+		members = append(members, model.TeamMember{PlayerID: objID, Role: entry.Role})
+	}
+	return members, nil
+}
@@ -0,0 +1,463 @@
+package handler
+
+import (
+	"context"
+	"embeck/model"
+	"embeck/repository"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InviteToTeam godoc
+// @Summary Invite Player (captain)
+// @Description Mengundang player ke team berdasarkan ML ID atau user yang sudah terhubung dengan player, opsional dengan role (starter, substitute, coach). Undangan kedaluwarsa setelah TEAM_INVITATION_TTL
+// @Tags Team Invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body model.TeamInvitationRequest true "Player to invite (ml_id atau user_id)"
+// @Success 201 {object} model.TeamInvitationResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/invitations [post]
+func InviteToTeam(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+	var req model.TeamInvitationRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	req.MLID = strings.TrimSpace(req.MLID)
+	if (req.MLID == "") == (req.UserID == "") {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "missing_fields",
+			Message: "Exactly one of ml_id or user_id is required",
+		})
+	}
+
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	playerID, err := repository.ResolveInvitee(c.Context(), req.MLID, req.UserID)
+	if err != nil {
+		return teamInvitationError(c, err)
+	}
+
+	insertedID, err := repository.CreateTeamInvitation(c.Context(), model.TeamInvitation{
+		Kind:      model.InvitationKindInvite,
+		TeamID:    team.ID,
+		PlayerID:  playerID,
+		Role:      req.Role,
+		Message:   req.Message,
+		CreatedBy: userObjID,
+	})
+	if err != nil {
+		return teamInvitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.TeamInvitationResponse{
+		Message:      "Invitation sent successfully",
+		InvitationID: insertedID.(primitive.ObjectID).Hex(),
+		Status:       model.InvitationPending,
+	})
+}
+
+// GetTeamInvitations godoc
+// @Summary Get Team Invitations (captain)
+// @Description Mendapatkan undangan dan permintaan bergabung milik team, bisa difilter berdasarkan kind (invite, join_request) dan status
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param kind query string false "Filter kind"
+// @Param status query string false "Filter status"
+// @Success 200 {array} model.TeamInvitation
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/invitations [get]
+func GetTeamInvitations(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+	kind, status := c.Query("kind"), c.Query("status")
+
+	if invalid := validateInvitationFilter(kind, status); invalid != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(invalid)
+	}
+
+	invitations, err := repository.GetTeamInvitations(c.Context(), team.ID, kind, status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve invitations",
+		})
+	}
+	if invitations == nil {
+		invitations = []model.TeamInvitation{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(invitations)
+}
+
+// CancelTeamInvitation godoc
+// @Summary Cancel Invitation (captain)
+// @Description Menarik kembali undangan team yang belum dijawab
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param invitationId path string true "Invitation ID"
+// @Success 200 {object} model.TeamInvitationResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/invitations/{invitationId}/cancel [post]
+func CancelTeamInvitation(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+	invitationID := c.Params("invitationId")
+
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	invitation, err := repository.CancelInvitation(c.Context(), invitationID, team.ID, userObjID)
+	if err != nil {
+		return teamInvitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TeamInvitationResponse{
+		Message:      "Invitation cancelled successfully",
+		InvitationID: invitationID,
+		Status:       invitation.Status,
+	})
+}
+
+// AcceptJoinRequest godoc
+// @Summary Accept Join Request (captain)
+// @Description Menerima permintaan bergabung sehingga player langsung ditambahkan ke anggota team. Player hanya boleh menjadi anggota aktif satu team
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param invitationId path string true "Join request ID"
+// @Success 200 {object} model.TeamInvitationResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/join-requests/{invitationId}/accept [post]
+func AcceptJoinRequest(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+	invitationID := c.Params("invitationId")
+
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	invitation, err := repository.AcceptJoinRequest(c.Context(), invitationID, team.ID, userObjID)
+	if err != nil {
+		return teamInvitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TeamInvitationResponse{
+		Message:      "Join request accepted successfully",
+		InvitationID: invitationID,
+		Status:       invitation.Status,
+	})
+}
+
+// DeclineJoinRequest godoc
+// @Summary Decline Join Request (captain)
+// @Description Menolak permintaan bergabung dari player
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param invitationId path string true "Join request ID"
+// @Success 200 {object} model.TeamInvitationResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/captain/teams/{id}/join-requests/{invitationId}/decline [post]
+func DeclineJoinRequest(c *fiber.Ctx) error {
+	team := c.Locals("team").(*model.Team)
+	invitationID := c.Params("invitationId")
+
+	userObjID, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	invitation, err := repository.DeclineJoinRequest(c.Context(), invitationID, team.ID, userObjID)
+	if err != nil {
+		return teamInvitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TeamInvitationResponse{
+		Message:      "Join request declined successfully",
+		InvitationID: invitationID,
+		Status:       invitation.Status,
+	})
+}
+
+// RequestToJoinTeam godoc
+// @Summary Request To Join Team
+// @Description Player yang terhubung dengan akun mengajukan permintaan bergabung ke team. Permintaan kedaluwarsa setelah TEAM_INVITATION_TTL
+// @Tags Team Invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body model.JoinRequestRequest false "Optional message to the captain"
+// @Success 201 {object} model.TeamInvitationResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/teams/{id}/join-requests [post]
+func RequestToJoinTeam(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req model.JoinRequestRequest
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid request data",
+			})
+		}
+	}
+
+	teamObjID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid team ID format",
+		})
+	}
+
+	userObjID, playerID, err := claimsPlayerID(c)
+	if err != nil {
+		return unauthorizedClaims(c)
+	}
+	if playerID == nil {
+		return playerNotLinked(c)
+	}
+
+	insertedID, err := repository.CreateTeamInvitation(c.Context(), model.TeamInvitation{
+		Kind:      model.InvitationKindJoinRequest,
+		TeamID:    teamObjID,
+		PlayerID:  *playerID,
+		Message:   req.Message,
+		CreatedBy: userObjID,
+	})
+	if err != nil {
+		return teamInvitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.TeamInvitationResponse{
+		Message:      "Join request sent successfully",
+		InvitationID: insertedID.(primitive.ObjectID).Hex(),
+		Status:       model.InvitationPending,
+	})
+}
+
+// GetMyInvitations godoc
+// @Summary Get My Invitations
+// @Description Mendapatkan undangan team dan permintaan bergabung milik player yang terhubung dengan akun
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Param kind query string false "Filter kind"
+// @Param status query string false "Filter status"
+// @Success 200 {array} model.TeamInvitation
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/me/invitations [get]
+func GetMyInvitations(c *fiber.Ctx) error {
+	kind, status := c.Query("kind"), c.Query("status")
+
+	if invalid := validateInvitationFilter(kind, status); invalid != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(invalid)
+	}
+
+	_, playerID, err := claimsPlayerID(c)
+	if err != nil {
+		return unauthorizedClaims(c)
+	}
+	if playerID == nil {
+		return playerNotLinked(c)
+	}
+
+	invitations, err := repository.GetPlayerInvitations(c.Context(), *playerID, kind, status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve invitations",
+		})
+	}
+	if invitations == nil {
+		invitations = []model.TeamInvitation{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(invitations)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept Invitation
+// @Description Menerima undangan team sehingga player langsung ditambahkan ke anggota team. Player hanya boleh menjadi anggota aktif satu team
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} model.TeamInvitationResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/me/invitations/{id}/accept [post]
+func AcceptInvitation(c *fiber.Ctx) error {
+	return respondAsPlayer(c, repository.AcceptInvitation, "Invitation accepted successfully")
+}
+
+// DeclineInvitation godoc
+// @Summary Decline Invitation
+// @Description Menolak undangan team
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} model.TeamInvitationResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/me/invitations/{id}/decline [post]
+func DeclineInvitation(c *fiber.Ctx) error {
+	return respondAsPlayer(c, repository.DeclineInvitation, "Invitation declined successfully")
+}
+
+// CancelJoinRequest godoc
+// @Summary Cancel Join Request
+// @Description Menarik kembali permintaan bergabung yang belum dijawab captain
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Join request ID"
+// @Success 200 {object} model.TeamInvitationResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/me/join-requests/{id}/cancel [post]
+func CancelJoinRequest(c *fiber.Ctx) error {
+	return respondAsPlayer(c, repository.CancelJoinRequest, "Join request cancelled successfully")
+}
+
+// LeaveTeam godoc
+// @Summary Leave Team
+// @Description Keluar dari team saat ini. Captain harus menyerahkan posisi captain terlebih dahulu
+// @Tags Team Invitations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.TeamResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/me/team/leave [post]
+func LeaveTeam(c *fiber.Ctx) error {
+	_, playerID, err := claimsPlayerID(c)
+	if err != nil {
+		return unauthorizedClaims(c)
+	}
+	if playerID == nil {
+		return playerNotLinked(c)
+	}
+
+	team, err := repository.LeaveTeam(c.Context(), *playerID)
+	if err != nil {
+		return teamInvitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TeamResponse{
+		Message: "Left team successfully",
+		TeamID:  team.ID.Hex(),
+	})
+}
+
+// respondAsPlayer answers an invitation or join request on behalf of the player linked to the account
+func respondAsPlayer(c *fiber.Ctx, respond func(ctx context.Context, id string, playerID, responder primitive.ObjectID) (*model.TeamInvitation, error), message string) error {
+	id := c.Params("id")
+
+	userObjID, playerID, err := claimsPlayerID(c)
+	if err != nil {
+		return unauthorizedClaims(c)
+	}
+	if playerID == nil {
+		return playerNotLinked(c)
+	}
+
+	invitation, err := respond(c.Context(), id, *playerID, userObjID)
+	if err != nil {
+		return teamInvitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TeamInvitationResponse{
+		Message:      message,
+		InvitationID: id,
+		Status:       invitation.Status,
+	})
+}
+
+// validateInvitationFilter checks the kind and status query filters of invitation listings
+func validateInvitationFilter(kind, status string) *model.ErrorResponse {
+	validKinds := map[string]bool{
+		"":                              true,
+		model.InvitationKindInvite:      true,
+		model.InvitationKindJoinRequest: true,
+	}
+	if !validKinds[kind] {
+		return &model.ErrorResponse{
+			Error:   "invalid_kind",
+			Message: "Kind must be 'invite' or 'join_request'",
+		}
+	}
+
+	validStatuses := map[string]bool{
+		"":                        true,
+		model.InvitationPending:   true,
+		model.InvitationAccepted:  true,
+		model.InvitationDeclined:  true,
+		model.InvitationCancelled: true,
+		model.InvitationExpired:   true,
+	}
+	if !validStatuses[status] {
+		return &model.ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be 'pending', 'accepted', 'declined', 'cancelled', or 'expired'",
+		}
+	}
+	return nil
+}
+
+// teamInvitationError maps repository errors of team invitations to HTTP responses
+func teamInvitationError(c *fiber.Ctx, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "tidak ditemukan"):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Error: "not_found", Message: err.Error()})
+	case strings.Contains(err.Error(), "invalid"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid_id", Message: err.Error()})
+	case strings.Contains(err.Error(), "sudah"):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Error: "conflict", Message: err.Error()})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "validation_error", Message: err.Error()})
+}
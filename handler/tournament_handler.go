@@ -2,8 +2,11 @@ package handler
 
 import (
 	"embeck/model"
+	"embeck/pkg/bracket"
 	"embeck/repository"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,6 +15,12 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// validBracketFormats lists the bracket formats a tournament can use
+var validBracketFormats = map[string]bool{
+	bracket.FormatSingleElimination: true,
+	bracket.FormatDoubleElimination: true,
+}
+
 // CreateTournament creates a new tournament (admin only)
 // @Summary Create new tournament
 // @Description Create a new tournament (admin access required)
@@ -56,6 +65,17 @@ func CreateTournament(c *fiber.Ctx) error {
 		})
 	}
 
+	// Validate bracket format
+	if req.BracketFormat == "" {
+		req.BracketFormat = bracket.FormatSingleElimination
+	}
+	if !validBracketFormats[req.BracketFormat] {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_bracket_format",
+			Message: "Bracket format must be 'single_elimination' or 'double_elimination'",
+		})
+	}
+
 	// Validate date range
 	if req.EndDate.Before(req.StartDate) {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
@@ -64,6 +84,14 @@ func CreateTournament(c *fiber.Ctx) error {
 		})
 	}
 
+	// Validate registration window
+	if err := validateRegistrationWindow(req.Registration); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_registration",
+			Message: err.Error(),
+		})
+	}
+
 	// Convert team IDs to ObjectIDs if provided
 	var teamsParticipating []primitive.ObjectID
 	if len(req.TeamsParticipating) > 0 {
@@ -96,6 +124,9 @@ func CreateTournament(c *fiber.Ctx) error {
 		PrizePool:          req.PrizePool,
 		RulesDocumentURL:   req.RulesDocumentURL,
 		Status:             req.Status,
+		BracketFormat:      req.BracketFormat,
+		BracketReset:       req.BracketReset != nil && *req.BracketReset,
+		Registration:       req.Registration,
 		TeamsParticipating: teamsParticipating,
 		CreatedBy:          primitive.NewObjectID(), // TODO: Get from JWT
 		CreatedAt:          time.Now(),
@@ -119,7 +150,7 @@ func CreateTournament(c *fiber.Ctx) error {
 
 // GetAllTournaments gets all tournaments (admin view)
 // @Summary Get all tournaments
-// @Description Get all tournaments with admin details
+// @Description Get all tournaments with admin details. Staff whose role is scoped to tournaments only see those tournaments
 // @Tags Tournament Management (Admin)
 // @Produce json
 // @Security BearerAuth
@@ -127,7 +158,10 @@ func CreateTournament(c *fiber.Ctx) error {
 // @Failure 500 {object} model.ErrorResponse
 // @Router /api/admin/tournaments [get]
 func GetAllTournaments(c *fiber.Ctx) error {
-	tournaments, err := repository.GetAllTournaments()
+	// Set by RequirePermissionInAnyTournament for staff scoped to some tournaments
+	tournamentIDs, _ := c.Locals("tournament_ids").([]primitive.ObjectID)
+
+	tournaments, err := repository.GetAllTournaments(tournamentIDs)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 			Error:   "database_error",
@@ -146,6 +180,7 @@ func GetAllTournaments(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Tournament ID"
 // @Success 200 {object} model.Tournament
+// @Header 200 {string} ETag "Versi tournament untuk header If-Match"
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /api/admin/tournaments/{id} [get]
@@ -167,6 +202,7 @@ func GetTournamentByID(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, tournament.Version)
 	return c.Status(fiber.StatusOK).JSON(tournament)
 }
 
@@ -179,9 +215,13 @@ func GetTournamentByID(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Tournament ID"
 // @Param tournament body model.TournamentRequest true "Tournament data"
+// @Param If-Match header string true "ETag dari GET data yang akan diubah"
 // @Success 200 {object} model.TournamentResponse
+// @Header 200 {string} ETag "Versi tournament setelah diupdate"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse "Data sudah diubah oleh request lain"
+// @Failure 428 {object} model.ErrorResponse "Header If-Match tidak dikirim"
 // @Failure 500 {object} model.ErrorResponse
 // @Router /api/admin/tournaments/{id} [put]
 func UpdateTournament(c *fiber.Ctx) error {
@@ -211,6 +251,14 @@ func UpdateTournament(c *fiber.Ctx) error {
 		}
 	}
 
+	// Validate bracket format if provided
+	if req.BracketFormat != "" && !validBracketFormats[req.BracketFormat] {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_bracket_format",
+			Message: "Bracket format must be 'single_elimination' or 'double_elimination'",
+		})
+	}
+
 	// Validate date range if both dates provided
 	if !req.StartDate.IsZero() && !req.EndDate.IsZero() && req.EndDate.Before(req.StartDate) {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
@@ -219,6 +267,14 @@ func UpdateTournament(c *fiber.Ctx) error {
 		})
 	}
 
+	// Validate registration window if provided
+	if err := validateRegistrationWindow(req.Registration); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_registration",
+			Message: err.Error(),
+		})
+	}
+
 	// Build update document
 	update := bson.M{}
 	if req.Name != "" {
@@ -242,6 +298,15 @@ func UpdateTournament(c *fiber.Ctx) error {
 	if req.Status != "" {
 		update["status"] = req.Status
 	}
+	if req.BracketFormat != "" {
+		update["bracket_format"] = req.BracketFormat
+	}
+	if req.BracketReset != nil {
+		update["bracket_reset"] = *req.BracketReset
+	}
+	if req.Registration != nil {
+		update["registration"] = req.Registration
+	}
 
 	// Handle teams participating
 	if len(req.TeamsParticipating) > 0 {
@@ -267,10 +332,18 @@ func UpdateTournament(c *fiber.Ctx) error {
 		update["teams_participating"] = teamsParticipating
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return versionError(c, err)
+	}
+
 	// Update tournament
-	err := repository.UpdateTournament(id, update)
+	err = repository.UpdateTournament(id, update, version)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if errors.Is(err, repository.ErrStaleVersion) {
+			return versionError(c, err)
+		}
+		if err == mongo.ErrNoDocuments || strings.Contains(err.Error(), "tidak ditemukan") {
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Error:   "not_found",
 				Message: "Tournament not found",
@@ -282,14 +355,21 @@ func UpdateTournament(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(model.TournamentResponse{
-		Message: "Tournament updated successfully",
-	})
+	// Starting the tournament also locks its rosters, so the version is read back
+	response := model.TournamentResponse{
+		Message:      "Tournament updated successfully",
+		TournamentID: id,
+	}
+	if updated, err := repository.GetTournamentByID(id); err == nil && updated != nil {
+		setETag(c, updated.Version)
+		response.Version = updated.Version
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
 }
 
 // DeleteTournament deletes a tournament
 // @Summary Delete tournament
-// @Description Delete tournament by ID
+// @Description Move tournament to the trash; it can be restored or purged through /api/admin/trash
 // @Tags Tournament Management (Admin)
 // @Produce json
 // @Security BearerAuth
@@ -301,18 +381,14 @@ func UpdateTournament(c *fiber.Ctx) error {
 func DeleteTournament(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	err := repository.DeleteTournament(id)
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	err := repository.DeleteTournament(id, actor)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
-				Error:   "not_found",
-				Message: "Tournament not found",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to delete tournament",
-		})
+		return deleteError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.TournamentResponse{
@@ -347,6 +423,7 @@ func GetAllTournamentsPublic(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Tournament ID"
 // @Success 200 {object} model.TournamentWithDetails
+// @Header 200 {string} ETag "Versi tournament"
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /api/tournaments/{id} [get]
@@ -367,5 +444,6 @@ func GetTournamentWithDetailsByID(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, tournament.Version)
 	return c.Status(fiber.StatusOK).JSON(tournament)
 }
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetTournamentRosters godoc
+// @Summary Get Tournament Rosters (public)
+// @Description Mendapatkan roster setiap team peserta. Setelah turnamen dimulai roster terkunci (locked) sehingga perubahan roster team tidak mengubah riwayat pertandingan
+// @Tags Tournament Data (Public)
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {array} model.TournamentRoster
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/tournaments/{id}/rosters [get]
+func GetTournamentRosters(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	rosters, err := repository.GetTournamentRosters(c.Context(), *tournament)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve rosters",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rosters)
+}
+
+// LockTournamentRosters godoc
+// @Summary Lock Tournament Rosters
+// @Description Mengunci roster semua team peserta sebelum turnamen dimulai. Roster juga terkunci otomatis saat status turnamen menjadi ongoing; roster yang sudah terkunci tidak ditimpa
+// @Tags Tournament Management (Admin)
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} model.LockRostersResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/admin/tournaments/{id}/rosters/lock [post]
+func LockTournamentRosters(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	tournament, err := repository.GetTournamentByID(id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Error:   "not_found",
+				Message: "Tournament not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tournament ID format",
+		})
+	}
+
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	locked, err := repository.LockTournamentRosters(c.Context(), tournament.ID, &actor)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to lock rosters",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.LockRostersResponse{
+		Message:      "Rosters locked successfully",
+		TournamentID: tournament.ID.Hex(),
+		LockedTeams:  locked,
+	})
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPlayerTransfers godoc
+// @Summary Get Player Transfer History (public)
+// @Description Mendapatkan riwayat keanggotaan team (joined_at, left_at, role) dan transfer seorang player
+// @Tags Transfers
+// @Produce json
+// @Param id path string true "Player ID"
+// @Success 200 {object} model.PlayerTransferHistory
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/players/{id}/transfers [get]
+func GetPlayerTransfers(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	player, err := repository.GetPlayerByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid player ID format",
+		})
+	}
+	if player == nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Player not found",
+		})
+	}
+
+	history, err := repository.GetPlayerTransferHistory(c.Context(), player.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve transfer history",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(history)
+}
+
+// GetTeamTransfers godoc
+// @Summary Get Team Transfer History (public)
+// @Description Mendapatkan riwayat keanggotaan roster team serta transfer player masuk dan keluar
+// @Tags Transfers
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {object} model.TeamTransferHistory
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/teams/{id}/transfers [get]
+func GetTeamTransfers(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	team, err := repository.GetTeamByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid team ID format",
+		})
+	}
+	if team == nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Team not found",
+		})
+	}
+
+	history, err := repository.GetTeamTransferHistory(c.Context(), team.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve transfer history",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(history)
+}
+
+// GetRecentTransfers godoc
+// @Summary Get Recent Transfers (public)
+// @Description Mendapatkan feed transfer terbaru (player bergabung, pindah, atau keluar dari team) untuk halaman berita
+// @Tags Transfers
+// @Produce json
+// @Param days query int false "Rentang hari ke belakang (default 30, maksimal 365)"
+// @Param limit query int false "Jumlah transfer (default 20, maksimal 100)"
+// @Success 200 {array} model.Transfer
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/transfers [get]
+func GetRecentTransfers(c *fiber.Ctx) error {
+	days := c.QueryInt("days", 30)
+	if days <= 0 || days > 365 {
+		days = 30
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	feed, err := repository.GetRecentTransfers(c.Context(), since, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve transfers",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(feed)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"embeck/model"
+	"embeck/repository"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTrash godoc
+// @Summary Get Trash
+// @Description Mendapatkan daftar data yang dihapus (soft delete), terbaru lebih dulu; dapat difilter per tipe
+// @Tags Trash (Admin)
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Tipe data (user, player, team, tournament, match)"
+// @Success 200 {array} model.TrashItem
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/trash [get]
+func GetTrash(c *fiber.Ctx) error {
+	kind := c.Query("type")
+	if errResponse := validateTrashType(kind, true); errResponse != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResponse)
+	}
+
+	items, err := repository.GetTrash(c.Context(), kind)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve trash",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(items)
+}
+
+// RestoreFromTrash godoc
+// @Summary Restore From Trash
+// @Description Memulihkan data dari trash. Tim hanya dipulihkan bila pemainnya belum bergabung dengan tim lain
+// @Tags Trash (Admin)
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Tipe data (user, player, team, tournament, match)"
+// @Param id path string true "ID data"
+// @Success 200 {object} model.TrashResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /api/admin/trash/{type}/{id}/restore [post]
+func RestoreFromTrash(c *fiber.Ctx) error {
+	kind := c.Params("type")
+	id := c.Params("id")
+	if errResponse := validateTrashType(kind, false); errResponse != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResponse)
+	}
+
+	if err := repository.RestoreFromTrash(c.Context(), kind, id); err != nil {
+		return trashError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TrashResponse{
+		Message: "Restored successfully",
+		Type:    kind,
+		ID:      id,
+	})
+}
+
+// PurgeFromTrash godoc
+// @Summary Purge From Trash
+// @Description Menghapus permanen data yang sudah ada di trash. Ditolak (409) bila masih direferensikan data lain, kecuali dengan cascade=true yang membersihkan referensi dan mengarsipkan data terkait dalam satu transaksi
+// @Tags Trash (Admin)
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Tipe data (user, player, team, tournament, match)"
+// @Param id path string true "ID data"
+// @Param cascade query bool false "Bersihkan atau arsipkan data yang masih mereferensikan"
+// @Success 200 {object} model.TrashResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.DeleteConflictResponse
+// @Router /api/admin/trash/{type}/{id} [delete]
+func PurgeFromTrash(c *fiber.Ctx) error {
+	kind := c.Params("type")
+	id := c.Params("id")
+	if errResponse := validateTrashType(kind, false); errResponse != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResponse)
+	}
+
+	if err := repository.PurgeFromTrash(c.Context(), kind, id, c.QueryBool("cascade")); err != nil {
+		return trashError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TrashResponse{
+		Message: "Purged permanently",
+		Type:    kind,
+		ID:      id,
+	})
+}
+
+// validateTrashType checks a trash type, returning the error response for an unknown one
+func validateTrashType(kind string, optional bool) *model.ErrorResponse {
+	if (optional && kind == "") || slices.Contains(repository.TrashTypes, kind) {
+		return nil
+	}
+	return &model.ErrorResponse{
+		Error:   "invalid_type",
+		Message: fmt.Sprintf("Type must be one of: %s", strings.Join(repository.TrashTypes, ", ")),
+	}
+}
+
+// trashError maps repository errors of trash operations to HTTP responses
+func trashError(c *fiber.Ctx, err error) error {
+	if strings.Contains(err.Error(), "sudah") {
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Error:   "conflict",
+			Message: err.Error(),
+		})
+	}
+	return deleteError(c, err)
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/totp"
+	"embeck/repository"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// claimsUser loads the logged-in user. It responds itself and returns nil when that fails
+func claimsUser(c *fiber.Ctx) (*model.User, error) {
+	userID, ok := claimsUserID(c)
+	if !ok {
+		return nil, unauthorizedClaims(c)
+	}
+	user, err := repository.GetUserByID(c.Context(), userID.Hex())
+	if err != nil {
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to get user",
+		})
+	}
+	if user == nil {
+		return nil, unauthorizedClaims(c)
+	}
+	return user, nil
+}
+
+// twoFactorError maps errors of the 2FA operations to HTTP responses
+func twoFactorError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrInvalidTwoFactorCode):
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+			Error:   "invalid_code",
+			Message: err.Error(),
+		})
+	case errors.Is(err, repository.ErrInvalidUserToken):
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+			Error:   "invalid_challenge",
+			Message: "Challenge login tidak valid atau sudah kedaluwarsa, silakan login ulang",
+		})
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "two_factor_error",
+			Message: err.Error(),
+		})
+	}
+}
+
+// SetupTwoFactor godoc
+// @Summary Start 2FA Setup
+// @Description Membuat secret TOTP baru beserta URI otpauth untuk QR code. 2FA baru aktif setelah dikonfirmasi lewat /api/auth/2fa/enable
+// @Tags Two-Factor Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.TwoFactorSetupResponse
+// @Failure 400 {object} model.ErrorResponse "2FA sudah aktif"
+// @Failure 401 {object} model.ErrorResponse
+// @Router /api/auth/2fa/setup [post]
+func SetupTwoFactor(c *fiber.Ctx) error {
+	user, err := claimsUser(c)
+	if user == nil {
+		return err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to generate secret",
+		})
+	}
+	if err := repository.StartTwoFactorSetup(c.Context(), user.ID, secret); err != nil {
+		return twoFactorError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.TwoFactorSetupResponse{
+		Message:         "Scan the QR code with an authenticator app, then confirm with a code",
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(config.TOTPIssuer(), user.Email, secret),
+	})
+}
+
+// EnableTwoFactor godoc
+// @Summary Enable 2FA
+// @Description Mengaktifkan 2FA dengan kode dari aplikasi authenticator dan mengembalikan recovery code yang hanya ditampilkan sekali. Session lain milik user diakhiri
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TwoFactorCodeRequest true "Kode TOTP"
+// @Success 200 {object} model.RecoveryCodesResponse
+// @Failure 400 {object} model.ErrorResponse "Belum ada setup 2FA"
+// @Failure 401 {object} model.ErrorResponse "Kode tidak valid"
+// @Router /api/auth/2fa/enable [post]
+func EnableTwoFactor(c *fiber.Ctx) error {
+	var req model.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "code is required",
+		})
+	}
+
+	user, err := claimsUser(c)
+	if user == nil {
+		return err
+	}
+	sessionHex, _ := c.Locals("session_id").(string)
+	sessionID, _ := primitive.ObjectIDFromHex(sessionHex)
+
+	codes, err := repository.EnableTwoFactor(c.Context(), *user, req.Code, sessionID)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.RecoveryCodesResponse{
+		Message:       "Two-factor authentication enabled, store the recovery codes somewhere safe",
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable 2FA
+// @Description Menonaktifkan 2FA dengan kode TOTP atau recovery code. Admin tidak dapat menonaktifkan 2FA saat REQUIRE_ADMIN_2FA=true
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TwoFactorCodeRequest true "Kode TOTP atau recovery code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse "Kode tidak valid"
+// @Failure 403 {object} model.ErrorResponse "2FA wajib untuk admin"
+// @Router /api/auth/2fa/disable [post]
+func DisableTwoFactor(c *fiber.Ctx) error {
+	var req model.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request data",
+		})
+	}
+
+	user, err := claimsUser(c)
+	if user == nil {
+		return err
+	}
+	if config.RequireAdminTwoFactor() && user.Role == "admin" {
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+			Error:   "two_factor_required",
+			Message: "2FA wajib untuk admin dan tidak dapat dinonaktifkan",
+		})
+	}
+
+	if err := repository.DisableTwoFactor(c.Context(), *user, req.Code, req.RecoveryCode); err != nil {
+		return twoFactorError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate Recovery Codes
+// @Description Mengganti semua recovery code dengan yang baru setelah konfirmasi kode TOTP. Recovery code lama tidak berlaku lagi
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TwoFactorCodeRequest true "Kode TOTP"
+// @Success 200 {object} model.RecoveryCodesResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse "Kode tidak valid"
+// @Router /api/auth/2fa/recovery-codes [post]
+func RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	var req model.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "code is required",
+		})
+	}
+
+	user, err := claimsUser(c)
+	if user == nil {
+		return err
+	}
+
+	codes, err := repository.RegenerateRecoveryCodes(c.Context(), *user, req.Code)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.RecoveryCodesResponse{
+		Message:       "Recovery codes regenerated, the previous codes no longer work",
+		RecoveryCodes: codes,
+	})
+}
+
+// LoginTwoFactor godoc
+// @Summary Login Second Step
+// @Description Menyelesaikan login akun dengan 2FA memakai challenge_token dari /api/auth/login dan kode TOTP atau recovery code. Kode yang salah dihitung sebagai login gagal untuk akun dan IP
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.TwoFactorLoginRequest true "Challenge token dan kode"
+// @Success 200 {object} model.AuthResponse "Login berhasil dengan token"
+// @Failure 400 {object} model.ErrorResponse "Request data tidak valid"
+// @Failure 401 {object} model.ErrorResponse "Kode atau challenge tidak valid"
+// @Failure 429 {object} map[string]interface{} "Terlalu banyak percobaan gagal, coba lagi setelah header Retry-After"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /api/auth/login/2fa [post]
+func LoginTwoFactor(c *fiber.Ctx) error {
+	var req model.TwoFactorLoginRequest
+	if err := c.BodyParser(&req); err != nil || req.ChallengeToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "challenge_token is required",
+		})
+	}
+
+	user, throttle, err := repository.CompleteTwoFactorLogin(c.Context(), req.ChallengeToken, req.Code, req.RecoveryCode, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+	if throttle != nil {
+		return loginThrottled(c, throttle)
+	}
+	_ = repository.ClearLoginFailures(c.Context(), strings.ToLower(user.Email))
+
+	session, refreshToken, err := repository.CreateSession(c.Context(), user.ID, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to create session",
+		})
+	}
+	return authTokens(c, "Login successful", user, session, refreshToken)
+}
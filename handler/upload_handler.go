@@ -27,13 +27,29 @@ import (
 // @Failure 500 {object} model.ErrorResponse
 // @Router /api/admin/upload/team-logo [post]
 func UploadTeamLogo(c *fiber.Ctx) error {
+	response, failure := saveTeamLogo(c)
+	if failure != nil {
+		return c.Status(failure.status).JSON(failure.response)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// uploadError carries the HTTP status and body of a rejected upload
+type uploadError struct {
+	status   int
+	response model.ErrorResponse
+}
+
+// saveTeamLogo validates the uploaded team logo and stores it under ./uploads/team_logos
+func saveTeamLogo(c *fiber.Ctx) (*model.UploadResponse, *uploadError) {
 	// Parse multipart form
 	file, err := c.FormFile("file")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+		return nil, &uploadError{fiber.StatusBadRequest, model.ErrorResponse{
 			Error:   "bad_request",
 			Message: "No file uploaded",
-		})
+		}}
 	}
 
 	// Validate file extension
@@ -45,19 +61,19 @@ func UploadTeamLogo(c *fiber.Ctx) error {
 	}
 
 	if !allowedExts[ext] {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+		return nil, &uploadError{fiber.StatusBadRequest, model.ErrorResponse{
 			Error:   "invalid_file_type",
 			Message: "Only JPG, JPEG, and PNG files are allowed",
-		})
+		}}
 	}
 
 	// Validate file size (max 5MB)
 	maxSize := int64(5 * 1024 * 1024) // 5MB
 	if file.Size > maxSize {
-		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+		return nil, &uploadError{fiber.StatusBadRequest, model.ErrorResponse{
 			Error:   "file_too_large",
 			Message: "File size must be less than 5MB",
-		})
+		}}
 	}
 
 	// Generate unique filename
@@ -68,29 +84,29 @@ func UploadTeamLogo(c *fiber.Ctx) error {
 	// Create upload directory if it doesn't exist
 	uploadDir := "./uploads/team_logos"
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+		return nil, &uploadError{fiber.StatusInternalServerError, model.ErrorResponse{
 			Error:   "server_error",
 			Message: "Failed to create upload directory",
-		})
+		}}
 	}
 
 	// Save file
 	filePath := filepath.Join(uploadDir, newFileName)
 	if err := saveUploadedFile(file, filePath); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+		return nil, &uploadError{fiber.StatusInternalServerError, model.ErrorResponse{
 			Error:   "save_failed",
 			Message: "Failed to save uploaded file",
-		})
+		}}
 	}
 
 	// Generate file URL (relative path for serving)
 	fileURL := fmt.Sprintf("/uploads/team_logos/%s", newFileName)
 
-	return c.Status(fiber.StatusOK).JSON(model.UploadResponse{
+	return &model.UploadResponse{
 		Message:  "File uploaded successfully",
 		FileURL:  fileURL,
 		FileName: newFileName,
-	})
+	}, nil
 }
 
 // UploadPlayerAvatar uploads player avatar image
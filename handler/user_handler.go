@@ -3,6 +3,7 @@ package handler
 import (
 	"embeck/model"
 	"embeck/repository"
+	"errors"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -45,7 +46,8 @@ func GetAllUsers(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID" example("64f123abc456def789012345")
-// @Success 200 {object} model.UserProfile "User detail berhasil diambil"
+// @Success 200 {object} model.UserProfile "User detail berhasil diambil, termasuk locked_until jika login sedang dikunci"
+// @Header 200 {string} ETag "Versi user untuk header If-Match"
 // @Failure 400 {object} map[string]interface{} "ID tidak valid"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - Token tidak valid"
 // @Failure 403 {object} map[string]interface{} "Forbidden - Hanya admin yang dapat mengakses"
@@ -81,14 +83,20 @@ func GetUserByID(c *fiber.Ctx) error {
 
 	// Return user profile (without password)
 	profile := model.UserProfile{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		Role:             user.Role,
+		PlayerID:         user.PlayerID,
+		EmailVerified:    user.EmailVerifiedAt != nil,
+		TwoFactorEnabled: user.TwoFactor != nil && user.TwoFactor.Enabled,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+		Version:          user.Version,
 	}
+	profile.LockedUntil, _ = repository.GetAccountLock(c.Context(), user.Email)
 
+	setETag(c, user.Version)
 	return c.Status(fiber.StatusOK).JSON(profile)
 }
 
@@ -101,12 +109,16 @@ func GetUserByID(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "User ID" example("64f123abc456def789012345")
 // @Param request body model.UpdateUserRequest true "Data user yang akan diupdate"
+// @Param If-Match header string true "ETag dari GET data yang akan diubah"
 // @Success 200 {object} model.UserProfile "User berhasil diupdate"
+// @Header 200 {string} ETag "Versi user setelah diupdate"
 // @Failure 400 {object} map[string]interface{} "Request data tidak valid"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - Token tidak valid"
 // @Failure 403 {object} map[string]interface{} "Forbidden - Hanya admin yang dapat mengakses"
 // @Failure 404 {object} map[string]interface{} "User tidak ditemukan"
 // @Failure 409 {object} map[string]interface{} "Username atau email sudah digunakan"
+// @Failure 412 {object} model.ErrorResponse "Data sudah diubah oleh request lain"
+// @Failure 428 {object} model.ErrorResponse "Header If-Match tidak dikirim"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/admin/users/{id} [put]
 func UpdateUser(c *fiber.Ctx) error {
@@ -156,24 +168,32 @@ func UpdateUser(c *fiber.Ctx) error {
 	if req.Username != "" {
 		updateData["username"] = req.Username
 	}
-	if req.Email != "" {
+	if req.Email != "" && strings.ToLower(req.Email) != existingUser.Email {
 		updateData["email"] = strings.ToLower(req.Email)
 	}
 	if req.Role != "" {
 		updateData["role"] = req.Role
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return versionError(c, err)
+	}
+
 	// Update user in database
-	_, err = repository.UpdateUser(c.Context(), id, updateData)
+	_, err = repository.UpdateUser(c.Context(), id, updateData, version)
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return versionError(c, err)
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "sudah digunakan") {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 				"error": err.Error(),
 			})
 		}
-		if strings.Contains(err.Error(), "tidak ada data yang diupdate") || strings.Contains(err.Error(), "not found") {
+		if strings.Contains(err.Error(), "tidak ditemukan") {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "User not found or no changes made",
+				"error": "User not found",
 			})
 		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -191,20 +211,25 @@ func UpdateUser(c *fiber.Ctx) error {
 
 	// Return updated user profile
 	profile := model.UserProfile{
-		ID:        updatedUser.ID,
-		Username:  updatedUser.Username,
-		Email:     updatedUser.Email,
-		Role:      updatedUser.Role,
-		CreatedAt: updatedUser.CreatedAt,
-		UpdatedAt: updatedUser.UpdatedAt,
+		ID:               updatedUser.ID,
+		Username:         updatedUser.Username,
+		Email:            updatedUser.Email,
+		Role:             updatedUser.Role,
+		PlayerID:         updatedUser.PlayerID,
+		EmailVerified:    updatedUser.EmailVerifiedAt != nil,
+		TwoFactorEnabled: updatedUser.TwoFactor != nil && updatedUser.TwoFactor.Enabled,
+		CreatedAt:        updatedUser.CreatedAt,
+		UpdatedAt:        updatedUser.UpdatedAt,
+		Version:          updatedUser.Version,
 	}
 
+	setETag(c, updatedUser.Version)
 	return c.Status(fiber.StatusOK).JSON(profile)
 }
 
 // DeleteUser godoc
 // @Summary Delete User
-// @Description Menghapus user dari sistem (Admin only)
+// @Description Memindahkan user ke trash sehingga tidak bisa login; dapat dipulihkan atau dihapus permanen lewat /api/admin/trash (Admin only)
 // @Tags Users Management
 // @Accept json
 // @Produce json
@@ -244,10 +269,15 @@ func DeleteUser(c *fiber.Ctx) error {
 		})
 	}
 
-	// Delete user from database
-	_, err = repository.DeleteUser(c.Context(), id)
+	actor, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	// Move user to the trash
+	_, err = repository.DeleteUser(c.Context(), id, actor)
 	if err != nil {
-		if strings.Contains(err.Error(), "tidak ada data yang dihapus") {
+		if strings.Contains(err.Error(), "tidak ditemukan") {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "User not found",
 			})
@@ -100,3 +100,72 @@ func HandleGetUserTickets(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(tickets)
 }
+
+// ScanTicket godoc
+// @Summary Scan Ticket
+// @Description Menandai tiket valid sebagai terpakai di gerbang masuk. Tiket hanya bisa dipindai sekali (Admin atau staff dengan permission ticket:scan)
+// @Tags Tickets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Ticket ID"
+// @Success 200 {object} model.UserTicket
+// @Failure 400 {object} model.ErrorResponse "Invalid ticket ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Permission ticket:scan required"
+// @Failure 404 {object} model.ErrorResponse "Ticket not found"
+// @Failure 409 {object} model.ErrorResponse "Ticket already used"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /api/admin/tickets/{id}/scan [post]
+func ScanTicket(c *fiber.Ctx) error {
+	scannedBy, ok := claimsUserID(c)
+	if !ok {
+		return unauthorizedClaims(c)
+	}
+
+	ticket, err := repository.ScanTicket(c.Context(), c.Params("id"), scannedBy)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid ticket ID") {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid_id", Message: err.Error()})
+		}
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Error: "not_found", Message: err.Error()})
+		}
+		if strings.Contains(err.Error(), "already") {
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Error: "conflict", Message: err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Error: "database_error", Message: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(ticket)
+}
+
+// GetTournamentTicketSales godoc
+// @Summary Get Tournament Ticket Sales
+// @Description Mendapatkan jumlah tiket terjual dan terpakai untuk setiap match di turnamen (Admin atau staff dengan permission finance:view)
+// @Tags Tickets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} model.TicketSalesReport
+// @Failure 400 {object} model.ErrorResponse "Invalid tournament ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Permission finance:view required"
+// @Failure 404 {object} model.ErrorResponse "Tournament not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /api/admin/tournaments/{id}/ticket-sales [get]
+func GetTournamentTicketSales(c *fiber.Ctx) error {
+	tournamentObjID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid_id", Message: "Invalid tournament ID format"})
+	}
+
+	report, err := repository.GetTournamentTicketSales(c.Context(), tournamentObjID)
+	if err != nil {
+		if strings.Contains(err.Error(), "tidak ditemukan") {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Error: "not_found", Message: err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Error: "database_error", Message: "Failed to retrieve ticket sales"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"embeck/config"
+	"embeck/handler"
+	"embeck/pkg/auth"
+	"embeck/pkg/mailer"
+	"embeck/pkg/scheduler"
+	"embeck/repository"
 	"embeck/router"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -41,9 +48,19 @@ func init() {
 // @description Type "Bearer" followed by a space and a PASETO token.
 func main() {
 	// Create Fiber app
-	app := fiber.New(fiber.Config{
+	appConfig := fiber.Config{
 		AppName: "EMBECK API v1.0",
-	})
+	}
+
+	// Behind a reverse proxy the client IP is read from X-Forwarded-For, trusted only when the
+	// request comes from one of the configured proxies
+	if proxies := config.TrustedProxies(); len(proxies) > 0 {
+		appConfig.ProxyHeader = fiber.HeaderXForwardedFor
+		appConfig.EnableTrustedProxyCheck = true
+		appConfig.TrustedProxies = proxies
+		appConfig.EnableIPValidation = true
+	}
+	app := fiber.New(appConfig)
 
 	// Middleware
 	app.Use(logger.New())
@@ -55,17 +72,50 @@ func main() {
 		log.Fatal("Failed to connect to database")
 	}
 
+	// Load the token keys once so a bad key stops startup instead of failing every login
+	keyring, err := auth.LoadKeyring(auth.KeyringConfig{
+		SigningKeyID:    config.PasetoKeyID(),
+		PrivateKey:      config.PasetoPrivateKey(),
+		PublicKeys:      config.PasetoPublicKeys(),
+		LegacyPublicKey: config.PasetoLegacyPublicKey(),
+		LegacyRetireAt:  config.PasetoLegacyRetireAt(),
+	})
+	if err != nil {
+		log.Fatal("Failed to load token keys: ", err)
+	}
+	log.Printf("🔑 Token keys loaded, accepting %v", keyring.KeyIDs(time.Now()))
+
 	// Setup Cors
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     strings.Join(config.GetAllowedOrigins(), ","),
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, If-Match",
+		ExposeHeaders:    "ETag",
 		AllowCredentials: true,
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
 	}))
 
+	// Deliver account emails through SMTP instead of the outbox when configured
+	if config.MailerDriver() == "smtp" {
+		handler.Mailer = mailer.SMTP{
+			Host:     config.SMTPHost(),
+			Port:     config.SMTPPort(),
+			Username: config.SMTPUsername(),
+			Password: config.SMTPPassword(),
+			From:     config.MailFrom(),
+		}
+	}
+
 	// Setup routes
 	router.SetupRoutes(app)
 
+	// Keep tournament and match statuses in sync in the background
+	if config.SchedulerEnabled() {
+		statusScheduler := scheduler.New(repository.SchedulerStore{})
+		statusScheduler.Interval = config.SchedulerInterval()
+		statusScheduler.Grace = config.MatchOverdueGrace()
+		go statusScheduler.Run(context.Background())
+	}
+
 	// Routes
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
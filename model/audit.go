@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audit actions of the plain CRUD routes. Other admin routes record their sub-path as action,
+// e.g. "approve", "games_void" or "rosters_lock"
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	AuditPurge  = "purge"
+)
+
+// AuditLog records one admin mutation: who did it, on what, what changed and how it was requested
+type AuditLog struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	ActorID       primitive.ObjectID `bson:"actor_id" json:"actor_id"`
+	ActorUsername string             `bson:"actor_username" json:"actor_username" example:"admin"`
+	ActorRole     string             `bson:"actor_role" json:"actor_role" example:"admin"`
+	Action        string             `bson:"action" json:"action" example:"update"`
+	EntityType    string             `bson:"entity_type" json:"entity_type" example:"match"`
+	EntityID      string             `bson:"entity_id,omitempty" json:"entity_id,omitempty" example:"687e5cd44643a58edf8210e8"`
+	Changes       []AuditChange      `bson:"changes,omitempty" json:"changes,omitempty"`
+	Request       AuditRequest       `bson:"request" json:"request"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// AuditChange is the before and after value of one changed field
+type AuditChange struct {
+	Field  string      `bson:"field" json:"field" example:"result_team_a_score"`
+	Before interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After  interface{} `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// AuditRequest holds the metadata of the audited request
+type AuditRequest struct {
+	Method    string `bson:"method" json:"method" example:"PUT"`
+	Path      string `bson:"path" json:"path" example:"/api/admin/matches/687e5cd44643a58edf8210e8"`
+	Query     string `bson:"query,omitempty" json:"query,omitempty"`
+	Status    int    `bson:"status" json:"status" example:"200"`
+	IP        string `bson:"ip" json:"ip" example:"127.0.0.1"`
+	UserAgent string `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+}
+
+// AuditLogFilter narrows the audit log listing; zero values do not filter
+type AuditLogFilter struct {
+	ActorID    *primitive.ObjectID
+	EntityType string
+	EntityID   string
+	Action     string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	Limit      int
+}
+
+// AuditLogList represents a page of audit logs, newest first
+type AuditLogList struct {
+	Total int64      `json:"total" example:"120"`
+	Page  int        `json:"page" example:"1"`
+	Limit int        `json:"limit" example:"50"`
+	Logs  []AuditLog `json:"logs"`
+}
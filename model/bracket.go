@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// BracketRequest represents request body for generating a tournament bracket
+type BracketRequest struct {
+	Seeding   string    `json:"seeding" example:"random"`
+	TeamIDs   []string  `json:"team_ids,omitempty" example:"687f9d7c8efa8f58af86646a,687f9d7c8efa8f58af86646b"`
+	MatchDate time.Time `json:"match_date,omitempty"`
+	MatchTime string    `json:"match_time,omitempty" example:"20:00"`
+	Location  string    `json:"location,omitempty" example:"Stadium XYZ"`
+}
+
+// BracketResponse represents response for bracket generation
+type BracketResponse struct {
+	Message      string   `json:"message"`
+	TournamentID string   `json:"tournament_id"`
+	TotalMatches int      `json:"total_matches"`
+	MatchIDs     []string `json:"match_ids"`
+}
+
+// BracketView represents the bracket layout of a tournament, grouped for drawing on the frontend
+type BracketView struct {
+	Format string             `json:"format"`
+	Rounds []BracketRoundView `json:"rounds"`
+}
+
+// BracketRoundView represents one round of one bracket section (main, upper, lower or grand_final)
+type BracketRoundView struct {
+	Bracket string           `json:"bracket"`
+	Round   int              `json:"round"`
+	Name    string           `json:"name"`
+	Matches []MatchBasicInfo `json:"matches"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TeamDisqualification records a team being disqualified from a tournament
+type TeamDisqualification struct {
+	TeamID         primitive.ObjectID `bson:"team_id" json:"team_id"`
+	Reason         string             `bson:"reason" json:"reason"`
+	DisqualifiedBy primitive.ObjectID `bson:"disqualified_by" json:"disqualified_by"`
+	DisqualifiedAt time.Time          `bson:"disqualified_at" json:"disqualified_at"`
+}
+
+// DisqualifyTeamRequest represents request body for disqualifying a team from a tournament
+type DisqualifyTeamRequest struct {
+	TeamID string `json:"team_id" validate:"required" example:"687f9d7c8efa8f58af86646b"`
+	Reason string `json:"reason" validate:"required" example:"Menggunakan akun pemain yang tidak terdaftar"`
+}
+
+// DisqualifyTeamResponse represents response for disqualifying a team
+type DisqualifyTeamResponse struct {
+	Message          string   `json:"message"`
+	TournamentID     string   `json:"tournament_id"`
+	TeamID           string   `json:"team_id"`
+	ForfeitedMatches []string `json:"forfeited_matches"`
+}
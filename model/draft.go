@@ -0,0 +1,66 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// GameDraft represents the hero draft of one game: bans and picks per team in order
+type GameDraft struct {
+	Bans  []DraftBan  `bson:"bans" json:"bans"`
+	Picks []DraftPick `bson:"picks" json:"picks"`
+}
+
+// DraftBan represents one hero ban in a draft
+type DraftBan struct {
+	Order  int                `bson:"order" json:"order"`
+	TeamID primitive.ObjectID `bson:"team_id" json:"team_id"`
+	Hero   string             `bson:"hero" json:"hero"`
+}
+
+// DraftPick represents one hero pick in a draft and who played it in which role
+type DraftPick struct {
+	Order    int                 `bson:"order" json:"order"`
+	TeamID   primitive.ObjectID  `bson:"team_id" json:"team_id"`
+	Hero     string              `bson:"hero" json:"hero"`
+	PlayerID *primitive.ObjectID `bson:"player_id,omitempty" json:"player_id,omitempty"`
+	Role     string              `bson:"role,omitempty" json:"role,omitempty"`
+}
+
+// GameDraftRequest represents request body for recording the draft of a game
+type GameDraftRequest struct {
+	Bans  []DraftBanRequest  `json:"bans"`
+	Picks []DraftPickRequest `json:"picks"`
+}
+
+// DraftBanRequest represents one ban in a draft request
+type DraftBanRequest struct {
+	Order  int    `json:"order" example:"1"`
+	TeamID string `json:"team_id" example:"687f9d7c8efa8f58af86646a"`
+	Hero   string `json:"hero" example:"Fanny"`
+}
+
+// DraftPickRequest represents one pick in a draft request
+type DraftPickRequest struct {
+	Order    int    `json:"order" example:"1"`
+	TeamID   string `json:"team_id" example:"687f9d7c8efa8f58af86646a"`
+	Hero     string `json:"hero" example:"Ling"`
+	PlayerID string `json:"player_id,omitempty" example:"687f9d7c8efa8f58af866470"`
+	Role     string `json:"role,omitempty" example:"jungle"`
+}
+
+// HeroStat represents pick/ban statistics of one hero in a tournament
+type HeroStat struct {
+	Hero     string  `json:"hero"`
+	Picks    int     `json:"picks"`
+	Bans     int     `json:"bans"`
+	Wins     int     `json:"wins"`
+	PickRate float64 `json:"pick_rate"`
+	BanRate  float64 `json:"ban_rate"`
+	Presence float64 `json:"presence"`
+	WinRate  float64 `json:"win_rate"`
+}
+
+// DraftStatsResponse represents hero pick/ban statistics of a tournament
+type DraftStatsResponse struct {
+	TournamentID string     `json:"tournament_id"`
+	TotalGames   int        `json:"total_games"`
+	Heroes       []HeroStat `json:"heroes"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeleteBlocker lists the documents of one collection that still reference a record being deleted
+type DeleteBlocker struct {
+	Collection string `json:"collection" example:"matches"`
+	Field      string `json:"field" example:"team_a_id"`
+	Count      int64  `json:"count" example:"3"`
+}
+
+// DeleteConflictResponse represents the response for a delete refused because of dependent records
+type DeleteConflictResponse struct {
+	Error    string          `json:"error" example:"delete_conflict"`
+	Message  string          `json:"message"`
+	Blockers []DeleteBlocker `json:"blockers"`
+}
+
+// ArchivedDocument keeps a copy of a document removed by a cascading delete
+type ArchivedDocument struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Collection string             `bson:"collection" json:"collection"`
+	DocumentID interface{}        `bson:"document_id" json:"document_id"`
+	Document   bson.Raw           `bson:"document" json:"document"`
+	Reason     string             `bson:"reason" json:"reason"`
+	ArchivedAt time.Time          `bson:"archived_at" json:"archived_at"`
+}
@@ -8,20 +8,41 @@ import (
 
 // Match represents a match entity
 type Match struct {
-	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
-	TournamentID     primitive.ObjectID  `bson:"tournament_id" json:"tournament_id"`
-	TeamAID          primitive.ObjectID  `bson:"team_a_id" json:"team_a_id"`
-	TeamBID          primitive.ObjectID  `bson:"team_b_id" json:"team_b_id"`
-	MatchDate        time.Time           `bson:"match_date" json:"match_date"`
-	MatchTime        string              `bson:"match_time" json:"match_time"`
-	Location         string              `bson:"location,omitempty" json:"location,omitempty"`
-	Round            string              `bson:"round" json:"round"`
-	ResultTeamAScore *int                `bson:"result_team_a_score,omitempty" json:"result_team_a_score"`
-	ResultTeamBScore *int                `bson:"result_team_b_score,omitempty" json:"result_team_b_score"`
-	WinnerTeamID     *primitive.ObjectID `bson:"winner_team_id,omitempty" json:"winner_team_id,omitempty"`
-	Status           string              `bson:"status" json:"status"`
-	CreatedAt        time.Time           `bson:"created_at" json:"created_at"`
-	UpdatedAt        time.Time           `bson:"updated_at" json:"updated_at"`
+	ID                 primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	TournamentID       primitive.ObjectID  `bson:"tournament_id" json:"tournament_id"`
+	TeamAID            primitive.ObjectID  `bson:"team_a_id" json:"team_a_id"`
+	TeamBID            primitive.ObjectID  `bson:"team_b_id" json:"team_b_id"`
+	MatchDate          time.Time           `bson:"match_date" json:"match_date"`
+	MatchTime          string              `bson:"match_time" json:"match_time"`
+	Location           string              `bson:"location,omitempty" json:"location,omitempty"`
+	Round              string              `bson:"round" json:"round"`
+	ResultTeamAScore   *int                `bson:"result_team_a_score,omitempty" json:"result_team_a_score"`
+	ResultTeamBScore   *int                `bson:"result_team_b_score,omitempty" json:"result_team_b_score"`
+	WinnerTeamID       *primitive.ObjectID `bson:"winner_team_id,omitempty" json:"winner_team_id,omitempty"`
+	Status             string              `bson:"status" json:"status"`
+	Outcome            string              `bson:"outcome,omitempty" json:"outcome,omitempty"`
+	OutcomeReason      string              `bson:"outcome_reason,omitempty" json:"outcome_reason,omitempty"`
+	ForfeitingTeamID   *primitive.ObjectID `bson:"forfeiting_team_id,omitempty" json:"forfeiting_team_id,omitempty"`
+	Overdue            bool                `bson:"overdue,omitempty" json:"overdue,omitempty"`
+	Bracket            string              `bson:"bracket,omitempty" json:"bracket,omitempty"`
+	BracketRound       int                 `bson:"bracket_round,omitempty" json:"bracket_round,omitempty"`
+	BracketPosition    int                 `bson:"bracket_position,omitempty" json:"bracket_position,omitempty"`
+	NextMatchID        *primitive.ObjectID `bson:"next_match_id,omitempty" json:"next_match_id,omitempty"`
+	NextMatchSlot      string              `bson:"next_match_slot,omitempty" json:"next_match_slot,omitempty"`
+	LoserNextMatchID   *primitive.ObjectID `bson:"loser_next_match_id,omitempty" json:"loser_next_match_id,omitempty"`
+	LoserNextMatchSlot string              `bson:"loser_next_match_slot,omitempty" json:"loser_next_match_slot,omitempty"`
+	IsBye              bool                `bson:"is_bye,omitempty" json:"is_bye,omitempty"`
+	Group              string              `bson:"group,omitempty" json:"group,omitempty"`
+	Matchday           int                 `bson:"matchday,omitempty" json:"matchday,omitempty"`
+	SwissRound         int                 `bson:"swiss_round,omitempty" json:"swiss_round,omitempty"`
+	BestOf             int                 `bson:"best_of,omitempty" json:"best_of,omitempty"`
+	Games              []MatchGame         `bson:"games,omitempty" json:"games,omitempty"`
+	StatusHistory      []MatchStatusChange `bson:"status_history,omitempty" json:"status_history,omitempty"`
+	CreatedAt          time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time           `bson:"updated_at" json:"updated_at"`
+	Version            int64               `bson:"version,omitempty" json:"version" example:"3"`
+	DeletedAt          *time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy          *primitive.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 }
 
 // MatchRequest represents request body for creating/updating match
@@ -36,31 +57,52 @@ type MatchRequest struct {
 	ResultTeamAScore *int      `json:"result_team_a_score,omitempty" example:"2"`
 	ResultTeamBScore *int      `json:"result_team_b_score,omitempty" example:"3"`
 	WinnerTeamID     string    `json:"winner_team_id,omitempty" example:"687f9d7c8efa8f58af86646b"`
-	Status           string    `json:"status" validate:"required,oneof=scheduled ongoing completed cancelled" example:"completed"`
+	BestOf           int       `json:"best_of,omitempty" validate:"omitempty,oneof=1 2 3 5 7" example:"3"`
+	Status           string    `json:"status,omitempty" validate:"omitempty,oneof=scheduled postponed" example:"scheduled"`
 }
 
 // MatchResponse represents response for match operations
 type MatchResponse struct {
 	Message string `json:"message"`
 	MatchID string `json:"match_id,omitempty"`
+	Version int64  `json:"version,omitempty"`
 }
 
 // MatchWithDetails represents match with populated team details
 type MatchWithDetails struct {
-	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
-	TournamentID     primitive.ObjectID  `bson:"tournament_id" json:"tournament_id"`
-	TeamAID          primitive.ObjectID  `bson:"team_a_id" json:"team_a_id"`
-	TeamBID          primitive.ObjectID  `bson:"team_b_id" json:"team_b_id"`
-	MatchDate        time.Time           `bson:"match_date" json:"match_date"`
-	MatchTime        string              `bson:"match_time" json:"match_time"`
-	Location         string              `bson:"location,omitempty" json:"location,omitempty"`
-	Round            string              `bson:"round" json:"round"`
-	ResultTeamAScore *int                `bson:"result_team_a_score,omitempty" json:"result_team_a_score"`
-	ResultTeamBScore *int                `bson:"result_team_b_score,omitempty" json:"result_team_b_score"`
-	WinnerTeamID     *primitive.ObjectID `bson:"winner_team_id,omitempty" json:"winner_team_id,omitempty"`
-	Status           string              `bson:"status" json:"status"`
-	CreatedAt        time.Time           `bson:"created_at" json:"created_at"`
-	UpdatedAt        time.Time           `bson:"updated_at" json:"updated_at"`
-	TeamA            *TeamBasicInfo      `json:"team_a,omitempty" bson:"team_a,omitempty"`
-	TeamB            *TeamBasicInfo      `json:"team_b,omitempty" bson:"team_b,omitempty"`
+	ID                 primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	TournamentID       primitive.ObjectID  `bson:"tournament_id" json:"tournament_id"`
+	TeamAID            primitive.ObjectID  `bson:"team_a_id" json:"team_a_id"`
+	TeamBID            primitive.ObjectID  `bson:"team_b_id" json:"team_b_id"`
+	MatchDate          time.Time           `bson:"match_date" json:"match_date"`
+	MatchTime          string              `bson:"match_time" json:"match_time"`
+	Location           string              `bson:"location,omitempty" json:"location,omitempty"`
+	Round              string              `bson:"round" json:"round"`
+	ResultTeamAScore   *int                `bson:"result_team_a_score,omitempty" json:"result_team_a_score"`
+	ResultTeamBScore   *int                `bson:"result_team_b_score,omitempty" json:"result_team_b_score"`
+	WinnerTeamID       *primitive.ObjectID `bson:"winner_team_id,omitempty" json:"winner_team_id,omitempty"`
+	Status             string              `bson:"status" json:"status"`
+	Outcome            string              `bson:"outcome,omitempty" json:"outcome,omitempty"`
+	OutcomeReason      string              `bson:"outcome_reason,omitempty" json:"outcome_reason,omitempty"`
+	ForfeitingTeamID   *primitive.ObjectID `bson:"forfeiting_team_id,omitempty" json:"forfeiting_team_id,omitempty"`
+	Overdue            bool                `bson:"overdue,omitempty" json:"overdue,omitempty"`
+	Bracket            string              `bson:"bracket,omitempty" json:"bracket,omitempty"`
+	BracketRound       int                 `bson:"bracket_round,omitempty" json:"bracket_round,omitempty"`
+	BracketPosition    int                 `bson:"bracket_position,omitempty" json:"bracket_position,omitempty"`
+	NextMatchID        *primitive.ObjectID `bson:"next_match_id,omitempty" json:"next_match_id,omitempty"`
+	NextMatchSlot      string              `bson:"next_match_slot,omitempty" json:"next_match_slot,omitempty"`
+	LoserNextMatchID   *primitive.ObjectID `bson:"loser_next_match_id,omitempty" json:"loser_next_match_id,omitempty"`
+	LoserNextMatchSlot string              `bson:"loser_next_match_slot,omitempty" json:"loser_next_match_slot,omitempty"`
+	IsBye              bool                `bson:"is_bye,omitempty" json:"is_bye,omitempty"`
+	Group              string              `bson:"group,omitempty" json:"group,omitempty"`
+	Matchday           int                 `bson:"matchday,omitempty" json:"matchday,omitempty"`
+	SwissRound         int                 `bson:"swiss_round,omitempty" json:"swiss_round,omitempty"`
+	BestOf             int                 `bson:"best_of,omitempty" json:"best_of,omitempty"`
+	Games              []MatchGame         `bson:"games,omitempty" json:"games,omitempty"`
+	StatusHistory      []MatchStatusChange `bson:"status_history,omitempty" json:"status_history,omitempty"`
+	CreatedAt          time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time           `bson:"updated_at" json:"updated_at"`
+	Version            int64               `bson:"version,omitempty" json:"version" example:"3"`
+	TeamA              *TeamBasicInfo      `json:"team_a,omitempty" bson:"team_a,omitempty"`
+	TeamB              *TeamBasicInfo      `json:"team_b,omitempty" bson:"team_b,omitempty"`
 }
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchGame represents a single game of a best-of-N series, embedded in a match
+type MatchGame struct {
+	ID              primitive.ObjectID  `bson:"_id" json:"_id"`
+	GameNumber      int                 `bson:"game_number" json:"game_number"`
+	WinnerTeamID    primitive.ObjectID  `bson:"winner_team_id" json:"winner_team_id"`
+	DurationSeconds int                 `bson:"duration_seconds,omitempty" json:"duration_seconds,omitempty"`
+	BlueTeamID      *primitive.ObjectID `bson:"blue_team_id,omitempty" json:"blue_team_id,omitempty"`
+	RedTeamID       *primitive.ObjectID `bson:"red_team_id,omitempty" json:"red_team_id,omitempty"`
+	MVPPlayerID     *primitive.ObjectID `bson:"mvp_player_id,omitempty" json:"mvp_player_id,omitempty"`
+	Draft           *GameDraft          `bson:"draft,omitempty" json:"draft,omitempty"`
+	Status          string              `bson:"status" json:"status"` // "valid" or "void"
+	VoidReason      string              `bson:"void_reason,omitempty" json:"void_reason,omitempty"`
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// MatchGameRequest represents request body for adding/editing a game of a series
+type MatchGameRequest struct {
+	WinnerTeamID    string `json:"winner_team_id" example:"687f9d7c8efa8f58af86646a"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" example:"1140"`
+	BlueTeamID      string `json:"blue_team_id,omitempty" example:"687f9d7c8efa8f58af86646a"`
+	RedTeamID       string `json:"red_team_id,omitempty" example:"687f9d7c8efa8f58af86646b"`
+	MVPPlayerID     string `json:"mvp_player_id,omitempty" example:"687f9d7c8efa8f58af866470"`
+}
+
+// VoidMatchGameRequest represents request body for voiding a game
+type VoidMatchGameRequest struct {
+	Reason string `json:"reason" example:"Remake karena disconnect"`
+}
+
+// MatchGameResponse represents response for game operations, including the derived series score
+type MatchGameResponse struct {
+	Message          string `json:"message"`
+	MatchID          string `json:"match_id"`
+	GameID           string `json:"game_id,omitempty"`
+	ResultTeamAScore int    `json:"result_team_a_score"`
+	ResultTeamBScore int    `json:"result_team_b_score"`
+	WinnerTeamID     string `json:"winner_team_id,omitempty"`
+}
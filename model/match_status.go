@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchStatusChange records one status transition of a match
+type MatchStatusChange struct {
+	From      string             `bson:"from" json:"from"`
+	To        string             `bson:"to" json:"to"`
+	ChangedBy primitive.ObjectID `bson:"changed_by" json:"changed_by"`
+	ChangedAt time.Time          `bson:"changed_at" json:"changed_at"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// MatchTransition carries the status a match moves to and the data applied with it
+type MatchTransition struct {
+	To               string
+	Reason           string
+	ChangedBy        primitive.ObjectID
+	ResultTeamAScore *int
+	ResultTeamBScore *int
+	WinnerTeamID     *primitive.ObjectID
+	Outcome          string
+	ForfeitingTeamID *primitive.ObjectID
+	MatchDate        time.Time
+	MatchTime        string
+}
+
+// MatchTransitionRequest represents request body for changing the status of a match
+type MatchTransitionRequest struct {
+	Reason           string    `json:"reason,omitempty" example:"Server issue, dijadwalkan ulang"`
+	ResultTeamAScore *int      `json:"result_team_a_score,omitempty" example:"2"`
+	ResultTeamBScore *int      `json:"result_team_b_score,omitempty" example:"1"`
+	WinnerTeamID     string    `json:"winner_team_id,omitempty" example:"687f9d7c8efa8f58af86646a"`
+	Outcome          string    `json:"outcome,omitempty" example:"walkover"`
+	ForfeitingTeamID string    `json:"forfeiting_team_id,omitempty" example:"687f9d7c8efa8f58af86646b"`
+	MatchDate        time.Time `json:"match_date,omitempty"`
+	MatchTime        string    `json:"match_time,omitempty" example:"19:00"`
+}
+
+// MatchTransitionResponse represents response for match status transitions
+type MatchTransitionResponse struct {
+	Message string `json:"message"`
+	MatchID string `json:"match_id"`
+	From    string `json:"from"`
+	Status  string `json:"status"`
+}
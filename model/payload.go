@@ -3,4 +3,4 @@ package model
 type Payload struct {
 	User string `json:"user"`
 	Role string `json:"role"`
-}
\ No newline at end of file
+}
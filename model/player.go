@@ -8,14 +8,17 @@ import (
 
 // Player represents a Mobile Legends player
 type Player struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
-	Name       string             `bson:"name" json:"name"`
-	MLNickname string             `bson:"ml_nickname" json:"ml_nickname"`
-	MLID       string             `bson:"ml_id" json:"ml_id"`
-	Status     string             `bson:"status" json:"status"`
-	AvatarURL  string             `bson:"avatar_url,omitempty" json:"avatar_url,omitempty"`
-	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	Name       string              `bson:"name" json:"name"`
+	MLNickname string              `bson:"ml_nickname" json:"ml_nickname"`
+	MLID       string              `bson:"ml_id" json:"ml_id"`
+	Status     string              `bson:"status" json:"status"`
+	AvatarURL  string              `bson:"avatar_url,omitempty" json:"avatar_url,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+	Version    int64               `bson:"version,omitempty" json:"version" example:"3"`
+	DeletedAt  *time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy  *primitive.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 }
 
 // PlayerRequest represents request body for creating/updating player
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Player link statuses
+const (
+	PlayerLinkPending  = "pending"
+	PlayerLinkVerified = "verified"
+	PlayerLinkRejected = "rejected"
+)
+
+// PlayerLink represents a user's request to be linked to a player record, verified by an admin
+type PlayerLink struct {
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID          primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	PlayerID        primitive.ObjectID  `bson:"player_id" json:"player_id"`
+	Status          string              `bson:"status" json:"status"`
+	RejectionReason string              `bson:"rejection_reason,omitempty" json:"rejection_reason,omitempty"`
+	ReviewedBy      *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`
+	Username        string              `bson:"username,omitempty" json:"username,omitempty"`
+	MLNickname      string              `bson:"ml_nickname,omitempty" json:"ml_nickname,omitempty"`
+	MLID            string              `bson:"ml_id,omitempty" json:"ml_id,omitempty"`
+}
+
+// PlayerLinkRequest represents request body for linking the logged-in user to a player
+type PlayerLinkRequest struct {
+	MLID string `json:"ml_id" validate:"required" example:"100001"`
+}
+
+// RejectPlayerLinkRequest represents request body for rejecting a player link
+type RejectPlayerLinkRequest struct {
+	Reason string `json:"reason" validate:"required" example:"ML ID tidak cocok dengan akun in-game"`
+}
+
+// PlayerLinkResponse represents response for player link operations
+type PlayerLinkResponse struct {
+	Message string `json:"message"`
+	LinkID  string `json:"link_id,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+// CaptainRosterRequest represents request body for a captain updating the team roster
+type CaptainRosterRequest struct {
+	Roster    []TeamMemberRequest `json:"roster"`
+	CaptainID string              `json:"captain_id,omitempty" example:"687f9d7c8efa8f58af866470"`
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlayerGameStat represents one player's stat line in one game of a match
+type PlayerGameStat struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	TournamentID primitive.ObjectID `bson:"tournament_id" json:"tournament_id"`
+	MatchID      primitive.ObjectID `bson:"match_id" json:"match_id"`
+	GameID       primitive.ObjectID `bson:"game_id" json:"game_id"`
+	TeamID       primitive.ObjectID `bson:"team_id" json:"team_id"`
+	PlayerID     primitive.ObjectID `bson:"player_id" json:"player_id"`
+	Hero         string             `bson:"hero,omitempty" json:"hero,omitempty"`
+	Kills        int                `bson:"kills" json:"kills"`
+	Deaths       int                `bson:"deaths" json:"deaths"`
+	Assists      int                `bson:"assists" json:"assists"`
+	Gold         int                `bson:"gold" json:"gold"`
+	HeroDamage   int                `bson:"hero_damage" json:"hero_damage"`
+	TurretDamage int                `bson:"turret_damage" json:"turret_damage"`
+	MVP          bool               `bson:"mvp" json:"mvp"`
+	Win          bool               `bson:"win" json:"win"`
+	Void         bool               `bson:"void,omitempty" json:"void,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// GameStatsRequest represents request body for submitting the stat lines of a game
+type GameStatsRequest struct {
+	Stats []PlayerStatLineRequest `json:"stats"`
+}
+
+// PlayerStatLineRequest represents one player's stat line in a stats request
+type PlayerStatLineRequest struct {
+	PlayerID     string `json:"player_id" example:"687f9d7c8efa8f58af866470"`
+	Hero         string `json:"hero,omitempty" example:"Ling"`
+	Kills        int    `json:"kills" example:"7"`
+	Deaths       int    `json:"deaths" example:"2"`
+	Assists      int    `json:"assists" example:"9"`
+	Gold         int    `json:"gold" example:"12450"`
+	HeroDamage   int    `json:"hero_damage" example:"85320"`
+	TurretDamage int    `json:"turret_damage" example:"4100"`
+	MVP          bool   `json:"mvp" example:"true"`
+}
+
+// PlayerStatsSummary represents aggregated stat totals and averages over a set of games
+type PlayerStatsSummary struct {
+	Games           int     `bson:"games" json:"games"`
+	Wins            int     `bson:"wins" json:"wins"`
+	MVPCount        int     `bson:"mvp_count" json:"mvp_count"`
+	Kills           int     `bson:"kills" json:"kills"`
+	Deaths          int     `bson:"deaths" json:"deaths"`
+	Assists         int     `bson:"assists" json:"assists"`
+	Gold            int     `bson:"gold" json:"gold"`
+	HeroDamage      int     `bson:"hero_damage" json:"hero_damage"`
+	TurretDamage    int     `bson:"turret_damage" json:"turret_damage"`
+	WinRate         float64 `bson:"-" json:"win_rate"`
+	KDA             float64 `bson:"-" json:"kda"`
+	AvgKills        float64 `bson:"-" json:"avg_kills"`
+	AvgDeaths       float64 `bson:"-" json:"avg_deaths"`
+	AvgAssists      float64 `bson:"-" json:"avg_assists"`
+	AvgGold         float64 `bson:"-" json:"avg_gold"`
+	AvgHeroDamage   float64 `bson:"-" json:"avg_hero_damage"`
+	AvgTurretDamage float64 `bson:"-" json:"avg_turret_damage"`
+}
+
+// TournamentPlayerStats represents a player's aggregated stats within one tournament
+type TournamentPlayerStats struct {
+	TournamentID       primitive.ObjectID `bson:"_id" json:"tournament_id"`
+	TournamentName     string             `bson:"tournament_name" json:"tournament_name"`
+	PlayerStatsSummary `bson:",inline"`
+}
+
+// PlayerStatsResponse represents a player's career and per-tournament stats
+type PlayerStatsResponse struct {
+	PlayerID    primitive.ObjectID      `json:"player_id"`
+	MLNickname  string                  `json:"ml_nickname"`
+	Career      PlayerStatsSummary      `json:"career"`
+	Tournaments []TournamentPlayerStats `json:"tournaments"`
+}
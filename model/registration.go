@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Registration statuses
+const (
+	RegistrationPending  = "pending"
+	RegistrationApproved = "approved"
+	RegistrationRejected = "rejected"
+)
+
+// RegistrationWindow represents the period and limits for teams applying to a tournament
+type RegistrationWindow struct {
+	OpensAt       time.Time `bson:"opens_at" json:"opens_at"`
+	ClosesAt      time.Time `bson:"closes_at" json:"closes_at"`
+	MaxTeams      int       `bson:"max_teams,omitempty" json:"max_teams,omitempty" example:"16"`
+	MinRosterSize int       `bson:"min_roster_size,omitempty" json:"min_roster_size,omitempty" example:"5"`
+}
+
+// TournamentRegistration represents a team's application to join a tournament
+type TournamentRegistration struct {
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	TournamentID    primitive.ObjectID  `bson:"tournament_id" json:"tournament_id"`
+	TeamID          primitive.ObjectID  `bson:"team_id" json:"team_id"`
+	AppliedBy       primitive.ObjectID  `bson:"applied_by" json:"applied_by"`
+	Status          string              `bson:"status" json:"status"`
+	RejectionReason string              `bson:"rejection_reason,omitempty" json:"rejection_reason,omitempty"`
+	ReviewedBy      *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`
+	Team            *TeamBasicInfo      `bson:"team,omitempty" json:"team,omitempty"`
+	Tournament      *TournamentBasic    `bson:"tournament,omitempty" json:"tournament,omitempty"`
+}
+
+// TournamentBasic represents minimal tournament info attached to other records
+type TournamentBasic struct {
+	ID   primitive.ObjectID `bson:"_id" json:"_id"`
+	Name string             `bson:"name" json:"name"`
+}
+
+// RegistrationRequest represents request body for a captain registering their team for a tournament
+type RegistrationRequest struct {
+	TournamentID string `json:"tournament_id" validate:"required" example:"687e5cd44643a58edf8210e8"`
+}
+
+// RejectRegistrationRequest represents request body for rejecting an application
+type RejectRegistrationRequest struct {
+	Reason string `json:"reason" validate:"required" example:"Roster belum memenuhi minimal 5 pemain"`
+}
+
+// RegistrationResponse represents response for registration operations
+type RegistrationResponse struct {
+	Message        string `json:"message"`
+	RegistrationID string `json:"registration_id,omitempty"`
+	Status         string `json:"status,omitempty"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RoleAssignment gives a user a staff role, everywhere or only for one tournament
+type RoleAssignment struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID       primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Role         string              `bson:"role" json:"role" example:"referee"`
+	TournamentID *primitive.ObjectID `bson:"tournament_id,omitempty" json:"tournament_id,omitempty" example:"687e5cd44643a58edf8210e8"`
+	GrantedBy    primitive.ObjectID  `bson:"granted_by" json:"granted_by"`
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// RoleAssignmentRequest represents request body for assigning a staff role. Without
+// tournament_id the role applies to every tournament
+type RoleAssignmentRequest struct {
+	UserID       string `json:"user_id" validate:"required" example:"64f123abc456def789012345"`
+	Role         string `json:"role" validate:"required" example:"referee"`
+	TournamentID string `json:"tournament_id,omitempty" example:"687e5cd44643a58edf8210e8"`
+}
+
+// RoleInfo describes a staff role and the permissions it grants
+type RoleInfo struct {
+	Role        string   `json:"role" example:"referee"`
+	Permissions []string `json:"permissions" example:"match:score"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Login throttle scopes: failures are counted per account (by email) and per client IP
+const (
+	ThrottleAccount = "account"
+	ThrottleIP      = "ip"
+)
+
+// LoginThrottle counts recent failed logins of one account or IP
+type LoginThrottle struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Scope         string             `bson:"scope" json:"scope" example:"account"`
+	Key           string             `bson:"key" json:"key" example:"user.example@example.com"`
+	Failures      int                `bson:"failures" json:"failures" example:"3"`
+	LastFailureAt time.Time          `bson:"last_failure_at" json:"last_failure_at"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil   *time.Time         `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+}
+
+// Security event types
+const (
+	SecurityLoginFailed       = "login_failed"
+	SecurityAccountLocked     = "account_locked"
+	SecurityIPLocked          = "ip_locked"
+	SecurityAccountUnlocked   = "account_unlocked"
+	SecurityRefreshTokenReuse = "refresh_token_reuse"
+)
+
+// SecurityEvent records suspicious or security relevant activity on an account. UserID is
+// empty for failed logins with an unknown email
+type SecurityEvent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Email     string             `bson:"email,omitempty" json:"email,omitempty" example:"user.example@example.com"`
+	Type      string             `bson:"type" json:"type" example:"login_failed"`
+	IP        string             `bson:"ip,omitempty" json:"ip,omitempty" example:"127.0.0.1"`
+	UserAgent string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Detail    string             `bson:"detail,omitempty" json:"detail,omitempty" example:"3 failed attempts"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// SecurityEventList represents a page of security events, newest first
+type SecurityEventList struct {
+	Total  int64           `json:"total" example:"12"`
+	Page   int             `json:"page" example:"1"`
+	Limit  int             `json:"limit" example:"50"`
+	Events []SecurityEvent `json:"events"`
+}
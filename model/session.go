@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session revocation reasons
+const (
+	SessionLogout           = "logout"
+	SessionLogoutAll        = "logout_all"
+	SessionTokenReuse       = "refresh_token_reuse"
+	SessionUserDeleted      = "user_deleted"
+	SessionPasswordReset    = "password_reset"
+	SessionTwoFactorEnabled = "two_factor_enabled"
+)
+
+// Session represents a login. Its refresh token rotates on every refresh; access tokens
+// carry the session ID so revoking the session cuts them off immediately
+type Session struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"`
+	RefreshTokenHash string             `bson:"refresh_token_hash" json:"-"`
+	UserAgent        string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	IP               string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	RefreshedAt      *time.Time         `bson:"refreshed_at,omitempty" json:"refreshed_at,omitempty"`
+	ExpiresAt        time.Time          `bson:"expires_at" json:"expires_at"`
+	RevokedAt        *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	RevokedReason    string             `bson:"revoked_reason,omitempty" json:"revoked_reason,omitempty"`
+}
+
+// RefreshRequest represents request body for refreshing an access token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" example:"687f9d7c8efa8f58af866470.9c1e..."`
+}
+
+// LogoutRequest represents request body for logging out. All ends every session of the user
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"687f9d7c8efa8f58af866470.9c1e..."`
+	All          bool   `json:"all,omitempty" example:"false"`
+}
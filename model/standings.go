@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GroupStage represents the group stage configuration stored on a tournament
+type GroupStage struct {
+	Groups           []TournamentGroup `bson:"groups" json:"groups"`
+	DoubleRoundRobin bool              `bson:"double_round_robin" json:"double_round_robin"`
+	PointsPerWin     int               `bson:"points_per_win" json:"points_per_win"`
+	PointsPerDraw    int               `bson:"points_per_draw" json:"points_per_draw"`
+	Tiebreakers      []string          `bson:"tiebreakers" json:"tiebreakers"`
+}
+
+// TournamentGroup represents one group of teams in a group stage
+type TournamentGroup struct {
+	Name    string               `bson:"name" json:"name"`
+	TeamIDs []primitive.ObjectID `bson:"team_ids" json:"team_ids"`
+}
+
+// GroupStageRequest represents request body for generating a group stage
+type GroupStageRequest struct {
+	GroupCount       int       `json:"group_count" example:"2"`
+	Seeding          string    `json:"seeding" example:"random"`
+	TeamIDs          []string  `json:"team_ids,omitempty" example:"687f9d7c8efa8f58af86646a,687f9d7c8efa8f58af86646b"`
+	DoubleRoundRobin bool      `json:"double_round_robin" example:"false"`
+	PointsPerWin     *int      `json:"points_per_win,omitempty" example:"3"`
+	PointsPerDraw    *int      `json:"points_per_draw,omitempty" example:"1"`
+	Tiebreakers      []string  `json:"tiebreakers,omitempty" example:"head_to_head,game_diff,games_won"`
+	MatchDate        time.Time `json:"match_date,omitempty"`
+	MatchTime        string    `json:"match_time,omitempty" example:"20:00"`
+	Location         string    `json:"location,omitempty" example:"Stadium XYZ"`
+}
+
+// GroupStageResponse represents response for group stage generation
+type GroupStageResponse struct {
+	Message      string            `json:"message"`
+	TournamentID string            `json:"tournament_id"`
+	Groups       []TournamentGroup `json:"groups"`
+	TotalMatches int               `json:"total_matches"`
+}
+
+// Standing represents one row of a group standings table
+type Standing struct {
+	Rank      int                `json:"rank"`
+	TeamID    primitive.ObjectID `json:"team_id"`
+	TeamName  string             `json:"team_name"`
+	LogoURL   string             `json:"logo_url,omitempty"`
+	Played    int                `json:"played"`
+	Wins      int                `json:"wins"`
+	Draws     int                `json:"draws"`
+	Losses    int                `json:"losses"`
+	GamesWon  int                `json:"games_won"`
+	GamesLost int                `json:"games_lost"`
+	GameDiff  int                `json:"game_diff"`
+	Points    int                `json:"points"`
+}
+
+// GroupStandings represents the standings table of one group
+type GroupStandings struct {
+	Group     string     `json:"group"`
+	Standings []Standing `json:"standings"`
+}
+
+// StandingsResponse represents the public standings of a tournament
+type StandingsResponse struct {
+	TournamentID string           `json:"tournament_id"`
+	Tiebreakers  []string         `json:"tiebreakers"`
+	Groups       []GroupStandings `json:"groups"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SwissStage represents the Swiss stage state stored on a tournament
+type SwissStage struct {
+	TotalRounds  int                  `bson:"total_rounds" json:"total_rounds"`
+	CurrentRound int                  `bson:"current_round" json:"current_round"`
+	Seeds        []primitive.ObjectID `bson:"seeds" json:"seeds"`
+}
+
+// SwissRoundRequest represents request body for generating the next Swiss round
+type SwissRoundRequest struct {
+	TotalRounds int       `json:"total_rounds,omitempty" example:"5"`
+	Seeding     string    `json:"seeding,omitempty" example:"random"`
+	TeamIDs     []string  `json:"team_ids,omitempty" example:"687f9d7c8efa8f58af86646a,687f9d7c8efa8f58af86646b"`
+	MatchDate   time.Time `json:"match_date,omitempty"`
+	MatchTime   string    `json:"match_time,omitempty" example:"20:00"`
+	Location    string    `json:"location,omitempty" example:"Stadium XYZ"`
+}
+
+// SwissRoundResponse represents response for Swiss round generation
+type SwissRoundResponse struct {
+	Message      string   `json:"message"`
+	TournamentID string   `json:"tournament_id"`
+	Round        int      `json:"round"`
+	TotalRounds  int      `json:"total_rounds"`
+	MatchIDs     []string `json:"match_ids"`
+	ByeTeamID    string   `json:"bye_team_id,omitempty"`
+}
+
+// SwissStanding represents one row of the Swiss ranking
+type SwissStanding struct {
+	Rank     int                `json:"rank"`
+	TeamID   primitive.ObjectID `json:"team_id"`
+	TeamName string             `json:"team_name"`
+	LogoURL  string             `json:"logo_url,omitempty"`
+	Wins     int                `json:"wins"`
+	Losses   int                `json:"losses"`
+	Byes     int                `json:"byes"`
+	Buchholz int                `json:"buchholz"`
+	GameDiff int                `json:"game_diff"`
+}
+
+// SwissStandingsResponse represents the public Swiss ranking of a tournament
+type SwissStandingsResponse struct {
+	TournamentID string          `json:"tournament_id"`
+	CurrentRound int             `json:"current_round"`
+	TotalRounds  int             `json:"total_rounds"`
+	Standings    []SwissStanding `json:"standings"`
+}
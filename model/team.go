@@ -12,17 +12,35 @@ type Team struct {
 	TeamName  string               `bson:"team_name" json:"team_name"`
 	CaptainID primitive.ObjectID   `bson:"captain_id" json:"captain_id"`
 	Members   []primitive.ObjectID `bson:"members" json:"members"`
+	Roster    []TeamMember         `bson:"roster,omitempty" json:"roster,omitempty"`
 	LogoURL   string               `bson:"logo_url,omitempty" json:"logo_url,omitempty"`
 	CreatedAt time.Time            `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time            `bson:"updated_at" json:"updated_at"`
+	Version   int64                `bson:"version,omitempty" json:"version" example:"3"`
+	DeletedAt *time.Time           `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy *primitive.ObjectID  `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+}
+
+// TeamMember represents a roster entry with the member's role (starter, substitute or coach).
+// Starters and substitutes are also listed in Team.Members; the coach is not
+type TeamMember struct {
+	PlayerID primitive.ObjectID `bson:"player_id" json:"player_id"`
+	Role     string             `bson:"role" json:"role" example:"starter"`
+}
+
+// TeamMemberRequest represents a roster entry in team requests
+type TeamMemberRequest struct {
+	PlayerID string `json:"player_id" example:"687f9d7c8efa8f58af86646a"`
+	Role     string `json:"role" example:"starter"`
 }
 
 // TeamRequest represents request body for creating/updating team
 type TeamRequest struct {
-	TeamName  string   `json:"team_name" bson:"team_name" example:"RRQ Hoshi"`
-	CaptainID string   `json:"captain_id" bson:"captain_id" example:"687f9d7c8efa8f58af86646a"`
-	Members   []string `json:"members" bson:"members" example:"687f9d7c8efa8f58af86646a,687f9d7c8efa8f58af86646b"`
-	LogoURL   string   `json:"logo_url,omitempty" bson:"logo_url,omitempty" example:"https://example.com/rrq_logo.png"`
+	TeamName  string              `json:"team_name" bson:"team_name" example:"RRQ Hoshi"`
+	CaptainID string              `json:"captain_id" bson:"captain_id" example:"687f9d7c8efa8f58af86646a"`
+	Members   []string            `json:"members" bson:"members" example:"687f9d7c8efa8f58af86646a,687f9d7c8efa8f58af86646b"`
+	Roster    []TeamMemberRequest `json:"roster,omitempty" bson:"roster,omitempty"`
+	LogoURL   string              `json:"logo_url,omitempty" bson:"logo_url,omitempty" example:"https://example.com/rrq_logo.png"`
 }
 
 // TeamResponse represents response for team operations
@@ -37,9 +55,11 @@ type TeamWithDetails struct {
 	TeamName       string               `bson:"team_name" json:"team_name"`
 	CaptainID      primitive.ObjectID   `bson:"captain_id" json:"captain_id"`
 	Members        []primitive.ObjectID `bson:"members" json:"members"`
+	Roster         []TeamMember         `bson:"roster,omitempty" json:"roster,omitempty"`
 	LogoURL        string               `bson:"logo_url,omitempty" json:"logo_url,omitempty"`
 	CreatedAt      time.Time            `bson:"created_at" json:"created_at"`
 	UpdatedAt      time.Time            `bson:"updated_at" json:"updated_at"`
+	Version        int64                `bson:"version,omitempty" json:"version" example:"3"`
 	CaptainDetails *PlayerDetails       `json:"captain_details,omitempty" bson:"captain_details,omitempty"`
 	MembersDetails []PlayerDetails      `json:"members_details,omitempty" bson:"members_details,omitempty"`
 }
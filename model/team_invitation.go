@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Team invitation kinds: a captain inviting a player, or a player asking to join a team
+const (
+	InvitationKindInvite      = "invite"
+	InvitationKindJoinRequest = "join_request"
+)
+
+// Team invitation statuses
+const (
+	InvitationPending   = "pending"
+	InvitationAccepted  = "accepted"
+	InvitationDeclined  = "declined"
+	InvitationCancelled = "cancelled"
+	InvitationExpired   = "expired"
+)
+
+// TeamInvitation represents an invitation from a team to a player or a player's request to join a team
+type TeamInvitation struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	Kind        string              `bson:"kind" json:"kind" example:"invite"`
+	TeamID      primitive.ObjectID  `bson:"team_id" json:"team_id"`
+	PlayerID    primitive.ObjectID  `bson:"player_id" json:"player_id"`
+	Status      string              `bson:"status" json:"status" example:"pending"`
+	Role        string              `bson:"role,omitempty" json:"role,omitempty" example:"substitute"`
+	Message     string              `bson:"message,omitempty" json:"message,omitempty"`
+	CreatedBy   primitive.ObjectID  `bson:"created_by" json:"created_by"`
+	RespondedBy *primitive.ObjectID `bson:"responded_by,omitempty" json:"responded_by,omitempty"`
+	RespondedAt *time.Time          `bson:"responded_at,omitempty" json:"responded_at,omitempty"`
+	ExpiresAt   time.Time           `bson:"expires_at" json:"expires_at"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+	Team        *TeamBasicInfo      `bson:"team,omitempty" json:"team,omitempty"`
+	Player      *PlayerBasicInfo    `bson:"player,omitempty" json:"player,omitempty"`
+}
+
+// PlayerBasicInfo represents minimal player info
+type PlayerBasicInfo struct {
+	ID         primitive.ObjectID `bson:"_id" json:"_id"`
+	MLNickname string             `bson:"ml_nickname" json:"ml_nickname"`
+	MLID       string             `bson:"ml_id" json:"ml_id"`
+	AvatarURL  string             `bson:"avatar_url,omitempty" json:"avatar_url,omitempty"`
+}
+
+// TeamInvitationRequest represents request body for a captain inviting a player, by ML ID or linked user ID
+type TeamInvitationRequest struct {
+	MLID    string `json:"ml_id,omitempty" example:"100001"`
+	UserID  string `json:"user_id,omitempty" example:"687f9d7c8efa8f58af866480"`
+	Role    string `json:"role,omitempty" example:"substitute"`
+	Message string `json:"message,omitempty" example:"Kami butuh jungler untuk MPL season depan"`
+}
+
+// JoinRequestRequest represents request body for a player asking to join a team
+type JoinRequestRequest struct {
+	Message string `json:"message,omitempty" example:"Saya main roamer, rank Mythical Glory"`
+}
+
+// TeamInvitationResponse represents response for team invitation operations
+type TeamInvitationResponse struct {
+	Message      string `json:"message"`
+	InvitationID string `json:"invitation_id,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
@@ -8,62 +8,86 @@ import (
 
 // Tournament represents a tournament entity
 type Tournament struct {
-	ID                 primitive.ObjectID   `bson:"_id,omitempty" json:"_id,omitempty"`
-	Name               string               `bson:"name" json:"name"`
-	Description        string               `bson:"description" json:"description"`
-	StartDate          time.Time            `bson:"start_date" json:"start_date"`
-	EndDate            time.Time            `bson:"end_date" json:"end_date"`
-	PrizePool          string               `bson:"prize_pool" json:"prize_pool"`
-	RulesDocumentURL   string               `bson:"rules_document_url,omitempty" json:"rules_document_url,omitempty"`
-	Status             string               `bson:"status" json:"status"`
-	TeamsParticipating []primitive.ObjectID `bson:"teams_participating" json:"teams_participating"`
-	CreatedBy          primitive.ObjectID   `bson:"created_by" json:"created_by"`
-	CreatedAt          time.Time            `bson:"created_at" json:"created_at"`
-	UpdatedAt          time.Time            `bson:"updated_at" json:"updated_at"`
+	ID                 primitive.ObjectID     `bson:"_id,omitempty" json:"_id,omitempty"`
+	Name               string                 `bson:"name" json:"name"`
+	Description        string                 `bson:"description" json:"description"`
+	StartDate          time.Time              `bson:"start_date" json:"start_date"`
+	EndDate            time.Time              `bson:"end_date" json:"end_date"`
+	PrizePool          string                 `bson:"prize_pool" json:"prize_pool"`
+	RulesDocumentURL   string                 `bson:"rules_document_url,omitempty" json:"rules_document_url,omitempty"`
+	Status             string                 `bson:"status" json:"status"`
+	BracketFormat      string                 `bson:"bracket_format,omitempty" json:"bracket_format,omitempty"`
+	BracketReset       bool                   `bson:"bracket_reset,omitempty" json:"bracket_reset,omitempty"`
+	GroupStage         *GroupStage            `bson:"group_stage,omitempty" json:"group_stage,omitempty"`
+	Swiss              *SwissStage            `bson:"swiss,omitempty" json:"swiss,omitempty"`
+	Disqualifications  []TeamDisqualification `bson:"disqualifications,omitempty" json:"disqualifications,omitempty"`
+	Registration       *RegistrationWindow    `bson:"registration,omitempty" json:"registration,omitempty"`
+	RostersLockedAt    *time.Time             `bson:"rosters_locked_at,omitempty" json:"rosters_locked_at,omitempty"`
+	TeamsParticipating []primitive.ObjectID   `bson:"teams_participating" json:"teams_participating"`
+	CreatedBy          primitive.ObjectID     `bson:"created_by" json:"created_by"`
+	CreatedAt          time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time              `bson:"updated_at" json:"updated_at"`
+	Version            int64                  `bson:"version,omitempty" json:"version" example:"3"`
+	DeletedAt          *time.Time             `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy          *primitive.ObjectID    `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 }
 
 // TournamentRequest represents request body for creating/updating tournament
 type TournamentRequest struct {
-	Name               string    `json:"name" validate:"required"`
-	Description        string    `json:"description" validate:"required"`
-	StartDate          time.Time `json:"start_date" validate:"required"`
-	EndDate            time.Time `json:"end_date" validate:"required"`
-	PrizePool          string    `json:"prize_pool" validate:"required"`
-	RulesDocumentURL   string    `json:"rules_document_url,omitempty"`
-	Status             string    `json:"status" validate:"required,oneof=upcoming ongoing completed"`
-	TeamsParticipating []string  `json:"teams_participating,omitempty"`
+	Name               string              `json:"name" validate:"required"`
+	Description        string              `json:"description" validate:"required"`
+	StartDate          time.Time           `json:"start_date" validate:"required"`
+	EndDate            time.Time           `json:"end_date" validate:"required"`
+	PrizePool          string              `json:"prize_pool" validate:"required"`
+	RulesDocumentURL   string              `json:"rules_document_url,omitempty"`
+	Status             string              `json:"status" validate:"required,oneof=upcoming ongoing completed"`
+	BracketFormat      string              `json:"bracket_format,omitempty" validate:"omitempty,oneof=single_elimination double_elimination" example:"double_elimination"`
+	BracketReset       *bool               `json:"bracket_reset,omitempty" example:"true"`
+	TeamsParticipating []string            `json:"teams_participating,omitempty"`
+	Registration       *RegistrationWindow `json:"registration,omitempty"`
 }
 
 // TournamentResponse represents response for tournament operations
 type TournamentResponse struct {
 	Message      string `json:"message"`
 	TournamentID string `json:"tournament_id,omitempty"`
+	Version      int64  `json:"version,omitempty"`
 }
 
 // TournamentPublic represents tournament data for public access (without admin fields)
 type TournamentPublic struct {
-	ID               primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
-	Name             string             `bson:"name" json:"name"`
-	Description      string             `bson:"description" json:"description"`
-	StartDate        time.Time          `bson:"start_date" json:"start_date"`
-	EndDate          time.Time          `bson:"end_date" json:"end_date"`
-	PrizePool        string             `bson:"prize_pool" json:"prize_pool"`
-	RulesDocumentURL string             `bson:"rules_document_url,omitempty" json:"rules_document_url,omitempty"`
-	Status           string             `bson:"status" json:"status"`
+	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	Name             string              `bson:"name" json:"name"`
+	Description      string              `bson:"description" json:"description"`
+	StartDate        time.Time           `bson:"start_date" json:"start_date"`
+	EndDate          time.Time           `bson:"end_date" json:"end_date"`
+	PrizePool        string              `bson:"prize_pool" json:"prize_pool"`
+	RulesDocumentURL string              `bson:"rules_document_url,omitempty" json:"rules_document_url,omitempty"`
+	Status           string              `bson:"status" json:"status"`
+	Registration     *RegistrationWindow `bson:"registration,omitempty" json:"registration,omitempty"`
+	RostersLockedAt  *time.Time          `bson:"rosters_locked_at,omitempty" json:"rosters_locked_at,omitempty"`
+	Version          int64               `bson:"version,omitempty" json:"version" example:"3"`
 }
 
 // TournamentWithDetails represents tournament with populated teams and matches
 type TournamentWithDetails struct {
-	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
-	Name               string             `bson:"name" json:"name"`
-	Description        string             `bson:"description" json:"description"`
-	StartDate          time.Time          `bson:"start_date" json:"start_date"`
-	EndDate            time.Time          `bson:"end_date" json:"end_date"`
-	PrizePool          string             `bson:"prize_pool" json:"prize_pool"`
-	RulesDocumentURL   string             `bson:"rules_document_url,omitempty" json:"rules_document_url,omitempty"`
-	Status             string             `bson:"status" json:"status"`
-	TeamsParticipating []TeamBasicInfo    `bson:"teams_participating,omitempty" json:"teams_participating"`
-	Matches            []MatchBasicInfo   `bson:"matches,omitempty" json:"matches"`
+	ID                 primitive.ObjectID     `bson:"_id,omitempty" json:"_id,omitempty"`
+	Name               string                 `bson:"name" json:"name"`
+	Description        string                 `bson:"description" json:"description"`
+	StartDate          time.Time              `bson:"start_date" json:"start_date"`
+	EndDate            time.Time              `bson:"end_date" json:"end_date"`
+	PrizePool          string                 `bson:"prize_pool" json:"prize_pool"`
+	RulesDocumentURL   string                 `bson:"rules_document_url,omitempty" json:"rules_document_url,omitempty"`
+	Status             string                 `bson:"status" json:"status"`
+	BracketFormat      string                 `bson:"bracket_format,omitempty" json:"bracket_format,omitempty"`
+	BracketReset       bool                   `bson:"bracket_reset,omitempty" json:"bracket_reset,omitempty"`
+	Disqualifications  []TeamDisqualification `bson:"disqualifications,omitempty" json:"disqualifications,omitempty"`
+	Registration       *RegistrationWindow    `bson:"registration,omitempty" json:"registration,omitempty"`
+	RostersLockedAt    *time.Time             `bson:"rosters_locked_at,omitempty" json:"rosters_locked_at,omitempty"`
+	Version            int64                  `bson:"version,omitempty" json:"version" example:"3"`
+	TeamsParticipating []TeamBasicInfo        `bson:"teams_participating,omitempty" json:"teams_participating"`
+	Matches            []MatchBasicInfo       `bson:"matches,omitempty" json:"matches"`
+	Bracket            *BracketView           `bson:"-" json:"bracket,omitempty"`
 }
 
 // TeamBasicInfo represents minimal team info for tournament details
@@ -75,15 +99,26 @@ type TeamBasicInfo struct {
 
 // MatchBasicInfo represents minimal match info for tournament details
 type MatchBasicInfo struct {
-	ID               primitive.ObjectID  `bson:"_id" json:"_id"`
-	MatchDate        time.Time           `bson:"match_date" json:"match_date"`
-	MatchTime        string              `bson:"match_time" json:"match_time"`
-	Location         string              `bson:"location" json:"location"`
-	Round            string              `bson:"round" json:"round"`
-	TeamA            TeamBasicInfo       `bson:"team_a" json:"team_a"`
-	TeamB            TeamBasicInfo       `bson:"team_b" json:"team_b"`
-	ResultTeamAScore *int                `bson:"result_team_a_score,omitempty" json:"result_team_a_score"`
-	ResultTeamBScore *int                `bson:"result_team_b_score,omitempty" json:"result_team_b_score"`
-	WinnerTeamID     *primitive.ObjectID `bson:"winner_team_id,omitempty" json:"winner_team_id"`
-	Status           string              `bson:"status" json:"status"`
+	ID                 primitive.ObjectID  `bson:"_id" json:"_id"`
+	MatchDate          time.Time           `bson:"match_date" json:"match_date"`
+	MatchTime          string              `bson:"match_time" json:"match_time"`
+	Location           string              `bson:"location" json:"location"`
+	Round              string              `bson:"round" json:"round"`
+	TeamA              TeamBasicInfo       `bson:"team_a" json:"team_a"`
+	TeamB              TeamBasicInfo       `bson:"team_b" json:"team_b"`
+	ResultTeamAScore   *int                `bson:"result_team_a_score,omitempty" json:"result_team_a_score"`
+	ResultTeamBScore   *int                `bson:"result_team_b_score,omitempty" json:"result_team_b_score"`
+	WinnerTeamID       *primitive.ObjectID `bson:"winner_team_id,omitempty" json:"winner_team_id"`
+	Status             string              `bson:"status" json:"status"`
+	Outcome            string              `bson:"outcome,omitempty" json:"outcome,omitempty"`
+	ForfeitingTeamID   *primitive.ObjectID `bson:"forfeiting_team_id,omitempty" json:"forfeiting_team_id,omitempty"`
+	Overdue            bool                `bson:"overdue,omitempty" json:"overdue,omitempty"`
+	Bracket            string              `bson:"bracket,omitempty" json:"bracket,omitempty"`
+	BracketRound       int                 `bson:"bracket_round,omitempty" json:"bracket_round,omitempty"`
+	BracketPosition    int                 `bson:"bracket_position,omitempty" json:"bracket_position,omitempty"`
+	NextMatchID        *primitive.ObjectID `bson:"next_match_id,omitempty" json:"next_match_id,omitempty"`
+	NextMatchSlot      string              `bson:"next_match_slot,omitempty" json:"next_match_slot,omitempty"`
+	LoserNextMatchID   *primitive.ObjectID `bson:"loser_next_match_id,omitempty" json:"loser_next_match_id,omitempty"`
+	LoserNextMatchSlot string              `bson:"loser_next_match_slot,omitempty" json:"loser_next_match_slot,omitempty"`
+	IsBye              bool                `bson:"is_bye,omitempty" json:"is_bye,omitempty"`
 }
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TournamentRoster represents the roster a team plays a tournament with. It is snapshotted
+// when the tournament starts and never rewritten, so later roster changes of the team do
+// not affect past matches
+type TournamentRoster struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	TournamentID primitive.ObjectID  `bson:"tournament_id" json:"tournament_id"`
+	TeamID       primitive.ObjectID  `bson:"team_id" json:"team_id"`
+	CaptainID    primitive.ObjectID  `bson:"captain_id" json:"captain_id"`
+	Roster       []TeamMember        `bson:"roster" json:"roster"`
+	Locked       bool                `bson:"-" json:"locked"`
+	LockedBy     *primitive.ObjectID `bson:"locked_by,omitempty" json:"locked_by,omitempty"`
+	LockedAt     *time.Time          `bson:"locked_at,omitempty" json:"locked_at,omitempty"`
+	Team         *TeamBasicInfo      `bson:"-" json:"team,omitempty"`
+}
+
+// LockRostersResponse represents response for locking the rosters of a tournament
+type LockRostersResponse struct {
+	Message      string `json:"message"`
+	TournamentID string `json:"tournament_id"`
+	LockedTeams  int    `json:"locked_teams"`
+}
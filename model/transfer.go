@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Transfer kinds
+const (
+	TransferJoin    = "join"
+	TransferMove    = "transfer"
+	TransferRelease = "release"
+)
+
+// TeamMembership represents a period a player spent on a team roster in one role.
+// A role change within the team closes the period and opens a new one
+type TeamMembership struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	TeamID   primitive.ObjectID `bson:"team_id" json:"team_id"`
+	PlayerID primitive.ObjectID `bson:"player_id" json:"player_id"`
+	Role     string             `bson:"role" json:"role" example:"starter"`
+	JoinedAt time.Time          `bson:"joined_at" json:"joined_at"`
+	LeftAt   *time.Time         `bson:"left_at,omitempty" json:"left_at,omitempty"`
+	Team     *TeamBasicInfo     `bson:"team,omitempty" json:"team,omitempty"`
+	Player   *PlayerBasicInfo   `bson:"player,omitempty" json:"player,omitempty"`
+}
+
+// Transfer represents a player joining, moving between or leaving teams, derived from memberships
+type Transfer struct {
+	Kind     string           `json:"kind" example:"transfer"`
+	PlayerID string           `json:"player_id"`
+	Player   *PlayerBasicInfo `json:"player,omitempty"`
+	FromTeam *TeamBasicInfo   `json:"from_team,omitempty"`
+	ToTeam   *TeamBasicInfo   `json:"to_team,omitempty"`
+	Role     string           `json:"role,omitempty" example:"starter"`
+	Date     time.Time        `json:"date"`
+}
+
+// PlayerTransferHistory represents the membership periods and transfers of a player
+type PlayerTransferHistory struct {
+	PlayerID    string           `json:"player_id"`
+	Memberships []TeamMembership `json:"memberships"`
+	Transfers   []Transfer       `json:"transfers"`
+}
+
+// TeamTransferHistory represents the membership periods of a team and the transfers in and out of it
+type TeamTransferHistory struct {
+	TeamID      string           `json:"team_id"`
+	Memberships []TeamMembership `json:"memberships"`
+	Transfers   []Transfer       `json:"transfers"`
+}
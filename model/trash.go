@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Trash types, one per soft deletable entity
+const (
+	TrashUser       = "user"
+	TrashPlayer     = "player"
+	TrashTeam       = "team"
+	TrashTournament = "tournament"
+	TrashMatch      = "match"
+)
+
+// TrashItem represents a soft deleted record in the admin trash listing
+type TrashItem struct {
+	Type      string              `bson:"type" json:"type" example:"team"`
+	ID        primitive.ObjectID  `bson:"_id" json:"_id"`
+	Name      string              `bson:"name" json:"name" example:"RRQ Hoshi"`
+	DeletedAt time.Time           `bson:"deleted_at" json:"deleted_at"`
+	DeletedBy *primitive.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+}
+
+// TrashResponse represents response for trash operations
+type TrashResponse struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+}
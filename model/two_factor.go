@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// TwoFactor holds the TOTP enrollment of a user. A pending secret becomes the active one once
+// the user confirms it with a code; recovery codes are stored hashed and removed when used
+type TwoFactor struct {
+	Enabled            bool       `bson:"enabled" json:"enabled"`
+	Secret             string     `bson:"secret,omitempty" json:"-"`
+	PendingSecret      string     `bson:"pending_secret,omitempty" json:"-"`
+	EnabledAt          *time.Time `bson:"enabled_at,omitempty" json:"enabled_at,omitempty"`
+	LastUsedStep       int64      `bson:"last_used_step,omitempty" json:"-"`
+	RecoveryCodeHashes []string   `bson:"recovery_codes,omitempty" json:"-"`
+}
+
+// TwoFactorSetupResponse represents the secret to add to an authenticator app
+type TwoFactorSetupResponse struct {
+	Message         string `json:"message" example:"Scan the QR code, then confirm with a code"`
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP" description:"Secret TOTP untuk dimasukkan manual"`
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/Embeck:admin@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Embeck" description:"URI otpauth untuk ditampilkan sebagai QR code"`
+}
+
+// TwoFactorCodeRequest represents a TOTP code or, instead, one of the recovery codes
+type TwoFactorCodeRequest struct {
+	Code         string `json:"code,omitempty" example:"123456" description:"Kode 6 digit dari aplikasi authenticator"`
+	RecoveryCode string `json:"recovery_code,omitempty" example:"3f9a1-c04be" description:"Recovery code sekali pakai"`
+}
+
+// TwoFactorLoginRequest represents the second login step
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" example:"9c1e0f..." description:"Challenge token dari respons login"`
+	Code           string `json:"code,omitempty" example:"123456" description:"Kode 6 digit dari aplikasi authenticator"`
+	RecoveryCode   string `json:"recovery_code,omitempty" example:"3f9a1-c04be" description:"Recovery code sekali pakai"`
+}
+
+// RecoveryCodesResponse represents newly generated recovery codes, shown only once
+type RecoveryCodesResponse struct {
+	Message       string   `json:"message" example:"Two-factor authentication enabled"`
+	RecoveryCodes []string `json:"recovery_codes" example:"3f9a1-c04be,8d2e7-51a0f"`
+}
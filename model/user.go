@@ -8,13 +8,19 @@ import (
 
 // User represents a user entity
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty" example:"64f123abc456def789012345" description:"ID unik user"`
-	Username  string             `bson:"username" json:"username" example:"userbaru123" description:"Nama pengguna untuk login"`
-	Email     string             `bson:"email" json:"email" example:"user.example@example.com" description:"Alamat email user"`
-	Password  string             `bson:"password" json:"-" description:"Password yang telah di-hash (tidak ditampilkan di response)"`
-	Role      string             `bson:"role" json:"role" example:"user" description:"Peran user: admin atau user"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at" example:"2025-07-16T07:28:37.016Z" description:"Waktu pembuatan user"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at" example:"2025-07-16T07:28:37.016Z" description:"Waktu terakhir diupdate"`
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty" example:"64f123abc456def789012345" description:"ID unik user"`
+	Username        string              `bson:"username" json:"username" example:"userbaru123" description:"Nama pengguna untuk login"`
+	Email           string              `bson:"email" json:"email" example:"user.example@example.com" description:"Alamat email user"`
+	Password        string              `bson:"password" json:"-" description:"Password yang telah di-hash (tidak ditampilkan di response)"`
+	Role            string              `bson:"role" json:"role" example:"user" description:"Peran user: admin atau user"`
+	PlayerID        *primitive.ObjectID `bson:"player_id,omitempty" json:"player_id,omitempty" example:"687f9d7c8efa8f58af866470" description:"Player yang terverifikasi milik user"`
+	EmailVerifiedAt *time.Time          `bson:"email_verified_at,omitempty" json:"email_verified_at,omitempty" description:"Waktu email diverifikasi"`
+	TwoFactor       *TwoFactor          `bson:"two_factor,omitempty" json:"-" description:"Enrollment 2FA (TOTP) user"`
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at" example:"2025-07-16T07:28:37.016Z" description:"Waktu pembuatan user"`
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at" example:"2025-07-16T07:28:37.016Z" description:"Waktu terakhir diupdate"`
+	Version         int64               `bson:"version,omitempty" json:"version" example:"3" description:"Versi data untuk If-Match; naik setiap kali user diubah"`
+	DeletedAt       *time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty" description:"Waktu user dipindahkan ke trash"`
+	DeletedBy       *primitive.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitempty" description:"Admin yang memindahkan user ke trash"`
 }
 
 // RegisterRequest represents request body for user registration
@@ -39,12 +45,17 @@ type UpdateUserRequest struct {
 
 // AuthResponse represents response for authentication operations
 type AuthResponse struct {
-	Message  string `json:"message" example:"Login successful" description:"Pesan konfirmasi"`
-	Token    string `json:"token,omitempty" example:"v2.local.xxx" description:"PASETO token untuk autentikasi"`
-	Role     string `json:"role,omitempty" example:"user" description:"Peran user"`
-	UserID   string `json:"user_id,omitempty" example:"64f123abc456def789012345" description:"ID user yang login"`
-	Username string `json:"username,omitempty" example:"userbaru123" description:"Nama pengguna"`
-	Email    string `json:"email,omitempty" example:"user.example@example.com" description:"Alamat email user"`
+	Message                string `json:"message" example:"Login successful" description:"Pesan konfirmasi"`
+	Token                  string `json:"token,omitempty" example:"v2.local.xxx" description:"PASETO token untuk autentikasi"`
+	Role                   string `json:"role,omitempty" example:"user" description:"Peran user"`
+	UserID                 string `json:"user_id,omitempty" example:"64f123abc456def789012345" description:"ID user yang login"`
+	Username               string `json:"username,omitempty" example:"userbaru123" description:"Nama pengguna"`
+	Email                  string `json:"email,omitempty" example:"user.example@example.com" description:"Alamat email user"`
+	RefreshToken           string `json:"refresh_token,omitempty" example:"687f9d7c8efa8f58af866470.9c1e..." description:"Refresh token untuk mendapatkan access token baru"`
+	ExpiresIn              int64  `json:"expires_in,omitempty" example:"900" description:"Masa berlaku access token dalam detik"`
+	TwoFactorRequired      bool   `json:"two_factor_required,omitempty" example:"false" description:"Login harus dilanjutkan ke /api/auth/login/2fa dengan challenge_token"`
+	ChallengeToken         string `json:"challenge_token,omitempty" example:"9c1e0f..." description:"Token untuk langkah kedua login"`
+	TwoFactorSetupRequired bool   `json:"two_factor_setup_required,omitempty" example:"false" description:"Admin wajib mengaktifkan 2FA sebelum memakai endpoint admin"`
 }
 
 // UserResponse represents response for user operations (without sensitive data)
@@ -62,20 +73,26 @@ type UsersListResponse struct {
 
 // UserProfile represents user profile data (without password)
 type UserProfile struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" example:"64f123abc456def789012345"`
-	Username  string             `json:"username" example:"userbaru123"`
-	Email     string             `json:"email" example:"user.example@example.com"`
-	Role      string             `json:"role" example:"user"`
-	CreatedAt time.Time          `json:"created_at" example:"2025-07-16T07:28:37.016Z"`
-	UpdatedAt time.Time          `json:"updated_at" example:"2025-07-16T07:28:37.016Z"`
+	ID               primitive.ObjectID  `json:"_id,omitempty" example:"64f123abc456def789012345"`
+	Username         string              `json:"username" example:"userbaru123"`
+	Email            string              `json:"email" example:"user.example@example.com"`
+	Role             string              `json:"role" example:"user"`
+	PlayerID         *primitive.ObjectID `json:"player_id,omitempty" example:"687f9d7c8efa8f58af866470"`
+	EmailVerified    bool                `json:"email_verified" example:"true"`
+	TwoFactorEnabled bool                `json:"two_factor_enabled" example:"false"`
+	LockedUntil      *time.Time          `json:"locked_until,omitempty"`
+	CreatedAt        time.Time           `json:"created_at" example:"2025-07-16T07:28:37.016Z"`
+	UpdatedAt        time.Time           `json:"updated_at" example:"2025-07-16T07:28:37.016Z"`
+	Version          int64               `json:"version" example:"3"`
 }
 
 // TokenClaims represents the claims stored in PASETO token
 type TokenClaims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
-	IssuedAt int64  `json:"iat"`
-	ExpireAt int64  `json:"exp"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpireAt  int64  `json:"exp"`
 }
@@ -8,11 +8,13 @@ import (
 
 // UserTicket represents a ticket purchased by a user for a specific match.
 type UserTicket struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
-	UserID       primitive.ObjectID `bson:"user_id" json:"user_id"`
-	MatchID      primitive.ObjectID `bson:"match_id" json:"match_id"`
-	PurchaseDate time.Time          `bson:"purchase_date" json:"purchase_date"`
-	Status       string             `bson:"status" json:"status"` // e.g., "valid", "used"
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID       primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	MatchID      primitive.ObjectID  `bson:"match_id" json:"match_id"`
+	PurchaseDate time.Time           `bson:"purchase_date" json:"purchase_date"`
+	Status       string              `bson:"status" json:"status"` // e.g., "valid", "used"
+	ScannedAt    *time.Time          `bson:"scanned_at,omitempty" json:"scanned_at,omitempty"`
+	ScannedBy    *primitive.ObjectID `bson:"scanned_by,omitempty" json:"scanned_by,omitempty"`
 }
 
 // UserTicketRequest represents the request body for purchasing a ticket.
@@ -29,3 +31,18 @@ type UserTicketResponse struct {
 	Status       string             `json:"status" bson:"status"`
 	MatchDetails *MatchBasicInfo    `json:"match_details,omitempty" bson:"match_details,omitempty"`
 }
+
+// MatchTicketSales counts the tickets sold for one match and how many were scanned at the gate
+type MatchTicketSales struct {
+	MatchID primitive.ObjectID `bson:"_id" json:"match_id"`
+	Sold    int                `bson:"sold" json:"sold"`
+	Used    int                `bson:"used" json:"used"`
+}
+
+// TicketSalesReport sums up the tickets sold for the matches of a tournament
+type TicketSalesReport struct {
+	TournamentID primitive.ObjectID `json:"tournament_id"`
+	Sold         int                `json:"sold"`
+	Used         int                `json:"used"`
+	Matches      []MatchTicketSales `json:"matches"`
+}
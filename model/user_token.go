@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// User token purposes
+const (
+	TokenEmailVerification = "email_verification"
+	TokenPasswordReset     = "password_reset"
+	TokenTwoFactorLogin    = "two_factor_login"
+)
+
+// UserToken is a single-use token sent to a user by email. Only the hash of the token is stored
+type UserToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Purpose   string             `bson:"purpose" json:"purpose"`
+	Email     string             `bson:"email" json:"email"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty" json:"used_at,omitempty"`
+	Attempts  int                `bson:"attempts,omitempty" json:"attempts,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// OutboxMessage is an email stored by the outbox mailer for delivery by another process
+type OutboxMessage struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	To        string             `bson:"to" json:"to"`
+	Subject   string             `bson:"subject" json:"subject"`
+	Body      string             `bson:"body" json:"body"`
+	Status    string             `bson:"status" json:"status" example:"pending"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// VerifyEmailRequest represents request body for verifying an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" example:"9c1e0f..." description:"Token dari tautan verifikasi"`
+}
+
+// EmailRequest represents request body for endpoints that only take an email address
+type EmailRequest struct {
+	Email string `json:"email" example:"user.example@example.com" description:"Alamat email akun"`
+}
+
+// ResetPasswordRequest represents request body for choosing a new password
+type ResetPasswordRequest struct {
+	Token    string `json:"token" example:"9c1e0f..." description:"Token dari tautan reset password"`
+	Password string `json:"password" example:"passwordBaru123" description:"Password baru minimal 6 karakter"`
+}
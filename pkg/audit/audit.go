@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"embeck/model"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Redacted replaces the value of sensitive fields in recorded changes
+const Redacted = "[redacted]"
+
+// entityTypes maps the first admin path segment to the audited entity type
+var entityTypes = map[string]string{
+	"players":          "player",
+	"teams":            "team",
+	"tournaments":      "tournament",
+	"matches":          "match",
+	"users":            "user",
+	"registrations":    "registration",
+	"player-links":     "player_link",
+	"role-assignments": "role_assignment",
+	"tickets":          "ticket",
+	"upload":           "upload",
+}
+
+// ignored fields change on every write and carry no information of their own
+var ignored = map[string]bool{"updated_at": true, "version": true}
+
+// sensitive fields are recorded as changed without their values
+var sensitive = map[string]bool{"password": true, "two_factor": true}
+
+// Target identifies what an admin request acts on
+type Target struct {
+	EntityType string
+	EntityID   string
+	Action     string
+}
+
+// Resolve derives the target of an admin request from its method and the path segments after
+// /api/admin. Plain CRUD routes give create, update or delete; routes with a sub-path after the
+// entity ID give that sub-path without IDs, e.g. POST /registrations/:id/approve is "approve".
+// Trash routes take the entity type from the path and purging is recorded as "purge"
+func Resolve(method string, segments []string) (Target, bool) {
+	if len(segments) == 0 {
+		return Target{}, false
+	}
+
+	var target Target
+	rest := segments[1:]
+	if segments[0] == "trash" {
+		if len(rest) == 0 {
+			return Target{}, false
+		}
+		target.EntityType, rest = rest[0], rest[1:]
+	} else {
+		target.EntityType = segments[0]
+		if entityType, ok := entityTypes[segments[0]]; ok {
+			target.EntityType = entityType
+		}
+	}
+
+	var sub []string
+	for _, segment := range rest {
+		if primitive.IsValidObjectID(segment) {
+			if target.EntityID == "" {
+				target.EntityID = segment
+			}
+			continue
+		}
+		sub = append(sub, strings.ReplaceAll(segment, "-", "_"))
+	}
+
+	switch {
+	case len(sub) > 0 && method == "POST":
+		target.Action = strings.Join(sub, "_")
+	case len(sub) > 0:
+		target.Action = verb(method) + "_" + strings.Join(sub, "_")
+	case segments[0] == "trash" && method == "DELETE":
+		target.Action = model.AuditPurge
+	default:
+		target.Action = verb(method)
+	}
+	return target, true
+}
+
+// verb names the CRUD action of an HTTP method
+func verb(method string) string {
+	switch method {
+	case "POST":
+		return model.AuditCreate
+	case "DELETE":
+		return model.AuditDelete
+	default:
+		return model.AuditUpdate
+	}
+}
+
+// Diff lists the top-level fields that differ between two snapshots, sorted by field name.
+// A nil snapshot stands for a document that does not exist (before a create, after a purge)
+func Diff(before, after bson.M) []model.AuditChange {
+	fields := make(map[string]bool)
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	var changes []model.AuditChange
+	for field := range fields {
+		if ignored[field] {
+			continue
+		}
+		oldValue, newValue := before[field], after[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		change := model.AuditChange{Field: field, Before: oldValue, After: newValue}
+		if sensitive[field] {
+			change.Before, change.After = nil, nil
+			if oldValue != nil {
+				change.Before = Redacted
+			}
+			if newValue != nil {
+				change.After = Redacted
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
@@ -2,70 +2,42 @@ package auth
 
 import (
 	"embeck/model"
-	"encoding/hex"
 	"errors"
-	"os"
 	"time"
 
 	"aidanwoods.dev/go-paseto"
 )
 
-// GenerateToken creates a new PASETO token for a user using public-key signing.
-func GenerateToken(user *model.User) (string, error) {
-	// Get the hex-encoded private key from the environment variables.
-	privateKeyHex := os.Getenv("PRIVATE_KEY")
-	if privateKeyHex == "" {
-		return "", errors.New("PRIVATE_KEY environment variable not set")
-	}
-
-	// Decode the hex string into bytes.
-	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
+// GenerateToken creates a new PASETO access token for a user, signed with the current key of the keyring.
+// The token is bound to a login session so it stops working once the session is revoked.
+func GenerateToken(user *model.User, sessionID string, ttl time.Duration) (string, error) {
+	ring, err := currentKeyring()
 	if err != nil {
-		return "", errors.New("failed to decode private key")
-	}
-
-	// Create a Paseto V4 asymmetric private key from the decoded bytes.
-	privateKey, err := paseto.NewV4AsymmetricSecretKeyFromBytes(privateKeyBytes)
-	if err != nil {
-		return "", errors.New("failed to create paseto private key")
+		return "", err
 	}
 
 	// Create a new token.
 	token := paseto.NewToken()
 	token.SetIssuedAt(time.Now())
 	token.SetNotBefore(time.Now())
-	token.SetExpiration(time.Now().Add(24 * time.Hour)) // Token is valid for 24 hours.
+	token.SetExpiration(time.Now().Add(ttl))
 
 	// Add custom claims to the token.
 	token.SetString("user_id", user.ID.Hex())
 	token.SetString("username", user.Username)
 	token.SetString("email", user.Email)
 	token.SetString("role", user.Role)
+	token.SetString("sid", sessionID)
 
-	// Sign the token with the private key to create a public token.
-	signedToken := token.V4Sign(privateKey, nil)
-
-	return signedToken, nil
+	// Sign the token with the current key to create a public token.
+	return ring.sign(token), nil
 }
 
-// ValidateToken validates and parses a PASETO token using the public key.
+// ValidateToken validates and parses a PASETO token using the keyring.
 func ValidateToken(tokenString string) (*model.TokenClaims, error) {
-	// Get the hex-encoded public key from the environment variables.
-	publicKeyHex := os.Getenv("PUBLIC_KEY")
-	if publicKeyHex == "" {
-		return nil, errors.New("PUBLIC_KEY environment variable not set")
-	}
-
-	// Decode the hex string into bytes.
-	publicKeyBytes, err := hex.DecodeString(publicKeyHex)
-	if err != nil {
-		return nil, errors.New("failed to decode public key")
-	}
-
-	// Create a Paseto V4 asymmetric public key from the decoded bytes.
-	publicKey, err := paseto.NewV4AsymmetricPublicKeyFromBytes(publicKeyBytes)
+	ring, err := currentKeyring()
 	if err != nil {
-		return nil, errors.New("failed to create paseto public key")
+		return nil, err
 	}
 
 	// Create a new Paseto parser to verify the token.
@@ -73,10 +45,10 @@ func ValidateToken(tokenString string) (*model.TokenClaims, error) {
 	// Add rules to validate standard claims (e.g., token has not expired).
 	parser.AddRule(paseto.NotExpired())
 
-	// Parse and validate the token using the public key.
-	token, err := parser.ParseV4Public(publicKey, tokenString, nil)
+	// Verify the token with the key named in its footer.
+	token, err := ring.verify(parser, tokenString, time.Now())
 	if err != nil {
-		return nil, errors.New("invalid token or signature")
+		return nil, err
 	}
 
 	// Extract custom claims from the token.
@@ -93,6 +65,10 @@ func ValidateToken(tokenString string) (*model.TokenClaims, error) {
 	if err := token.Get("role", &claims.Role); err != nil {
 		return nil, err
 	}
+	// Tokens issued before sessions existed cannot be revoked and are no longer accepted
+	if err := token.Get("sid", &claims.SessionID); err != nil || claims.SessionID == "" {
+		return nil, errors.New("token has no session, please log in again")
+	}
 
 	// Extract standard time-based claims.
 	issuedAt, err := token.GetIssuedAt()
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+// KeyringConfig holds the hex-encoded keys a keyring is built from
+type KeyringConfig struct {
+	// SigningKeyID is stamped in the footer of every new token
+	SigningKeyID string
+	// PrivateKey signs new tokens; its public half is always accepted for verification
+	PrivateKey string
+	// PublicKeys lists earlier keys that still verify tokens, as "kid=hex" pairs separated by
+	// commas. A key retires at an RFC 3339 time given after "@", e.g. "v1=ab12...@2026-11-01T00:00:00Z"
+	PublicKeys string
+	// LegacyPublicKey verifies tokens signed before tokens carried a key ID
+	LegacyPublicKey string
+	// LegacyRetireAt is the RFC 3339 time LegacyPublicKey stops verifying tokens; it is required
+	// with that key
+	LegacyRetireAt string
+}
+
+// verificationKey is a public key accepted for the tokens signed with its key ID
+type verificationKey struct {
+	key      paseto.V4AsymmetricPublicKey
+	retireAt *time.Time
+}
+
+// Keyring signs tokens with one key and verifies them with any key that has not retired yet,
+// so keys can be rotated without logging everyone out
+type Keyring struct {
+	signingKeyID string
+	signingKey   paseto.V4AsymmetricSecretKey
+	keys         map[string]verificationKey
+}
+
+// footer is the unencrypted but signed part of a token naming the key that signed it
+type footer struct {
+	KeyID string `json:"kid"`
+}
+
+var (
+	keyringMu sync.RWMutex
+	keyring   *Keyring
+)
+
+// LoadKeyring parses the keys once and makes them the keyring used by GenerateToken and
+// ValidateToken. It is called at startup so a bad key fails fast instead of on the first login
+func LoadKeyring(cfg KeyringConfig) (*Keyring, error) {
+	if cfg.SigningKeyID == "" {
+		return nil, errors.New("signing key ID is not set")
+	}
+	if cfg.PrivateKey == "" {
+		return nil, errors.New("private key is not set")
+	}
+	signingKey, err := paseto.NewV4AsymmetricSecretKeyFromHex(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+
+	ring := &Keyring{
+		signingKeyID: cfg.SigningKeyID,
+		signingKey:   signingKey,
+		keys:         map[string]verificationKey{cfg.SigningKeyID: {key: signingKey.Public()}},
+	}
+
+	for _, entry := range strings.Split(cfg.PublicKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		keyID, rest, found := strings.Cut(entry, "=")
+		if !found || keyID == "" {
+			return nil, fmt.Errorf("invalid public key entry %q, expected kid=hex[@retire_at]", entry)
+		}
+		if _, exists := ring.keys[keyID]; exists {
+			return nil, fmt.Errorf("duplicate key ID %q", keyID)
+		}
+
+		keyHex, retireAt, hasRetireAt := strings.Cut(rest, "@")
+		key, err := paseto.NewV4AsymmetricPublicKeyFromHex(keyHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %v", keyID, err)
+		}
+		verification := verificationKey{key: key}
+		if hasRetireAt {
+			retire, err := time.Parse(time.RFC3339, retireAt)
+			if err != nil {
+				return nil, fmt.Errorf("invalid retirement time of key %q: %v", keyID, err)
+			}
+			verification.retireAt = &retire
+		}
+		ring.keys[keyID] = verification
+	}
+
+	// Tokens without a key ID are stored under the empty ID, and only until the legacy key retires
+	if cfg.LegacyPublicKey != "" {
+		if cfg.LegacyRetireAt == "" {
+			return nil, errors.New("legacy public key has no retirement time")
+		}
+		retire, err := time.Parse(time.RFC3339, cfg.LegacyRetireAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retirement time of legacy public key: %v", err)
+		}
+		key, err := paseto.NewV4AsymmetricPublicKeyFromHex(cfg.LegacyPublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid legacy public key: %v", err)
+		}
+		ring.keys[""] = verificationKey{key: key, retireAt: &retire}
+	}
+
+	keyringMu.Lock()
+	keyring = ring
+	keyringMu.Unlock()
+	return ring, nil
+}
+
+// currentKeyring returns the keyring loaded at startup
+func currentKeyring() (*Keyring, error) {
+	keyringMu.RLock()
+	defer keyringMu.RUnlock()
+	if keyring == nil {
+		return nil, errors.New("token keyring is not loaded")
+	}
+	return keyring, nil
+}
+
+// KeyIDs returns the IDs of the keys that verify tokens at the given time, the signing key
+// first. The legacy key has the empty ID
+func (k *Keyring) KeyIDs(now time.Time) []string {
+	var others []string
+	for id, key := range k.keys {
+		if id != k.signingKeyID && (key.retireAt == nil || now.Before(*key.retireAt)) {
+			others = append(others, id)
+		}
+	}
+	sort.Strings(others)
+	return append([]string{k.signingKeyID}, others...)
+}
+
+// sign signs a token with the current key and names the key in its footer
+func (k *Keyring) sign(token paseto.Token) string {
+	data, _ := json.Marshal(footer{KeyID: k.signingKeyID})
+	token.SetFooter(data)
+	return token.V4Sign(k.signingKey, nil)
+}
+
+// verify checks a token against the key named in its footer. Tokens of retired or unknown
+// keys are rejected
+func (k *Keyring) verify(parser paseto.Parser, tokenString string, now time.Time) (*paseto.Token, error) {
+	var keyID string
+	data, err := parser.UnsafeParseFooter(paseto.V4Public, tokenString)
+	if err != nil {
+		return nil, errors.New("invalid token or signature")
+	}
+	if len(data) > 0 {
+		var f footer
+		if err := json.Unmarshal(data, &f); err != nil || f.KeyID == "" {
+			return nil, errors.New("invalid token footer")
+		}
+		keyID = f.KeyID
+	}
+
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, errors.New("token signed with an unknown key, please log in again")
+	}
+	if key.retireAt != nil && !now.Before(*key.retireAt) {
+		return nil, errors.New("token signed with a retired key, please log in again")
+	}
+
+	// The footer is covered by the signature, so a forged key ID fails here
+	token, err := parser.ParseV4Public(key.key, tokenString, nil)
+	if err != nil {
+		return nil, errors.New("invalid token or signature")
+	}
+	return token, nil
+}
+
+// GenerateKeyPair creates a new Ed25519 keypair, hex-encoded in the format the keyring reads
+func GenerateKeyPair() (privateKey string, publicKey string) {
+	key := paseto.NewV4AsymmetricSecretKey()
+	return key.ExportHex(), key.Public().ExportHex()
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewRefreshToken creates an opaque refresh token for a session. The token carries the
+// session ID followed by a random secret; only its hash is stored server-side
+func NewRefreshToken(sessionID primitive.ObjectID) (token string, hash string, err error) {
+	secret, err := newSecret()
+	if err != nil {
+		return "", "", err
+	}
+	token = sessionID.Hex() + "." + secret
+	return token, HashToken(token), nil
+}
+
+// ParseRefreshToken returns the session a refresh token belongs to
+func ParseRefreshToken(token string) (primitive.ObjectID, error) {
+	sessionID, secret, found := strings.Cut(token, ".")
+	if !found || secret == "" {
+		return primitive.NilObjectID, errors.New("invalid refresh token format")
+	}
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return primitive.NilObjectID, errors.New("invalid refresh token format")
+	}
+	return objID, nil
+}
+
+// NewOneTimeToken creates a random token for links sent by email, such as email verification
+// and password reset. Only its hash is stored
+func NewOneTimeToken() (token string, hash string, err error) {
+	token, err = newSecret()
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex SHA-256 of a token as stored server-side
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSecret returns 32 random bytes, hex encoded
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
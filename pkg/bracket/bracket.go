@@ -0,0 +1,228 @@
+package bracket
+
+import (
+	"embeck/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Slot names used in Match.NextMatchSlot to tell which side of the next match a team takes.
+const (
+	SlotTeamA = "team_a"
+	SlotTeamB = "team_b"
+)
+
+// Bracket formats supported by tournaments.
+const (
+	FormatSingleElimination = "single_elimination"
+	FormatDoubleElimination = "double_elimination"
+)
+
+// Bracket sections stored in Match.Bracket.
+const (
+	BracketMain            = "main"
+	BracketUpper           = "upper"
+	BracketLower           = "lower"
+	BracketGrandFinal      = "grand_final"
+	BracketGrandFinalReset = "grand_final_reset"
+)
+
+// ErrNotEnoughTeams is returned when a bracket is requested for too few teams.
+var ErrNotEnoughTeams = errors.New("jumlah team tidak cukup untuk membuat bracket")
+
+// Schedule holds the default schedule applied to every generated match.
+type Schedule struct {
+	MatchDate time.Time
+	MatchTime string
+	Location  string
+}
+
+// Size returns the smallest power of two that fits the given number of teams.
+func Size(teams int) int {
+	size := 1
+	for size < teams {
+		size *= 2
+	}
+	return size
+}
+
+// SeedPositions returns the standard seed order for a bracket of the given size,
+// e.g. size 8 gives [1 8 4 5 2 7 3 6], so that top seeds meet as late as possible.
+func SeedPositions(size int) []int {
+	positions := []int{1}
+	for len(positions) < size {
+		n := len(positions) * 2
+		next := make([]int, 0, n)
+		for _, seed := range positions {
+			next = append(next, seed, n+1-seed)
+		}
+		positions = next
+	}
+	return positions
+}
+
+// seedTeam returns the team for a 1-based seed, or a zero ObjectID when the seed is a bye.
+func seedTeam(teams []primitive.ObjectID, seed int) primitive.ObjectID {
+	if seed > len(teams) {
+		return primitive.NilObjectID
+	}
+	return teams[seed-1]
+}
+
+// roundCount returns log2 of a bracket size.
+func roundCount(size int) int {
+	rounds := 0
+	for n := size; n > 1; n /= 2 {
+		rounds++
+	}
+	return rounds
+}
+
+// roundName returns a display name for a round given how many rounds the bracket has.
+func roundName(totalRounds, round int) string {
+	switch totalRounds - round {
+	case 0:
+		return "Final"
+	case 1:
+		return "Semifinal"
+	case 2:
+		return "Quarterfinal"
+	default:
+		return fmt.Sprintf("Round %d", round)
+	}
+}
+
+// newMatch returns an unplayed bracket match with a fresh ID.
+func newMatch(tournamentID primitive.ObjectID, schedule Schedule, section, name string, round, position int) *model.Match {
+	now := time.Now()
+	return &model.Match{
+		ID:              primitive.NewObjectID(),
+		TournamentID:    tournamentID,
+		MatchDate:       schedule.MatchDate,
+		MatchTime:       schedule.MatchTime,
+		Location:        schedule.Location,
+		Round:           name,
+		Bracket:         section,
+		BracketRound:    round,
+		BracketPosition: position,
+		Status:          "scheduled",
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// linkWinner routes the winner of from into the given slot of to.
+func linkWinner(from, to *model.Match, slot string) {
+	id := to.ID
+	from.NextMatchID = &id
+	from.NextMatchSlot = slot
+}
+
+// linkLoser routes the loser of from into the given slot of to.
+func linkLoser(from, to *model.Match, slot string) {
+	id := to.ID
+	from.LoserNextMatchID = &id
+	from.LoserNextMatchSlot = slot
+}
+
+// slotFor returns team_a for even positions and team_b for odd ones (0-based).
+func slotFor(position int) string {
+	if position%2 == 1 {
+		return SlotTeamB
+	}
+	return SlotTeamA
+}
+
+// PlaceTeam puts a team into the given slot of a match.
+func PlaceTeam(match *model.Match, slot string, teamID primitive.ObjectID) {
+	if slot == SlotTeamB {
+		match.TeamBID = teamID
+		return
+	}
+	match.TeamAID = teamID
+}
+
+// resolveByes walks the matches in generation order and marks every match that can never
+// have two teams as a bye. A bye whose lone team is already known is completed and the team
+// moves on; byes still waiting for a team are completed later when that team is placed.
+// Matches that can never receive any team are cancelled.
+func resolveByes(matches []*model.Match) {
+	type feed struct {
+		from  *model.Match
+		loser bool
+	}
+
+	byID := make(map[primitive.ObjectID]*model.Match, len(matches))
+	feeds := make(map[primitive.ObjectID]map[string]feed, len(matches))
+	for _, m := range matches {
+		byID[m.ID] = m
+		feeds[m.ID] = map[string]feed{}
+	}
+	for _, m := range matches {
+		if m.NextMatchID != nil {
+			feeds[*m.NextMatchID][m.NextMatchSlot] = feed{from: m}
+		}
+		if m.LoserNextMatchID != nil {
+			feeds[*m.LoserNextMatchID][m.LoserNextMatchSlot] = feed{from: m, loser: true}
+		}
+	}
+
+	winnerLive := map[primitive.ObjectID]bool{}
+	loserLive := map[primitive.ObjectID]bool{}
+	slotLive := func(m *model.Match, slot string, team primitive.ObjectID) bool {
+		f, ok := feeds[m.ID][slot]
+		if !ok {
+			return !team.IsZero()
+		}
+		if f.loser {
+			return loserLive[f.from.ID]
+		}
+		return winnerLive[f.from.ID]
+	}
+
+	for _, m := range matches {
+		// The reset match is only filled when the grand final needs it
+		if m.Bracket == BracketGrandFinalReset {
+			continue
+		}
+
+		liveA := slotLive(m, SlotTeamA, m.TeamAID)
+		liveB := slotLive(m, SlotTeamB, m.TeamBID)
+		winnerLive[m.ID] = liveA || liveB
+		loserLive[m.ID] = liveA && liveB
+
+		switch {
+		case liveA && liveB:
+			continue
+		case !liveA && !liveB:
+			m.IsBye = true
+			m.Status = "cancelled"
+		default:
+			m.IsBye = true
+			team := m.TeamAID
+			if liveB {
+				team = m.TeamBID
+			}
+			if team.IsZero() {
+				continue
+			}
+			m.Status = "completed"
+			m.WinnerTeamID = &team
+			if m.NextMatchID != nil {
+				PlaceTeam(byID[*m.NextMatchID], m.NextMatchSlot, team)
+			}
+		}
+	}
+}
+
+// flatten copies the generated matches into a value slice.
+func flatten(matches []*model.Match) []model.Match {
+	result := make([]model.Match, len(matches))
+	for i, m := range matches {
+		result[i] = *m
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package bracket
+
+import (
+	"embeck/model"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DoubleElimination builds the upper bracket, lower bracket and grand final for the given teams.
+// Teams must already be in seed order. Losers of every upper-bracket round drop into the lower
+// bracket; from the second round on they drop in reverse order to avoid early rematches.
+// When withReset is true an extra grand final reset match is created, which is only played
+// if the lower-bracket champion wins the first grand final.
+func DoubleElimination(tournamentID primitive.ObjectID, teams []primitive.ObjectID, schedule Schedule, withReset bool) ([]model.Match, error) {
+	if len(teams) < 3 {
+		return nil, ErrNotEnoughTeams
+	}
+
+	size := Size(len(teams))
+	upper := buildTree(tournamentID, size, schedule, BracketUpper, upperRoundName)
+	seedFirstRound(upper[0], teams, size)
+	upperRounds := len(upper)
+
+	// The lower bracket alternates between rounds where upper-bracket losers drop in
+	// and rounds where lower-bracket survivors play each other.
+	lowerRounds := 2 * (upperRounds - 1)
+	lower := make([][]*model.Match, lowerRounds)
+	for r := range lower {
+		count := size >> ((r+1)/2 + 2)
+		if r%2 == 1 {
+			count = size >> ((r+1)/2 + 1)
+		}
+		lower[r] = make([]*model.Match, count)
+		for p := range lower[r] {
+			lower[r][p] = newMatch(tournamentID, schedule, BracketLower, lowerRoundName(lowerRounds, r+1), r+1, p+1)
+		}
+	}
+
+	// Lower round 1: losers of upper round 1, paired up
+	for p, match := range upper[0] {
+		linkLoser(match, lower[0][p/2], slotFor(p))
+	}
+
+	for r := 1; r < lowerRounds; r++ {
+		if r%2 == 1 {
+			// Drop-in round: lower survivors take team_a, upper losers take team_b
+			upperRound := upper[(r+1)/2]
+			for p, match := range lower[r-1] {
+				linkWinner(match, lower[r][p], SlotTeamA)
+			}
+			for p, match := range upperRound {
+				target := p
+				if ((r+1)/2)%2 == 1 {
+					target = len(upperRound) - 1 - p
+				}
+				linkLoser(match, lower[r][target], SlotTeamB)
+			}
+			continue
+		}
+		for p, match := range lower[r-1] {
+			linkWinner(match, lower[r][p/2], slotFor(p))
+		}
+	}
+
+	grandFinal := newMatch(tournamentID, schedule, BracketGrandFinal, "Grand Final", 1, 1)
+	linkWinner(upper[upperRounds-1][0], grandFinal, SlotTeamA)
+	linkWinner(lower[lowerRounds-1][0], grandFinal, SlotTeamB)
+
+	var matches []*model.Match
+	for _, round := range upper {
+		matches = append(matches, round...)
+	}
+	for _, round := range lower {
+		matches = append(matches, round...)
+	}
+	matches = append(matches, grandFinal)
+	if withReset {
+		matches = append(matches, newMatch(tournamentID, schedule, BracketGrandFinalReset, "Grand Final Reset", 2, 1))
+	}
+
+	resolveByes(matches)
+	return flatten(matches), nil
+}
+
+// upperRoundName names upper-bracket rounds, e.g. "Upper Bracket Semifinal".
+func upperRoundName(totalRounds, round int) string {
+	return "Upper Bracket " + roundName(totalRounds, round)
+}
+
+// lowerRoundName names lower-bracket rounds, e.g. "Lower Bracket Round 3".
+func lowerRoundName(totalRounds, round int) string {
+	if round == totalRounds {
+		return "Lower Bracket Final"
+	}
+	return fmt.Sprintf("Lower Bracket Round %d", round)
+}
@@ -0,0 +1,167 @@
+package bracket
+
+import (
+	"embeck/model"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDoubleEliminationNotEnoughTeams(t *testing.T) {
+	if _, err := DoubleElimination(primitive.NewObjectID(), newTeams(2), Schedule{}, true); err != ErrNotEnoughTeams {
+		t.Fatalf("got %v, want %v", err, ErrNotEnoughTeams)
+	}
+}
+
+func TestDoubleEliminationShape(t *testing.T) {
+	tests := []struct {
+		teams int
+		upper []int
+		lower []int
+	}{
+		{4, []int{2, 1}, []int{1, 1}},
+		{8, []int{4, 2, 1}, []int{2, 2, 1, 1}},
+		{16, []int{8, 4, 2, 1}, []int{4, 4, 2, 2, 1, 1}},
+	}
+	for _, tt := range tests {
+		matches, err := DoubleElimination(primitive.NewObjectID(), newTeams(tt.teams), Schedule{}, false)
+		if err != nil {
+			t.Fatalf("%d teams: %v", tt.teams, err)
+		}
+		for r, want := range tt.upper {
+			if got := len(matchesIn(matches, BracketUpper, r+1)); got != want {
+				t.Errorf("%d teams, upper round %d: got %d matches, want %d", tt.teams, r+1, got, want)
+			}
+		}
+		for r, want := range tt.lower {
+			if got := len(matchesIn(matches, BracketLower, r+1)); got != want {
+				t.Errorf("%d teams, lower round %d: got %d matches, want %d", tt.teams, r+1, got, want)
+			}
+		}
+		lowerFinal := matchesIn(matches, BracketLower, len(tt.lower))
+		if len(lowerFinal) != 1 || lowerFinal[0].Round != "Lower Bracket Final" {
+			t.Errorf("%d teams: lower final missing or misnamed", tt.teams)
+		}
+		if got := len(matchesIn(matches, BracketGrandFinal, 1)); got != 1 {
+			t.Errorf("%d teams: got %d grand finals, want 1", tt.teams, got)
+		}
+		// n-1 upper matches, n-2 lower matches and the grand final
+		if got, want := len(matches), 2*tt.teams-2; got != want {
+			t.Errorf("%d teams: got %d matches, want %d", tt.teams, got, want)
+		}
+	}
+}
+
+func TestDoubleEliminationLoserRouting(t *testing.T) {
+	matches, err := DoubleElimination(primitive.NewObjectID(), newTeams(8), Schedule{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := byID(matches)
+
+	type route struct {
+		upperRound, upperPosition int
+		lowerRound, lowerPosition int
+		slot                      string
+	}
+	tests := []route{
+		// Upper round 1 losers pair up in lower round 1
+		{1, 1, 1, 1, SlotTeamA},
+		{1, 2, 1, 1, SlotTeamB},
+		{1, 3, 1, 2, SlotTeamA},
+		{1, 4, 1, 2, SlotTeamB},
+		// Upper round 2 losers drop in reversed to avoid rematches
+		{2, 1, 2, 2, SlotTeamB},
+		{2, 2, 2, 1, SlotTeamB},
+		// The upper final loser drops into the lower final
+		{3, 1, 4, 1, SlotTeamB},
+	}
+	for _, tt := range tests {
+		m := matchesIn(matches, BracketUpper, tt.upperRound)[tt.upperPosition-1]
+		if m.LoserNextMatchID == nil {
+			t.Errorf("upper %d/%d: loser not routed", tt.upperRound, tt.upperPosition)
+			continue
+		}
+		next := index[*m.LoserNextMatchID]
+		if next.Bracket != BracketLower || next.BracketRound != tt.lowerRound || next.BracketPosition != tt.lowerPosition || m.LoserNextMatchSlot != tt.slot {
+			t.Errorf("upper %d/%d: loser routed to %s %d/%d %s, want lower %d/%d %s",
+				tt.upperRound, tt.upperPosition, next.Bracket, next.BracketRound, next.BracketPosition, m.LoserNextMatchSlot,
+				tt.lowerRound, tt.lowerPosition, tt.slot)
+		}
+	}
+
+	// Lower survivors keep team A in drop-in rounds
+	for p, m := range matchesIn(matches, BracketLower, 1) {
+		next := index[*m.NextMatchID]
+		if next.BracketRound != 2 || next.BracketPosition != p+1 || m.NextMatchSlot != SlotTeamA {
+			t.Errorf("lower 1/%d: winner routed to lower %d/%d %s", p+1, next.BracketRound, next.BracketPosition, m.NextMatchSlot)
+		}
+	}
+}
+
+func TestDoubleEliminationGrandFinal(t *testing.T) {
+	tests := []struct {
+		withReset bool
+		resets    int
+	}{
+		{false, 0},
+		{true, 1},
+	}
+	for _, tt := range tests {
+		matches, err := DoubleElimination(primitive.NewObjectID(), newTeams(8), Schedule{}, tt.withReset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		grandFinal := matchesIn(matches, BracketGrandFinal, 1)[0]
+		upperFinal := matchesIn(matches, BracketUpper, 3)[0]
+		lowerFinal := matchesIn(matches, BracketLower, 4)[0]
+
+		if upperFinal.NextMatchID == nil || *upperFinal.NextMatchID != grandFinal.ID || upperFinal.NextMatchSlot != SlotTeamA {
+			t.Errorf("reset %v: upper champion not routed to grand final team A", tt.withReset)
+		}
+		if lowerFinal.NextMatchID == nil || *lowerFinal.NextMatchID != grandFinal.ID || lowerFinal.NextMatchSlot != SlotTeamB {
+			t.Errorf("reset %v: lower champion not routed to grand final team B", tt.withReset)
+		}
+		if grandFinal.NextMatchID != nil || grandFinal.LoserNextMatchID != nil {
+			t.Errorf("reset %v: grand final leads to another match", tt.withReset)
+		}
+
+		resets := matchesIn(matches, BracketGrandFinalReset, 2)
+		if len(resets) != tt.resets {
+			t.Fatalf("reset %v: got %d reset matches, want %d", tt.withReset, len(resets), tt.resets)
+		}
+		// The reset waits for the grand final result instead of being resolved as a bye
+		for _, reset := range resets {
+			if reset.IsBye || reset.Status != "scheduled" || !reset.TeamAID.IsZero() || !reset.TeamBID.IsZero() {
+				t.Errorf("reset match: got bye %v, status %s, want an empty scheduled match", reset.IsBye, reset.Status)
+			}
+		}
+	}
+}
+
+func TestDoubleEliminationByes(t *testing.T) {
+	teams := newTeams(3)
+	matches, err := DoubleElimination(primitive.NewObjectID(), teams, Schedule{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upper := matchesIn(matches, BracketUpper, 1)
+	if !upper[0].IsBye || upper[0].Status != "completed" || *upper[0].WinnerTeamID != teams[0] {
+		t.Errorf("top seed bye: got bye %v, status %s", upper[0].IsBye, upper[0].Status)
+	}
+	if upper[1].IsBye {
+		t.Errorf("seeds 2 and 3 should play")
+	}
+
+	// Nobody loses the bye, so the lone loser of the other match skips lower round 1
+	lower := matchesIn(matches, BracketLower, 1)[0]
+	if !lower.IsBye || lower.Status != "scheduled" {
+		t.Errorf("lower round 1: got bye %v, status %s, want a bye waiting for its team", lower.IsBye, lower.Status)
+	}
+	for _, m := range []model.Match{matchesIn(matches, BracketLower, 2)[0], matchesIn(matches, BracketGrandFinal, 1)[0]} {
+		if m.IsBye {
+			t.Errorf("%s: should be played", m.Round)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package bracket
+
+import (
+	"embeck/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SingleElimination builds every match of a single-elimination tree for the given teams.
+// Teams must already be in seed order (index 0 is seed 1). Missing seeds become byes.
+func SingleElimination(tournamentID primitive.ObjectID, teams []primitive.ObjectID, schedule Schedule) ([]model.Match, error) {
+	if len(teams) < 2 {
+		return nil, ErrNotEnoughTeams
+	}
+
+	size := Size(len(teams))
+	tree := buildTree(tournamentID, size, schedule, BracketMain, roundName)
+	seedFirstRound(tree[0], teams, size)
+
+	var matches []*model.Match
+	for _, round := range tree {
+		matches = append(matches, round...)
+	}
+	resolveByes(matches)
+	return flatten(matches), nil
+}
+
+// buildTree creates the rounds of a knockout tree and links each match to the one its winner advances to.
+func buildTree(tournamentID primitive.ObjectID, size int, schedule Schedule, section string, name func(totalRounds, round int) string) [][]*model.Match {
+	totalRounds := roundCount(size)
+	tree := make([][]*model.Match, totalRounds)
+	for r := range tree {
+		tree[r] = make([]*model.Match, size>>(r+1))
+		for p := range tree[r] {
+			tree[r][p] = newMatch(tournamentID, schedule, section, name(totalRounds, r+1), r+1, p+1)
+		}
+	}
+
+	for r := 0; r < totalRounds-1; r++ {
+		for p, match := range tree[r] {
+			linkWinner(match, tree[r+1][p/2], slotFor(p))
+		}
+	}
+	return tree
+}
+
+// seedFirstRound places the seeded teams into the opening round.
+func seedFirstRound(round []*model.Match, teams []primitive.ObjectID, size int) {
+	seeds := SeedPositions(size)
+	for p, match := range round {
+		match.TeamAID = seedTeam(teams, seeds[2*p])
+		match.TeamBID = seedTeam(teams, seeds[2*p+1])
+	}
+}
@@ -0,0 +1,188 @@
+package bracket
+
+import (
+	"embeck/model"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTeams(n int) []primitive.ObjectID {
+	teams := make([]primitive.ObjectID, n)
+	for i := range teams {
+		teams[i] = primitive.NewObjectID()
+	}
+	return teams
+}
+
+// matchesIn returns the matches of one section and round, in bracket position order.
+func matchesIn(matches []model.Match, section string, round int) []model.Match {
+	var result []model.Match
+	for _, m := range matches {
+		if m.Bracket == section && m.BracketRound == round {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func byID(matches []model.Match) map[primitive.ObjectID]model.Match {
+	index := make(map[primitive.ObjectID]model.Match, len(matches))
+	for _, m := range matches {
+		index[m.ID] = m
+	}
+	return index
+}
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		teams int
+		want  int
+	}{
+		{2, 2},
+		{3, 4},
+		{4, 4},
+		{5, 8},
+		{8, 8},
+		{9, 16},
+		{16, 16},
+	}
+	for _, tt := range tests {
+		if got := Size(tt.teams); got != tt.want {
+			t.Errorf("Size(%d) = %d, want %d", tt.teams, got, tt.want)
+		}
+	}
+}
+
+func TestSeedPositions(t *testing.T) {
+	tests := []struct {
+		size int
+		want []int
+	}{
+		{2, []int{1, 2}},
+		{4, []int{1, 4, 2, 3}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+	}
+	for _, tt := range tests {
+		if got := SeedPositions(tt.size); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SeedPositions(%d) = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestSingleEliminationNotEnoughTeams(t *testing.T) {
+	if _, err := SingleElimination(primitive.NewObjectID(), newTeams(1), Schedule{}); err != ErrNotEnoughTeams {
+		t.Fatalf("got %v, want %v", err, ErrNotEnoughTeams)
+	}
+}
+
+func TestSingleEliminationShape(t *testing.T) {
+	tests := []struct {
+		teams  int
+		rounds []string
+	}{
+		{2, []string{"Final"}},
+		{4, []string{"Semifinal", "Final"}},
+		{6, []string{"Quarterfinal", "Semifinal", "Final"}},
+		{16, []string{"Round 1", "Quarterfinal", "Semifinal", "Final"}},
+	}
+	for _, tt := range tests {
+		matches, err := SingleElimination(primitive.NewObjectID(), newTeams(tt.teams), Schedule{})
+		if err != nil {
+			t.Fatalf("%d teams: %v", tt.teams, err)
+		}
+		if got, want := len(matches), Size(tt.teams)-1; got != want {
+			t.Errorf("%d teams: got %d matches, want %d", tt.teams, got, want)
+		}
+
+		index := byID(matches)
+		for r, name := range tt.rounds {
+			round := matchesIn(matches, BracketMain, r+1)
+			if got, want := len(round), Size(tt.teams)>>(r+1); got != want {
+				t.Errorf("%d teams, round %d: got %d matches, want %d", tt.teams, r+1, got, want)
+			}
+			for p, m := range round {
+				if m.Round != name {
+					t.Errorf("%d teams, round %d: named %q, want %q", tt.teams, r+1, m.Round, name)
+				}
+				if r == len(tt.rounds)-1 {
+					if m.NextMatchID != nil {
+						t.Errorf("%d teams: final leads to another match", tt.teams)
+					}
+					continue
+				}
+				next, ok := index[*m.NextMatchID]
+				if !ok || next.BracketRound != r+2 || next.BracketPosition != p/2+1 || m.NextMatchSlot != slotFor(p) {
+					t.Errorf("%d teams, round %d position %d: winner routed to round %d position %d %s",
+						tt.teams, r+1, p+1, next.BracketRound, next.BracketPosition, m.NextMatchSlot)
+				}
+			}
+		}
+	}
+}
+
+func TestSingleEliminationSeeding(t *testing.T) {
+	teams := newTeams(8)
+	matches, err := SingleElimination(primitive.NewObjectID(), teams, Schedule{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Seed 1 meets seed 8, and seeds 1 and 2 sit in opposite halves
+	want := [][2]int{{1, 8}, {4, 5}, {2, 7}, {3, 6}}
+	for p, m := range matchesIn(matches, BracketMain, 1) {
+		if m.TeamAID != teams[want[p][0]-1] || m.TeamBID != teams[want[p][1]-1] {
+			t.Errorf("position %d: want seeds %d and %d", p+1, want[p][0], want[p][1])
+		}
+	}
+}
+
+func TestSingleEliminationByes(t *testing.T) {
+	tests := []struct {
+		teams int
+		byes  int
+	}{
+		{3, 1},
+		{5, 3},
+		{6, 2},
+		{7, 1},
+		{8, 0},
+	}
+	for _, tt := range tests {
+		teams := newTeams(tt.teams)
+		matches, err := SingleElimination(primitive.NewObjectID(), teams, Schedule{})
+		if err != nil {
+			t.Fatalf("%d teams: %v", tt.teams, err)
+		}
+
+		index := byID(matches)
+		byes := 0
+		for _, m := range matchesIn(matches, BracketMain, 1) {
+			if !m.IsBye {
+				if m.Status != "scheduled" {
+					t.Errorf("%d teams: played match is %s, want scheduled", tt.teams, m.Status)
+				}
+				continue
+			}
+			byes++
+
+			// The top seed of a bye match advances without playing
+			if m.Status != "completed" || m.WinnerTeamID == nil || *m.WinnerTeamID != m.TeamAID {
+				t.Errorf("%d teams: bye not completed with team A as winner", tt.teams)
+				continue
+			}
+			next := index[*m.NextMatchID]
+			placed := next.TeamAID
+			if m.NextMatchSlot == SlotTeamB {
+				placed = next.TeamBID
+			}
+			if placed != m.TeamAID {
+				t.Errorf("%d teams: bye winner not placed in the next match", tt.teams)
+			}
+		}
+		if byes != tt.byes {
+			t.Errorf("%d teams: got %d byes, want %d", tt.teams, byes, tt.byes)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package bracket
+
+import (
+	"embeck/model"
+	"sort"
+)
+
+// sectionOrder is the order bracket sections are drawn in.
+var sectionOrder = map[string]int{
+	BracketMain:            0,
+	BracketUpper:           1,
+	BracketLower:           2,
+	BracketGrandFinal:      3,
+	BracketGrandFinalReset: 4,
+}
+
+// View groups the bracket matches of a tournament into rounds per section, ordered
+// for drawing. Matches that are not part of a bracket are left out. It returns nil
+// when the tournament has no bracket yet.
+func View(format string, matches []model.MatchBasicInfo) *model.BracketView {
+	var bracketMatches []model.MatchBasicInfo
+	for _, match := range matches {
+		if match.BracketRound > 0 {
+			bracketMatches = append(bracketMatches, match)
+		}
+	}
+	if len(bracketMatches) == 0 {
+		return nil
+	}
+
+	sort.Slice(bracketMatches, func(i, j int) bool {
+		a, b := bracketMatches[i], bracketMatches[j]
+		if sectionOrder[a.Bracket] != sectionOrder[b.Bracket] {
+			return sectionOrder[a.Bracket] < sectionOrder[b.Bracket]
+		}
+		if a.BracketRound != b.BracketRound {
+			return a.BracketRound < b.BracketRound
+		}
+		return a.BracketPosition < b.BracketPosition
+	})
+
+	if format == "" {
+		format = FormatSingleElimination
+	}
+	view := &model.BracketView{Format: format}
+	for _, match := range bracketMatches {
+		last := len(view.Rounds) - 1
+		if last < 0 || view.Rounds[last].Bracket != match.Bracket || view.Rounds[last].Round != match.BracketRound {
+			view.Rounds = append(view.Rounds, model.BracketRoundView{
+				Bracket: match.Bracket,
+				Round:   match.BracketRound,
+				Name:    match.Round,
+			})
+			last++
+		}
+		view.Rounds[last].Matches = append(view.Rounds[last].Matches, match)
+	}
+	return view
+}
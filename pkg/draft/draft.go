@@ -0,0 +1,139 @@
+package draft
+
+import (
+	"embeck/model"
+	"embeck/pkg/series"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxPicksPerTeam is the number of heroes each team locks in.
+const MaxPicksPerTeam = 5
+
+// ValidRoles lists the MLBB roles a pick can be played in.
+var ValidRoles = map[string]bool{
+	"gold":   true,
+	"exp":    true,
+	"mid":    true,
+	"jungle": true,
+	"roam":   true,
+}
+
+// heroKey normalizes hero names so "Ling" and " ling" count as the same hero.
+func heroKey(hero string) string {
+	return strings.ToLower(strings.TrimSpace(hero))
+}
+
+// Validate checks a draft against the two teams of its match: every hero appears at
+// most once across bans and picks, each team picks at most five heroes, every player
+// picks at most once and roles are valid MLBB roles.
+func Validate(d model.GameDraft, teamA, teamB primitive.ObjectID) error {
+	isMatchTeam := func(teamID primitive.ObjectID) bool {
+		return teamID == teamA || teamID == teamB
+	}
+
+	used := map[string]string{}
+	for _, ban := range d.Bans {
+		if !isMatchTeam(ban.TeamID) {
+			return fmt.Errorf("Ban hero %s harus dari team A atau team B", ban.Hero)
+		}
+		key := heroKey(ban.Hero)
+		if key == "" {
+			return fmt.Errorf("Nama hero pada ban ke-%d wajib diisi", ban.Order)
+		}
+		if _, ok := used[key]; ok {
+			return fmt.Errorf("Hero %s sudah di-ban", ban.Hero)
+		}
+		used[key] = "ban"
+	}
+
+	picksPerTeam := map[primitive.ObjectID]int{}
+	pickedBy := map[primitive.ObjectID]bool{}
+	for _, pick := range d.Picks {
+		if !isMatchTeam(pick.TeamID) {
+			return fmt.Errorf("Pick hero %s harus dari team A atau team B", pick.Hero)
+		}
+		key := heroKey(pick.Hero)
+		if key == "" {
+			return fmt.Errorf("Nama hero pada pick ke-%d wajib diisi", pick.Order)
+		}
+		if action, ok := used[key]; ok {
+			if action == "ban" {
+				return fmt.Errorf("Hero %s sudah di-ban dan tidak dapat di-pick", pick.Hero)
+			}
+			return fmt.Errorf("Hero %s sudah di-pick dalam game ini", pick.Hero)
+		}
+		used[key] = "pick"
+
+		picksPerTeam[pick.TeamID]++
+		if picksPerTeam[pick.TeamID] > MaxPicksPerTeam {
+			return fmt.Errorf("Satu team maksimal memilih %d hero", MaxPicksPerTeam)
+		}
+
+		if pick.PlayerID != nil {
+			if pickedBy[*pick.PlayerID] {
+				return fmt.Errorf("Player %s sudah memilih hero lain dalam game ini", pick.PlayerID.Hex())
+			}
+			pickedBy[*pick.PlayerID] = true
+		}
+		if pick.Role != "" && !ValidRoles[pick.Role] {
+			return fmt.Errorf("Role %s tidak valid, gunakan gold, exp, mid, jungle, atau roam", pick.Role)
+		}
+	}
+	return nil
+}
+
+// Stats aggregates hero picks, bans and wins over every valid game with a recorded draft.
+// Rates are relative to the number of drafted games; presence counts a hero picked or banned.
+func Stats(matches []model.Match) (totalGames int, heroes []model.HeroStat) {
+	byHero := map[string]*model.HeroStat{}
+	stat := func(hero string) *model.HeroStat {
+		key := heroKey(hero)
+		if _, ok := byHero[key]; !ok {
+			byHero[key] = &model.HeroStat{Hero: strings.TrimSpace(hero)}
+		}
+		return byHero[key]
+	}
+
+	for _, match := range matches {
+		for _, game := range match.Games {
+			if game.Status == series.GameVoid || game.Draft == nil {
+				continue
+			}
+			totalGames++
+			for _, ban := range game.Draft.Bans {
+				stat(ban.Hero).Bans++
+			}
+			for _, pick := range game.Draft.Picks {
+				s := stat(pick.Hero)
+				s.Picks++
+				if pick.TeamID == game.WinnerTeamID {
+					s.Wins++
+				}
+			}
+		}
+	}
+
+	for _, s := range byHero {
+		if totalGames > 0 {
+			s.PickRate = float64(s.Picks) / float64(totalGames)
+			s.BanRate = float64(s.Bans) / float64(totalGames)
+			s.Presence = float64(s.Picks+s.Bans) / float64(totalGames)
+		}
+		if s.Picks > 0 {
+			s.WinRate = float64(s.Wins) / float64(s.Picks)
+		}
+		heroes = append(heroes, *s)
+	}
+
+	sort.Slice(heroes, func(i, j int) bool {
+		if heroes[i].Presence != heroes[j].Presence {
+			return heroes[i].Presence > heroes[j].Presence
+		}
+		return heroes[i].Hero < heroes[j].Hero
+	})
+	return totalGames, heroes
+}
@@ -0,0 +1,28 @@
+package etag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format renders an entity version as a strong ETag
+func Format(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// Parse reads the entity version from an If-Match header written by Format. Weak validators
+// (W/"3") are accepted since versions change on every write
+func Parse(header string) (int64, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(header), "W/")
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		unquoted = value
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid If-Match, gunakan ETag dari data yang akan diubah (mis. \"3\")")
+	}
+	return version, nil
+}
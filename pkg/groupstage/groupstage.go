@@ -0,0 +1,136 @@
+package groupstage
+
+import (
+	"embeck/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tiebreakers that can be applied, in order, to teams level on points.
+const (
+	TiebreakerHeadToHead = "head_to_head"
+	TiebreakerGameDiff   = "game_diff"
+	TiebreakerGamesWon   = "games_won"
+)
+
+// DefaultTiebreakers is used when a group stage does not configure its own.
+var DefaultTiebreakers = []string{TiebreakerHeadToHead, TiebreakerGameDiff, TiebreakerGamesWon}
+
+// ValidTiebreakers lists the accepted tiebreaker names.
+var ValidTiebreakers = map[string]bool{
+	TiebreakerHeadToHead: true,
+	TiebreakerGameDiff:   true,
+	TiebreakerGamesWon:   true,
+}
+
+// ErrInvalidGroupCount is returned when teams cannot be split into the requested groups.
+var ErrInvalidGroupCount = errors.New("jumlah group tidak valid: setiap group membutuhkan minimal 2 team")
+
+// Schedule holds the default schedule applied to every generated match.
+type Schedule struct {
+	MatchDate time.Time
+	MatchTime string
+	Location  string
+}
+
+// Distribute splits seeded teams into groups using snake seeding (A, B, C, C, B, A, ...)
+// so every group gets a fair spread of seeds. Groups are named A, B, C and so on.
+func Distribute(teams []primitive.ObjectID, groupCount int) ([]model.TournamentGroup, error) {
+	if groupCount < 1 || len(teams) < groupCount*2 {
+		return nil, ErrInvalidGroupCount
+	}
+
+	groups := make([]model.TournamentGroup, groupCount)
+	for i := range groups {
+		groups[i].Name = string(rune('A' + i))
+	}
+
+	for i, team := range teams {
+		pass, offset := i/groupCount, i%groupCount
+		if pass%2 == 1 {
+			offset = groupCount - 1 - offset
+		}
+		groups[offset].TeamIDs = append(groups[offset].TeamIDs, team)
+	}
+	return groups, nil
+}
+
+// RoundRobin builds every pairing of each group as matches, one matchday at a time,
+// using the circle method. With doubleRoundRobin the second leg repeats every pairing
+// with the sides swapped.
+func RoundRobin(tournamentID primitive.ObjectID, groups []model.TournamentGroup, doubleRoundRobin bool, schedule Schedule) []model.Match {
+	now := time.Now()
+	var matches []model.Match
+
+	for _, group := range groups {
+		days := pairings(group.TeamIDs)
+		if doubleRoundRobin {
+			firstLeg := len(days)
+			for d := 0; d < firstLeg; d++ {
+				var swapped [][2]primitive.ObjectID
+				for _, pair := range days[d] {
+					swapped = append(swapped, [2]primitive.ObjectID{pair[1], pair[0]})
+				}
+				days = append(days, swapped)
+			}
+		}
+
+		for d, day := range days {
+			for _, pair := range day {
+				matches = append(matches, model.Match{
+					ID:           primitive.NewObjectID(),
+					TournamentID: tournamentID,
+					TeamAID:      pair[0],
+					TeamBID:      pair[1],
+					MatchDate:    schedule.MatchDate,
+					MatchTime:    schedule.MatchTime,
+					Location:     schedule.Location,
+					Round:        fmt.Sprintf("Group %s - Matchday %d", group.Name, d+1),
+					Group:        group.Name,
+					Matchday:     d + 1,
+					Status:       "scheduled",
+					CreatedAt:    now,
+					UpdatedAt:    now,
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// pairings returns the matchdays of a single round robin. Teams with an odd count
+// sit out one matchday each.
+func pairings(teams []primitive.ObjectID) [][][2]primitive.ObjectID {
+	circle := make([]primitive.ObjectID, len(teams))
+	copy(circle, teams)
+	if len(circle)%2 == 1 {
+		circle = append(circle, primitive.NilObjectID)
+	}
+
+	n := len(circle)
+	var days [][][2]primitive.ObjectID
+	for d := 0; d < n-1; d++ {
+		var day [][2]primitive.ObjectID
+		for i := 0; i < n/2; i++ {
+			a, b := circle[i], circle[n-1-i]
+			if a.IsZero() || b.IsZero() {
+				continue
+			}
+			// Alternate sides for the fixed team so it is not always team A
+			if i == 0 && d%2 == 1 {
+				a, b = b, a
+			}
+			day = append(day, [2]primitive.ObjectID{a, b})
+		}
+		days = append(days, day)
+
+		// Keep the first team fixed and rotate the rest clockwise
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+	return days
+}
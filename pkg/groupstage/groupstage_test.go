@@ -0,0 +1,128 @@
+package groupstage
+
+import (
+	"embeck/model"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTeams(n int) []primitive.ObjectID {
+	teams := make([]primitive.ObjectID, n)
+	for i := range teams {
+		teams[i] = primitive.NewObjectID()
+	}
+	return teams
+}
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		teams  int
+		groups int
+		want   map[string][]int
+	}{
+		{4, 2, map[string][]int{"A": {1, 4}, "B": {2, 3}}},
+		{8, 2, map[string][]int{"A": {1, 4, 5, 8}, "B": {2, 3, 6, 7}}},
+		{7, 3, map[string][]int{"A": {1, 6, 7}, "B": {2, 5}, "C": {3, 4}}},
+	}
+	for _, tt := range tests {
+		teams := newTeams(tt.teams)
+		groups, err := Distribute(teams, tt.groups)
+		if err != nil {
+			t.Fatalf("%d teams in %d groups: %v", tt.teams, tt.groups, err)
+		}
+		if len(groups) != tt.groups {
+			t.Fatalf("%d teams in %d groups: got %d groups", tt.teams, tt.groups, len(groups))
+		}
+		for _, group := range groups {
+			seeds := tt.want[group.Name]
+			if len(group.TeamIDs) != len(seeds) {
+				t.Errorf("%d teams, group %s: got %d teams, want %d", tt.teams, group.Name, len(group.TeamIDs), len(seeds))
+				continue
+			}
+			for i, seed := range seeds {
+				if group.TeamIDs[i] != teams[seed-1] {
+					t.Errorf("%d teams, group %s: position %d is not seed %d", tt.teams, group.Name, i+1, seed)
+				}
+			}
+		}
+	}
+}
+
+func TestDistributeInvalidGroupCount(t *testing.T) {
+	tests := []struct {
+		teams  int
+		groups int
+	}{
+		{4, 0},
+		{3, 2},
+		{8, 5},
+	}
+	for _, tt := range tests {
+		if _, err := Distribute(newTeams(tt.teams), tt.groups); err != ErrInvalidGroupCount {
+			t.Errorf("%d teams in %d groups: got %v, want %v", tt.teams, tt.groups, err, ErrInvalidGroupCount)
+		}
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	tests := []struct {
+		teams     int
+		double    bool
+		matches   int
+		matchdays int
+	}{
+		{2, false, 1, 1},
+		{3, false, 3, 3},
+		{4, false, 6, 3},
+		{5, false, 10, 5},
+		{4, true, 12, 6},
+		{5, true, 20, 10},
+	}
+	for _, tt := range tests {
+		group := model.TournamentGroup{Name: "A", TeamIDs: newTeams(tt.teams)}
+		matches := RoundRobin(primitive.NewObjectID(), []model.TournamentGroup{group}, tt.double, Schedule{})
+		if len(matches) != tt.matches {
+			t.Errorf("%d teams, double %v: got %d matches, want %d", tt.teams, tt.double, len(matches), tt.matches)
+		}
+
+		pairs := map[[2]primitive.ObjectID]int{}
+		playing := map[int]map[primitive.ObjectID]bool{}
+		matchdays := 0
+		for _, m := range matches {
+			if m.TeamAID == m.TeamBID || m.TeamAID.IsZero() || m.TeamBID.IsZero() {
+				t.Errorf("%d teams: match without two different teams", tt.teams)
+			}
+			if m.Group != "A" || m.Status != "scheduled" {
+				t.Errorf("%d teams: got group %q status %s", tt.teams, m.Group, m.Status)
+			}
+			pairs[[2]primitive.ObjectID{m.TeamAID, m.TeamBID}]++
+
+			if playing[m.Matchday] == nil {
+				playing[m.Matchday] = map[primitive.ObjectID]bool{}
+			}
+			if playing[m.Matchday][m.TeamAID] || playing[m.Matchday][m.TeamBID] {
+				t.Errorf("%d teams: a team plays twice on matchday %d", tt.teams, m.Matchday)
+			}
+			playing[m.Matchday][m.TeamAID] = true
+			playing[m.Matchday][m.TeamBID] = true
+			matchdays = max(matchdays, m.Matchday)
+		}
+		if matchdays != tt.matchdays {
+			t.Errorf("%d teams, double %v: got %d matchdays, want %d", tt.teams, tt.double, matchdays, tt.matchdays)
+		}
+
+		// Every pairing is played once per leg, with sides swapped in the second leg
+		for i, a := range group.TeamIDs {
+			for _, b := range group.TeamIDs[i+1:] {
+				ab, ba := pairs[[2]primitive.ObjectID{a, b}], pairs[[2]primitive.ObjectID{b, a}]
+				if tt.double && (ab != 1 || ba != 1) {
+					t.Errorf("%d teams, double: pairing played %d and %d times per side, want 1 and 1", tt.teams, ab, ba)
+				}
+				if !tt.double && ab+ba != 1 {
+					t.Errorf("%d teams: pairing played %d times, want 1", tt.teams, ab+ba)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package groupstage
+
+import (
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Standings computes the standings table of every group from its completed matches.
+// Only matches with both scores recorded count. Teams level on points are ordered by
+// the stage's tiebreakers in sequence, then by team ID so the order is stable.
+func Standings(stage model.GroupStage, matches []model.Match) []model.GroupStandings {
+	tiebreakers := stage.Tiebreakers
+	if len(tiebreakers) == 0 {
+		tiebreakers = DefaultTiebreakers
+	}
+
+	var result []model.GroupStandings
+	for _, group := range stage.Groups {
+		rows := make(map[primitive.ObjectID]*model.Standing, len(group.TeamIDs))
+		for _, teamID := range group.TeamIDs {
+			rows[teamID] = &model.Standing{TeamID: teamID}
+		}
+
+		var played []model.Match
+		for _, match := range matches {
+			if match.Group != group.Name || !counted(match) {
+				continue
+			}
+			a, okA := rows[match.TeamAID]
+			b, okB := rows[match.TeamBID]
+			if !okA || !okB {
+				continue
+			}
+			played = append(played, match)
+			record(a, b, *match.ResultTeamAScore, *match.ResultTeamBScore, stage)
+		}
+
+		table := make([]model.Standing, 0, len(rows))
+		for _, teamID := range group.TeamIDs {
+			table = append(table, *rows[teamID])
+		}
+		rank(table, played, tiebreakers, stage)
+		result = append(result, model.GroupStandings{Group: group.Name, Standings: table})
+	}
+	return result
+}
+
+// counted reports whether a match has a result that belongs in the standings. A forfeit
+// counts with its awarded score.
+func counted(match model.Match) bool {
+	return matchstate.Decided(match.Status) && match.ResultTeamAScore != nil && match.ResultTeamBScore != nil
+}
+
+// record adds one match result to both teams' rows.
+func record(a, b *model.Standing, scoreA, scoreB int, stage model.GroupStage) {
+	a.Played++
+	b.Played++
+	a.GamesWon += scoreA
+	a.GamesLost += scoreB
+	b.GamesWon += scoreB
+	b.GamesLost += scoreA
+	a.GameDiff = a.GamesWon - a.GamesLost
+	b.GameDiff = b.GamesWon - b.GamesLost
+
+	switch {
+	case scoreA > scoreB:
+		a.Wins++
+		b.Losses++
+		a.Points += stage.PointsPerWin
+	case scoreB > scoreA:
+		b.Wins++
+		a.Losses++
+		b.Points += stage.PointsPerWin
+	default:
+		a.Draws++
+		b.Draws++
+		a.Points += stage.PointsPerDraw
+		b.Points += stage.PointsPerDraw
+	}
+}
+
+// rank sorts a table by points, breaks ties among teams level on points and assigns ranks.
+func rank(table []model.Standing, played []model.Match, tiebreakers []string, stage model.GroupStage) {
+	sort.SliceStable(table, func(i, j int) bool {
+		return table[i].Points > table[j].Points
+	})
+
+	for start := 0; start < len(table); {
+		end := start + 1
+		for end < len(table) && table[end].Points == table[start].Points {
+			end++
+		}
+		if end-start > 1 {
+			breakTie(table[start:end], played, tiebreakers, stage)
+		}
+		start = end
+	}
+
+	for i := range table {
+		table[i].Rank = i + 1
+	}
+}
+
+// breakTie orders teams that are level on points.
+func breakTie(tied []model.Standing, played []model.Match, tiebreakers []string, stage model.GroupStage) {
+	headToHead := headToHeadPoints(tied, played, stage)
+
+	sort.SliceStable(tied, func(i, j int) bool {
+		a, b := tied[i], tied[j]
+		for _, tiebreaker := range tiebreakers {
+			var va, vb int
+			switch tiebreaker {
+			case TiebreakerHeadToHead:
+				va, vb = headToHead[a.TeamID], headToHead[b.TeamID]
+			case TiebreakerGameDiff:
+				va, vb = a.GameDiff, b.GameDiff
+			case TiebreakerGamesWon:
+				va, vb = a.GamesWon, b.GamesWon
+			}
+			if va != vb {
+				return va > vb
+			}
+		}
+		return a.TeamID.Hex() < b.TeamID.Hex()
+	})
+}
+
+// headToHeadPoints returns the points each tied team earned in matches among the tied teams only.
+func headToHeadPoints(tied []model.Standing, played []model.Match, stage model.GroupStage) map[primitive.ObjectID]int {
+	inTie := make(map[primitive.ObjectID]bool, len(tied))
+	for _, row := range tied {
+		inTie[row.TeamID] = true
+	}
+
+	points := make(map[primitive.ObjectID]int, len(tied))
+	for _, match := range played {
+		if !inTie[match.TeamAID] || !inTie[match.TeamBID] {
+			continue
+		}
+		scoreA, scoreB := *match.ResultTeamAScore, *match.ResultTeamBScore
+		switch {
+		case scoreA > scoreB:
+			points[match.TeamAID] += stage.PointsPerWin
+		case scoreB > scoreA:
+			points[match.TeamBID] += stage.PointsPerWin
+		default:
+			points[match.TeamAID] += stage.PointsPerDraw
+			points[match.TeamBID] += stage.PointsPerDraw
+		}
+	}
+	return points
+}
@@ -0,0 +1,145 @@
+package groupstage
+
+import (
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func result(a, b primitive.ObjectID, scoreA, scoreB int) model.Match {
+	return model.Match{
+		TeamAID:          a,
+		TeamBID:          b,
+		Group:            "A",
+		Status:           matchstate.Completed,
+		ResultTeamAScore: &scoreA,
+		ResultTeamBScore: &scoreB,
+	}
+}
+
+// order returns the team of every row as its index in teams.
+func order(t *testing.T, table []model.Standing, teams []primitive.ObjectID) []int {
+	t.Helper()
+	index := make(map[primitive.ObjectID]int, len(teams))
+	for i, team := range teams {
+		index[team] = i
+	}
+	got := make([]int, len(table))
+	for i, row := range table {
+		if row.Rank != i+1 {
+			t.Errorf("row %d has rank %d", i+1, row.Rank)
+		}
+		got[i] = index[row.TeamID]
+	}
+	return got
+}
+
+func TestStandingsRecord(t *testing.T) {
+	teams := newTeams(3)
+	stage := model.GroupStage{
+		Groups:        []model.TournamentGroup{{Name: "A", TeamIDs: teams}},
+		PointsPerWin:  3,
+		PointsPerDraw: 1,
+	}
+	scheduled := result(teams[1], teams[2], 2, 0)
+	scheduled.Status = matchstate.Scheduled
+	forfeit := result(teams[2], teams[0], 0, 1)
+	forfeit.Status = matchstate.Forfeited
+	otherGroup := result(teams[0], teams[1], 2, 0)
+	otherGroup.Group = "B"
+	matches := []model.Match{
+		result(teams[0], teams[1], 2, 1),
+		result(teams[1], teams[2], 1, 1),
+		forfeit,
+		scheduled,
+		otherGroup,
+	}
+
+	table := Standings(stage, matches)[0].Standings
+	want := []model.Standing{
+		{Rank: 1, TeamID: teams[0], Played: 2, Wins: 2, GamesWon: 3, GamesLost: 1, GameDiff: 2, Points: 6},
+		{Rank: 2, TeamID: teams[1], Played: 2, Draws: 1, Losses: 1, GamesWon: 2, GamesLost: 3, GameDiff: -1, Points: 1},
+		{Rank: 3, TeamID: teams[2], Played: 2, Draws: 1, Losses: 1, GamesWon: 1, GamesLost: 2, GameDiff: -1, Points: 1},
+	}
+	for i := range want {
+		if table[i] != want[i] {
+			t.Errorf("row %d: got %+v, want %+v", i+1, table[i], want[i])
+		}
+	}
+}
+
+func TestStandingsTiebreakers(t *testing.T) {
+	teams := newTeams(3)
+
+	// Each team beats one other, so all are level on points and head to head. Game differences
+	// are +2, -2 and 0, games won 5, 6 and 8
+	matches := []model.Match{
+		result(teams[0], teams[1], 3, 0),
+		result(teams[1], teams[2], 6, 5),
+		result(teams[2], teams[0], 3, 2),
+	}
+
+	tests := []struct {
+		name        string
+		tiebreakers []string
+		want        []int
+	}{
+		{"game difference", []string{TiebreakerGameDiff}, []int{0, 2, 1}},
+		{"games won", []string{TiebreakerGamesWon}, []int{2, 1, 0}},
+		{"level head to head falls through", []string{TiebreakerHeadToHead, TiebreakerGamesWon}, []int{2, 1, 0}},
+		{"default", nil, []int{0, 2, 1}},
+	}
+	for _, tt := range tests {
+		stage := model.GroupStage{
+			Groups:       []model.TournamentGroup{{Name: "A", TeamIDs: teams}},
+			PointsPerWin: 3,
+			Tiebreakers:  tt.tiebreakers,
+		}
+		got := order(t, Standings(stage, matches)[0].Standings, teams)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got order %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestStandingsHeadToHead(t *testing.T) {
+	teams := newTeams(4)
+
+	// Teams 0 and 1 finish level on 6 points. Team 1 won their match, team 0 has the better
+	// game difference (+7 against +1). The other teams are not part of the tie
+	matches := []model.Match{
+		result(teams[0], teams[2], 5, 0),
+		result(teams[1], teams[3], 1, 0),
+		result(teams[1], teams[0], 1, 0),
+		result(teams[0], teams[3], 3, 0),
+		result(teams[2], teams[1], 1, 0),
+	}
+
+	tests := []struct {
+		name        string
+		tiebreakers []string
+		want        []int
+	}{
+		{"head to head first", []string{TiebreakerHeadToHead, TiebreakerGameDiff}, []int{1, 0, 2, 3}},
+		{"game difference first", []string{TiebreakerGameDiff, TiebreakerHeadToHead}, []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		stage := model.GroupStage{
+			Groups:       []model.TournamentGroup{{Name: "A", TeamIDs: teams}},
+			PointsPerWin: 3,
+			Tiebreakers:  tt.tiebreakers,
+		}
+		got := order(t, Standings(stage, matches)[0].Standings, teams)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got order %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package lockout
+
+import "time"
+
+// Policy describes how failed logins are slowed down and when they lock a key out
+type Policy struct {
+	// MaxFailures within Window locks the key for LockDuration
+	MaxFailures  int
+	Window       time.Duration
+	LockDuration time.Duration
+	// BaseDelay doubles with every failure up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Decision is what a failed login means for the next attempt
+type Decision struct {
+	NextAttemptAt time.Time
+	LockedUntil   *time.Time
+}
+
+// Delay returns how long to wait after the given number of consecutive failures
+func (p Policy) Delay(failures int) time.Duration {
+	if failures <= 0 || p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// AfterFailure decides when the next attempt is allowed after the given number of failures.
+// Reaching MaxFailures locks the key; every further failure within the window locks it again
+func (p Policy) AfterFailure(failures int, now time.Time) Decision {
+	decision := Decision{NextAttemptAt: now.Add(p.Delay(failures))}
+	if p.MaxFailures > 0 && failures >= p.MaxFailures {
+		lockedUntil := now.Add(p.LockDuration)
+		decision.LockedUntil = &lockedUntil
+		decision.NextAttemptAt = lockedUntil
+	}
+	return decision
+}
+
+// WindowStart returns the time before which failures no longer count
+func (p Policy) WindowStart(now time.Time) time.Time {
+	return now.Add(-p.Window)
+}
@@ -0,0 +1,96 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	policy := Policy{BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{5, 16 * time.Second},
+		{6, 30 * time.Second},
+		{100, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.Delay(tt.failures); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestDelayLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		failures int
+		want     time.Duration
+	}{
+		{"no base delay", Policy{MaxDelay: time.Minute}, 3, 0},
+		{"no cap", Policy{BaseDelay: time.Second}, 8, 128 * time.Second},
+		{"base above cap", Policy{BaseDelay: time.Minute, MaxDelay: 10 * time.Second}, 1, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Delay(tt.failures); got != tt.want {
+			t.Errorf("%s: Delay(%d) = %v, want %v", tt.name, tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestAfterFailure(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := Policy{
+		MaxFailures:  5,
+		Window:       15 * time.Minute,
+		LockDuration: 15 * time.Minute,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+
+	tests := []struct {
+		failures int
+		next     time.Duration
+		locked   bool
+	}{
+		{1, time.Second, false},
+		{4, 8 * time.Second, false},
+		{5, 15 * time.Minute, true},
+		{7, 15 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		decision := policy.AfterFailure(tt.failures, now)
+		if want := now.Add(tt.next); !decision.NextAttemptAt.Equal(want) {
+			t.Errorf("%d failures: next attempt at %v, want %v", tt.failures, decision.NextAttemptAt, want)
+		}
+		if (decision.LockedUntil != nil) != tt.locked {
+			t.Errorf("%d failures: locked %v, want %v", tt.failures, decision.LockedUntil != nil, tt.locked)
+			continue
+		}
+		if tt.locked && !decision.LockedUntil.Equal(now.Add(policy.LockDuration)) {
+			t.Errorf("%d failures: locked until %v, want %v", tt.failures, *decision.LockedUntil, now.Add(policy.LockDuration))
+		}
+	}
+
+	// Without MaxFailures a key is only ever slowed down
+	policy.MaxFailures = 0
+	if decision := policy.AfterFailure(50, now); decision.LockedUntil != nil {
+		t.Errorf("no MaxFailures: locked until %v", *decision.LockedUntil)
+	}
+}
+
+func TestWindowStart(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := Policy{Window: 15 * time.Minute}
+	if got, want := policy.WindowStart(now), now.Add(-15*time.Minute); !got.Equal(want) {
+		t.Errorf("WindowStart = %v, want %v", got, want)
+	}
+}
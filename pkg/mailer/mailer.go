@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Link appends a token query parameter to a frontend URL
+func Link(baseURL, path, token string) string {
+	return baseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// VerificationEmail asks a new user to confirm their email address
+func VerificationEmail(to, username, link string, ttl time.Duration) Message {
+	return Message{
+		To:      to,
+		Subject: "Verifikasi email akun Anda",
+		Body: fmt.Sprintf("Halo %s,\n\n"+
+			"Terima kasih sudah mendaftar. Buka tautan berikut untuk memverifikasi email Anda:\n\n%s\n\n"+
+			"Tautan berlaku selama %s. Abaikan email ini jika Anda tidak merasa mendaftar.\n",
+			username, link, humanDuration(ttl)),
+	}
+}
+
+// PasswordResetEmail sends a user the link to choose a new password
+func PasswordResetEmail(to, username, link string, ttl time.Duration) Message {
+	return Message{
+		To:      to,
+		Subject: "Reset password akun Anda",
+		Body: fmt.Sprintf("Halo %s,\n\n"+
+			"Kami menerima permintaan untuk mereset password akun Anda. Buka tautan berikut untuk membuat password baru:\n\n%s\n\n"+
+			"Tautan hanya dapat dipakai sekali dan berlaku selama %s. Abaikan email ini jika Anda tidak memintanya.\n",
+			username, link, humanDuration(ttl)),
+	}
+}
+
+// humanDuration renders a link lifetime the way people read it, e.g. "48 jam" or "30 menit"
+func humanDuration(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%d jam", d/time.Hour)
+	}
+	return fmt.Sprintf("%d menit", d/time.Minute)
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP sends messages through an SMTP server. Without a username the server is used without
+// authentication, as local test servers such as MailHog or Mailpit expect
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send delivers the message to the SMTP server
+func (s SMTP) Send(ctx context.Context, message Message) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, s.From, []string{message.To}, s.compose(message))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// compose renders the message with the headers SMTP servers expect
+func (s SMTP) compose(message Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,171 @@
+package matchstate
+
+import (
+	"embeck/model"
+	"embeck/pkg/series"
+	"fmt"
+)
+
+// Match statuses.
+const (
+	Scheduled = "scheduled"
+	CheckedIn = "checked_in"
+	Ongoing   = "ongoing"
+	Completed = "completed"
+	Postponed = "postponed"
+	Cancelled = "cancelled"
+	Forfeited = "forfeited"
+)
+
+// Outcomes of a forfeited match.
+const (
+	OutcomeForfeit          = "forfeit"
+	OutcomeWalkover         = "walkover"
+	OutcomeDisqualification = "disqualification"
+)
+
+// ManualOutcomes lists the forfeit outcomes an admin may record directly; a
+// disqualification is recorded by disqualifying the team from the tournament.
+var ManualOutcomes = map[string]bool{OutcomeForfeit: true, OutcomeWalkover: true}
+
+// Transitions lists the statuses a match may move to from each status. Completed,
+// cancelled and forfeited are final.
+var Transitions = map[string][]string{
+	Scheduled: {CheckedIn, Postponed, Cancelled, Forfeited},
+	CheckedIn: {Ongoing, Postponed, Cancelled, Forfeited},
+	Ongoing:   {Completed, Postponed, Cancelled, Forfeited},
+	Postponed: {Scheduled, Cancelled, Forfeited},
+	Completed: {},
+	Cancelled: {},
+	Forfeited: {},
+}
+
+// InitialStatuses lists the statuses a match may be created with.
+var InitialStatuses = map[string]bool{Scheduled: true, Postponed: true}
+
+// Valid reports whether status is a known match status.
+func Valid(status string) bool {
+	_, ok := Transitions[status]
+	return ok
+}
+
+// CanTransition reports whether a match may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, next := range Transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Final reports whether a status ends the match.
+func Final(status string) bool {
+	return status == Completed || status == Cancelled || status == Forfeited
+}
+
+// Decided reports whether a match in this status has a final result that counts
+// towards brackets and standings.
+func Decided(status string) bool {
+	return status == Completed || status == Forfeited
+}
+
+// ForfeitScore returns the default score awarded for a forfeit: the wins needed to
+// take the series for the winner, nothing for the forfeiting team.
+func ForfeitScore(bestOf int) (winner, loser int) {
+	if bestOf == 0 {
+		return 1, 0
+	}
+	return series.WinsNeeded(bestOf), 0
+}
+
+// AcceptsGames reports whether games of a series may be recorded in this status.
+func AcceptsGames(status string) bool {
+	return status == CheckedIn || status == Ongoing || status == Completed
+}
+
+// DrawAllowed reports whether a match may end without a winner: group stage matches, whose
+// standings award points for a draw, and stand-alone even best-of series. Bracket and Swiss
+// matches always need a winner to pair or advance.
+func DrawAllowed(match model.Match) bool {
+	if match.Bracket != "" || match.BracketRound > 0 || match.SwissRound > 0 {
+		return false
+	}
+	return match.Group != "" || (match.BestOf > 0 && match.BestOf%2 == 0)
+}
+
+// ValidateResult checks that a match has both scores and a winner that is team A or
+// team B and agrees with the scores. In a best-of-N series the winner must have
+// exactly the number of wins that decides the series. A match that may be drawn can
+// have level scores and no winner; an even best-of draw must have all games played.
+func ValidateResult(match model.Match) error {
+	if match.ResultTeamAScore == nil || match.ResultTeamBScore == nil {
+		return fmt.Errorf("Skor team A dan team B wajib diisi")
+	}
+	if *match.ResultTeamAScore < 0 || *match.ResultTeamBScore < 0 {
+		return fmt.Errorf("Skor tidak boleh bernilai negatif")
+	}
+
+	scoreA, scoreB := *match.ResultTeamAScore, *match.ResultTeamBScore
+	if match.WinnerTeamID == nil {
+		if scoreA != scoreB || !DrawAllowed(match) {
+			return fmt.Errorf("Winner team wajib diisi")
+		}
+		if match.BestOf > 0 && match.BestOf%2 == 0 && scoreA+scoreB != match.BestOf {
+			return fmt.Errorf("Hasil seri best of %d harus %d-%d", match.BestOf, match.BestOf/2, match.BestOf/2)
+		}
+		return nil
+	}
+
+	switch *match.WinnerTeamID {
+	case match.TeamAID:
+		if scoreA <= scoreB {
+			return fmt.Errorf("Winner team A tidak sesuai dengan skor %d-%d", scoreA, scoreB)
+		}
+	case match.TeamBID:
+		if scoreB <= scoreA {
+			return fmt.Errorf("Winner team B tidak sesuai dengan skor %d-%d", scoreA, scoreB)
+		}
+	default:
+		return fmt.Errorf("Winner team harus team A atau team B")
+	}
+
+	if match.BestOf > 0 {
+		needed := series.WinsNeeded(match.BestOf)
+		if max(scoreA, scoreB) != needed {
+			return fmt.Errorf("Skor pemenang harus %d untuk best of %d", needed, match.BestOf)
+		}
+	}
+	return nil
+}
+
+// SeriesPath returns the statuses a match moves through when its recorded games change the
+// series, each step allowed by Transitions. started is whether any game counts and finished
+// whether the games decide or draw the series. A completed match is final, so games that
+// reopen its series are refused.
+func SeriesPath(from string, started, finished bool) ([]string, error) {
+	var path []string
+	switch {
+	case from == Completed:
+		if !finished {
+			return nil, fmt.Errorf("Match sudah completed, perubahan game ini akan membatalkan hasil series")
+		}
+		return nil, nil
+	case finished:
+		if from == CheckedIn {
+			path = append(path, Ongoing)
+		}
+		path = append(path, Completed)
+	case started && from == CheckedIn:
+		path = append(path, Ongoing)
+	}
+
+	current := from
+	for _, next := range path {
+		if !CanTransition(current, next) {
+			return nil, fmt.Errorf("Status match tidak dapat diubah dari %s ke %s", current, next)
+		}
+		current = next
+	}
+	return path, nil
+}
@@ -0,0 +1,197 @@
+package matchstate
+
+import (
+	"embeck/model"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{Scheduled, CheckedIn, true},
+		{Scheduled, Ongoing, false},
+		{Scheduled, Completed, false},
+		{Scheduled, Postponed, true},
+		{Scheduled, Forfeited, true},
+		{CheckedIn, Ongoing, true},
+		{CheckedIn, Completed, false},
+		{CheckedIn, Scheduled, false},
+		{Ongoing, Completed, true},
+		{Ongoing, CheckedIn, false},
+		{Postponed, Scheduled, true},
+		{Postponed, Ongoing, false},
+		{Completed, Ongoing, false},
+		{Completed, Forfeited, false},
+		{Cancelled, Scheduled, false},
+		{Forfeited, Completed, false},
+		{"unknown", Scheduled, false},
+		{Scheduled, Scheduled, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestStatusKinds(t *testing.T) {
+	tests := []struct {
+		status  string
+		valid   bool
+		final   bool
+		decided bool
+		games   bool
+	}{
+		{Scheduled, true, false, false, false},
+		{CheckedIn, true, false, false, true},
+		{Ongoing, true, false, false, true},
+		{Postponed, true, false, false, false},
+		{Completed, true, true, true, true},
+		{Cancelled, true, true, false, false},
+		{Forfeited, true, true, true, false},
+		{"unknown", false, false, false, false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.status); got != tt.valid {
+			t.Errorf("Valid(%s) = %v, want %v", tt.status, got, tt.valid)
+		}
+		if got := Final(tt.status); got != tt.final {
+			t.Errorf("Final(%s) = %v, want %v", tt.status, got, tt.final)
+		}
+		if got := Decided(tt.status); got != tt.decided {
+			t.Errorf("Decided(%s) = %v, want %v", tt.status, got, tt.decided)
+		}
+		if got := AcceptsGames(tt.status); got != tt.games {
+			t.Errorf("AcceptsGames(%s) = %v, want %v", tt.status, got, tt.games)
+		}
+	}
+
+	// Final statuses have no way out
+	for status, next := range Transitions {
+		if Final(status) && len(next) > 0 {
+			t.Errorf("final status %s has transitions %v", status, next)
+		}
+	}
+}
+
+func TestForfeitScore(t *testing.T) {
+	tests := []struct {
+		bestOf        int
+		winner, loser int
+	}{
+		{0, 1, 0},
+		{1, 1, 0},
+		{3, 2, 0},
+		{5, 3, 0},
+		{7, 4, 0},
+	}
+	for _, tt := range tests {
+		winner, loser := ForfeitScore(tt.bestOf)
+		if winner != tt.winner || loser != tt.loser {
+			t.Errorf("ForfeitScore(%d) = %d-%d, want %d-%d", tt.bestOf, winner, loser, tt.winner, tt.loser)
+		}
+	}
+}
+
+func TestDrawAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		match model.Match
+		want  bool
+	}{
+		{"group match", model.Match{Group: "A"}, true},
+		{"group best of 3", model.Match{Group: "A", BestOf: 3}, true},
+		{"stand-alone best of 2", model.Match{BestOf: 2}, true},
+		{"stand-alone best of 3", model.Match{BestOf: 3}, false},
+		{"stand-alone without best of", model.Match{}, false},
+		{"bracket match", model.Match{Bracket: "main", BracketRound: 1, BestOf: 2}, false},
+		{"swiss match", model.Match{SwissRound: 1, BestOf: 2}, false},
+	}
+	for _, tt := range tests {
+		if got := DrawAllowed(tt.match); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateResult(t *testing.T) {
+	a, b, other := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	result := func(bestOf, scoreA, scoreB int, winner *primitive.ObjectID) model.Match {
+		return model.Match{
+			TeamAID:          a,
+			TeamBID:          b,
+			BestOf:           bestOf,
+			ResultTeamAScore: &scoreA,
+			ResultTeamBScore: &scoreB,
+			WinnerTeamID:     winner,
+		}
+	}
+	drawable := func(m model.Match) model.Match {
+		m.Group = "A"
+		return m
+	}
+
+	tests := []struct {
+		name  string
+		match model.Match
+		ok    bool
+	}{
+		{"team A wins", result(0, 2, 1, &a), true},
+		{"team B wins", result(0, 0, 1, &b), true},
+		{"best of 3 won 2-1", result(3, 2, 1, &a), true},
+		{"best of 5 won 3-0", result(5, 0, 3, &b), true},
+		{"group draw", drawable(result(0, 1, 1, nil)), true},
+		{"best of 2 draw", result(2, 1, 1, nil), true},
+		{"missing scores", model.Match{TeamAID: a, TeamBID: b, WinnerTeamID: &a}, false},
+		{"negative score", result(0, -1, 0, &b), false},
+		{"winner disagrees with scores", result(0, 1, 2, &a), false},
+		{"winner on level scores", result(0, 1, 1, &a), false},
+		{"winner outside the match", result(0, 2, 0, &other), false},
+		{"best of 3 won 3-1", result(3, 3, 1, &a), false},
+		{"best of 3 won 1-0", result(3, 1, 0, &a), false},
+		{"draw where a winner is needed", result(0, 1, 1, nil), false},
+		{"draw with unequal scores", drawable(result(0, 2, 1, nil)), false},
+		{"best of 2 draw with games missing", result(2, 0, 0, nil), false},
+	}
+	for _, tt := range tests {
+		err := ValidateResult(tt.match)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got error %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestSeriesPath(t *testing.T) {
+	tests := []struct {
+		from              string
+		started, finished bool
+		want              []string
+		ok                bool
+	}{
+		{CheckedIn, false, false, nil, true},
+		{CheckedIn, true, false, []string{Ongoing}, true},
+		{CheckedIn, true, true, []string{Ongoing, Completed}, true},
+		{Ongoing, true, false, nil, true},
+		{Ongoing, true, true, []string{Completed}, true},
+		{Ongoing, false, false, nil, true},
+		{Completed, true, true, nil, true},
+		{Completed, true, false, nil, false},
+		{Scheduled, true, true, nil, false},
+		{Postponed, true, true, nil, false},
+	}
+	for _, tt := range tests {
+		path, err := SeriesPath(tt.from, tt.started, tt.finished)
+		if (err == nil) != tt.ok {
+			t.Errorf("SeriesPath(%s, %v, %v): got error %v, want ok %v", tt.from, tt.started, tt.finished, err, tt.ok)
+			continue
+		}
+		if !reflect.DeepEqual(path, tt.want) {
+			t.Errorf("SeriesPath(%s, %v, %v) = %v, want %v", tt.from, tt.started, tt.finished, path, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package permission
+
+import (
+	"embeck/model"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Permissions checked by the staff routes under /api/admin
+const (
+	TournamentManage   = "tournament:manage"
+	RegistrationReview = "registration:review"
+	MatchManage        = "match:manage"
+	MatchScore         = "match:score"
+	TeamManage         = "team:manage"
+	PlayerManage       = "player:manage"
+	TicketScan         = "ticket:scan"
+	FinanceView        = "finance:view"
+	UserManage         = "user:manage"
+	RoleManage         = "role:manage"
+	AuditView          = "audit:view"
+	TrashManage        = "trash:manage"
+)
+
+// Admin is the user role that holds every permission. It is set on the user itself and is not
+// handed out through role assignments
+const Admin = "admin"
+
+// roles maps the staff roles that can be assigned to users to their permissions
+var roles = map[string][]string{
+	"organizer":    {TournamentManage, RegistrationReview, MatchManage, MatchScore, TeamManage, PlayerManage},
+	"referee":      {MatchScore},
+	"ticket_staff": {TicketScan},
+	"finance":      {FinanceView},
+}
+
+// Valid reports whether a staff role exists
+func Valid(role string) bool {
+	_, ok := roles[role]
+	return ok
+}
+
+// Roles lists the staff roles with their permissions, sorted by role name
+func Roles() []model.RoleInfo {
+	infos := make([]model.RoleInfo, 0, len(roles))
+	for role, permissions := range roles {
+		infos = append(infos, model.RoleInfo{Role: role, Permissions: permissions})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Role < infos[j].Role })
+	return infos
+}
+
+// Has reports whether a staff role includes a permission
+func Has(role string, permission string) bool {
+	for _, granted := range roles[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether any of the assignments grants the permission. Assignments scoped to a
+// tournament only count when the request acts on that tournament
+func Allows(assignments []model.RoleAssignment, permission string, tournamentID *primitive.ObjectID) bool {
+	for _, assignment := range assignments {
+		if !Has(assignment.Role, permission) {
+			continue
+		}
+		if assignment.TournamentID == nil || (tournamentID != nil && *assignment.TournamentID == *tournamentID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tournaments returns the tournaments the assignments grant the permission for. all is true when
+// an unscoped assignment grants it everywhere, in which case the list is not meaningful
+func Tournaments(assignments []model.RoleAssignment, permission string) (ids []primitive.ObjectID, all bool) {
+	for _, assignment := range assignments {
+		if !Has(assignment.Role, permission) {
+			continue
+		}
+		if assignment.TournamentID == nil {
+			return nil, true
+		}
+		ids = append(ids, *assignment.TournamentID)
+	}
+	return ids, false
+}
@@ -0,0 +1,54 @@
+package playerstats
+
+import (
+	"embeck/model"
+	"math"
+)
+
+// Averages fills in the derived averages, win rate and KDA of a summary from its totals.
+// KDA follows the usual (kills + assists) / deaths, with zero deaths counted as one.
+func Averages(s *model.PlayerStatsSummary) {
+	if s.Games == 0 {
+		return
+	}
+
+	games := float64(s.Games)
+	s.WinRate = round(float64(s.Wins) / games)
+	s.AvgKills = round(float64(s.Kills) / games)
+	s.AvgDeaths = round(float64(s.Deaths) / games)
+	s.AvgAssists = round(float64(s.Assists) / games)
+	s.AvgGold = round(float64(s.Gold) / games)
+	s.AvgHeroDamage = round(float64(s.HeroDamage) / games)
+	s.AvgTurretDamage = round(float64(s.TurretDamage) / games)
+
+	deaths := s.Deaths
+	if deaths == 0 {
+		deaths = 1
+	}
+	s.KDA = round(float64(s.Kills+s.Assists) / float64(deaths))
+}
+
+// Career sums per-tournament totals into a career summary and fills in the averages
+// of the career and of every tournament.
+func Career(tournaments []model.TournamentPlayerStats) model.PlayerStatsSummary {
+	var career model.PlayerStatsSummary
+	for i := range tournaments {
+		t := &tournaments[i].PlayerStatsSummary
+		career.Games += t.Games
+		career.Wins += t.Wins
+		career.MVPCount += t.MVPCount
+		career.Kills += t.Kills
+		career.Deaths += t.Deaths
+		career.Assists += t.Assists
+		career.Gold += t.Gold
+		career.HeroDamage += t.HeroDamage
+		career.TurretDamage += t.TurretDamage
+		Averages(t)
+	}
+	Averages(&career)
+	return career
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,117 @@
+package roster
+
+import (
+	"embeck/model"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Member roles.
+const (
+	Starter    = "starter"
+	Substitute = "substitute"
+	Coach      = "coach"
+)
+
+// An MLBB roster fields 5 starters, with up to 2 substitutes and a coach.
+const (
+	Starters       = 5
+	MaxSubstitutes = 2
+	MaxCoaches     = 1
+)
+
+// Limits holds the maximum number of members per role.
+var Limits = map[string]int{Starter: Starters, Substitute: MaxSubstitutes, Coach: MaxCoaches}
+
+// ValidRole reports whether role is a known member role.
+func ValidRole(role string) bool {
+	_, ok := Limits[role]
+	return ok
+}
+
+// Validate checks that every member has a known role, appears once, and that no
+// role exceeds its limit. A complete roster must also have all 5 starters. The
+// captain has to be a starter or substitute, not the coach.
+func Validate(members []model.TeamMember, captainID primitive.ObjectID, complete bool) error {
+	seen := make(map[primitive.ObjectID]bool, len(members))
+	counts := make(map[string]int, len(Limits))
+	captainPlays := false
+
+	for _, member := range members {
+		if !ValidRole(member.Role) {
+			return fmt.Errorf("Role %q tidak valid, gunakan starter, substitute atau coach", member.Role)
+		}
+		if seen[member.PlayerID] {
+			return fmt.Errorf("Player %s terdaftar lebih dari sekali di roster", member.PlayerID.Hex())
+		}
+		seen[member.PlayerID] = true
+		counts[member.Role]++
+
+		if member.PlayerID == captainID && member.Role != Coach {
+			captainPlays = true
+		}
+	}
+
+	for _, role := range []string{Starter, Substitute, Coach} {
+		if counts[role] > Limits[role] {
+			return fmt.Errorf("Roster maksimal memiliki %d %s", Limits[role], role)
+		}
+	}
+	if complete && counts[Starter] < Starters {
+		return fmt.Errorf("Roster harus memiliki %d starter", Starters)
+	}
+	if !captainID.IsZero() && !captainPlays {
+		return fmt.Errorf("Captain harus terdaftar sebagai starter atau substitute")
+	}
+	return nil
+}
+
+// Players returns the starters and substitutes of a roster, the players listed in Team.Members.
+func Players(members []model.TeamMember) []primitive.ObjectID {
+	players := make([]primitive.ObjectID, 0, len(members))
+	for _, member := range members {
+		if member.Role != Coach {
+			players = append(players, member.PlayerID)
+		}
+	}
+	return players
+}
+
+// FromMembers builds a roster from a flat member list: the first 5 players start and the
+// rest are substitutes. Used for teams created before member roles existed.
+func FromMembers(members []primitive.ObjectID) []model.TeamMember {
+	roster := make([]model.TeamMember, len(members))
+	for i, playerID := range members {
+		role := Starter
+		if i >= Starters {
+			role = Substitute
+		}
+		roster[i] = model.TeamMember{PlayerID: playerID, Role: role}
+	}
+	return roster
+}
+
+// Of returns the roster of a team, derived from its members if it has none stored.
+func Of(team model.Team) []model.TeamMember {
+	if len(team.Roster) == 0 {
+		return FromMembers(team.Members)
+	}
+	return team.Roster
+}
+
+// NextRole returns the role a player joining the roster gets: a starter while the
+// starting five is incomplete, otherwise a substitute.
+func NextRole(members []model.TeamMember) (string, error) {
+	counts := make(map[string]int, len(Limits))
+	for _, member := range members {
+		counts[member.Role]++
+	}
+	switch {
+	case counts[Starter] < Starters:
+		return Starter, nil
+	case counts[Substitute] < MaxSubstitutes:
+		return Substitute, nil
+	}
+	return "", fmt.Errorf("Roster sudah penuh (%d starter dan %d substitute)", Starters, MaxSubstitutes)
+}
@@ -0,0 +1,19 @@
+package scheduler
+
+import "time"
+
+// Clock tells the scheduler the current time. Tests inject a fixed or stepped clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock.
+type ClockFunc func() time.Time
+
+// Now returns the time reported by the function.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the wall clock.
+var SystemClock Clock = ClockFunc(time.Now)
@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"context"
+	"embeck/model"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Store is the data the scheduler reads and updates.
+type Store interface {
+	// ActiveTournaments returns the tournaments that are upcoming or ongoing.
+	ActiveTournaments(ctx context.Context) ([]model.Tournament, error)
+	// TournamentMatches returns every match of a tournament.
+	TournamentMatches(ctx context.Context, tournamentID primitive.ObjectID) ([]model.Match, error)
+	// SetTournamentStatus moves a tournament from one status to another.
+	SetTournamentStatus(ctx context.Context, tournamentID primitive.ObjectID, from, to string) error
+	// OpenMatches returns the matches that have not started yet or are flagged as overdue.
+	OpenMatches(ctx context.Context) ([]model.Match, error)
+	// SetMatchOverdue sets or clears the overdue flag of a match.
+	SetMatchOverdue(ctx context.Context, matchID primitive.ObjectID, overdue bool) error
+}
+
+// Scheduler periodically brings tournament statuses in line with their matches and
+// flags matches that are past their start time without having started.
+type Scheduler struct {
+	Store    Store
+	Clock    Clock
+	Interval time.Duration
+	Grace    time.Duration
+	Location *time.Location
+}
+
+// New returns a scheduler using the wall clock, a one-minute interval, a 30-minute
+// grace period for overdue matches and Asia/Jakarta for match times without a zone.
+func New(store Store) *Scheduler {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		loc = time.FixedZone("WIB", 7*3600)
+	}
+	return &Scheduler{
+		Store:    store,
+		Clock:    SystemClock,
+		Interval: time.Minute,
+		Grace:    30 * time.Minute,
+		Location: loc,
+	}
+}
+
+// Run ticks until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx); err != nil {
+			log.Printf("Scheduler - Tick: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick runs one pass over tournaments and matches at the clock's current time.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	now := s.Clock.Now()
+
+	if err := s.progressTournaments(ctx, now); err != nil {
+		return err
+	}
+	return s.flagOverdueMatches(ctx, now)
+}
+
+func (s *Scheduler) progressTournaments(ctx context.Context, now time.Time) error {
+	tournaments, err := s.Store.ActiveTournaments(ctx)
+	if err != nil {
+		return fmt.Errorf("active tournaments: %w", err)
+	}
+
+	// One tournament failing is logged and retried next tick without holding up the others
+	for _, tournament := range tournaments {
+		if err := s.progressTournament(ctx, tournament, now); err != nil {
+			log.Printf("Scheduler - Tournament %s: %v", tournament.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) progressTournament(ctx context.Context, tournament model.Tournament, now time.Time) error {
+	matches, err := s.Store.TournamentMatches(ctx, tournament.ID)
+	if err != nil {
+		return fmt.Errorf("matches: %w", err)
+	}
+
+	status := TournamentStatus(tournament, matches, now)
+	if status == tournament.Status {
+		return nil
+	}
+	if err := s.Store.SetTournamentStatus(ctx, tournament.ID, tournament.Status, status); err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+	return nil
+}
+
+func (s *Scheduler) flagOverdueMatches(ctx context.Context, now time.Time) error {
+	matches, err := s.Store.OpenMatches(ctx)
+	if err != nil {
+		return fmt.Errorf("open matches: %w", err)
+	}
+
+	for _, match := range matches {
+		overdue := Overdue(match, now, s.Grace, s.Location)
+		if overdue == match.Overdue {
+			continue
+		}
+		if err := s.Store.SetMatchOverdue(ctx, match.ID, overdue); err != nil {
+			log.Printf("Scheduler - Overdue flag of match %s: %v", match.ID.Hex(), err)
+		}
+	}
+	return nil
+}
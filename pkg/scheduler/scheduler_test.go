@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// brokenStore serves several tournaments and fails to load the matches of one of them.
+type brokenStore struct {
+	fakeStore
+	tournaments []model.Tournament
+	broken      primitive.ObjectID
+	statuses    map[primitive.ObjectID]string
+}
+
+func (s *brokenStore) ActiveTournaments(ctx context.Context) ([]model.Tournament, error) {
+	return s.tournaments, nil
+}
+
+func (s *brokenStore) TournamentMatches(ctx context.Context, tournamentID primitive.ObjectID) ([]model.Match, error) {
+	if tournamentID == s.broken {
+		return nil, errors.New("connection reset")
+	}
+	return []model.Match{match(matchstate.Scheduled)}, nil
+}
+
+func (s *brokenStore) SetTournamentStatus(ctx context.Context, tournamentID primitive.ObjectID, from, to string) error {
+	s.statuses[tournamentID] = to
+	return nil
+}
+
+func TestTickContinuesAfterTournamentError(t *testing.T) {
+	first := newTournament(TournamentUpcoming)
+	second := newTournament(TournamentUpcoming)
+	store := &brokenStore{
+		tournaments: []model.Tournament{first, second},
+		broken:      first.ID,
+		statuses:    map[primitive.ObjectID]string{},
+	}
+	s := &Scheduler{Store: store, Clock: &steppedClock{now: startDate.Add(time.Hour)}}
+
+	tick(t, s)
+	if _, ok := store.statuses[first.ID]; ok {
+		t.Errorf("broken tournament: status changed to %s", store.statuses[first.ID])
+	}
+	if got := store.statuses[second.ID]; got != TournamentOngoing {
+		t.Errorf("next tournament: got %q, want %s", got, TournamentOngoing)
+	}
+}
@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"embeck/model"
+	"embeck/pkg/bracket"
+	"embeck/pkg/matchstate"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tournament statuses.
+const (
+	TournamentUpcoming  = "upcoming"
+	TournamentOngoing   = "ongoing"
+	TournamentCompleted = "completed"
+)
+
+// zoneOffsets maps the Indonesian time zone suffixes used in match times to UTC offsets.
+var zoneOffsets = map[string]int{"WIB": 7, "WITA": 8, "WIT": 9}
+
+var matchTimePattern = regexp.MustCompile(`^(\d{1,2})[:.](\d{2})\s*([A-Za-z]*)$`)
+
+// TournamentStatus returns the status a tournament should have given its matches. An
+// upcoming tournament starts once its start date passes or any match gets under way.
+// An ongoing one completes when every match is finished, at least one was decided and
+// its last stage is over: the bracket final or grand final has been played, or the last
+// Swiss round. Without either, more matches can still follow, so the tournament
+// completes only once its end date has passed as well.
+func TournamentStatus(tournament model.Tournament, matches []model.Match, now time.Time) string {
+	status := tournament.Status
+
+	if status == TournamentUpcoming {
+		started := !tournament.StartDate.IsZero() && !now.Before(tournament.StartDate)
+		for _, match := range matches {
+			if match.Status != matchstate.Scheduled && match.Status != matchstate.Postponed && match.Status != matchstate.Cancelled && !match.IsBye {
+				started = true
+				break
+			}
+		}
+		if !started {
+			return status
+		}
+		status = TournamentOngoing
+	}
+
+	if status != TournamentOngoing || len(matches) == 0 {
+		return status
+	}
+
+	decided := false
+	for _, match := range matches {
+		if !matchstate.Final(match.Status) {
+			return status
+		}
+		if matchstate.Decided(match.Status) && !match.IsBye {
+			decided = true
+		}
+	}
+	if !decided || !stagesOver(tournament, matches, now) {
+		return status
+	}
+	return TournamentCompleted
+}
+
+// stagesOver reports whether no stage of a tournament is left to play. A bracket is the
+// last stage when there is one, then a Swiss stage; a group stage may still be followed
+// by a bracket generated later.
+func stagesOver(tournament model.Tournament, matches []model.Match, now time.Time) bool {
+	if final := decidingMatch(matches); final != nil {
+		return matchstate.Final(final.Status)
+	}
+	if tournament.Swiss != nil {
+		return tournament.Swiss.TotalRounds > 0 && tournament.Swiss.CurrentRound >= tournament.Swiss.TotalRounds
+	}
+	return !tournament.EndDate.IsZero() && now.After(tournament.EndDate)
+}
+
+// decidingMatch returns the bracket match that decides the tournament: the grand final
+// reset when it is played, otherwise the grand final or the single elimination final.
+func decidingMatch(matches []model.Match) *model.Match {
+	var final *model.Match
+	for i, match := range matches {
+		switch {
+		case match.Bracket == bracket.BracketGrandFinalReset:
+			if match.Status != matchstate.Cancelled {
+				return &matches[i]
+			}
+		case match.Bracket != "" && match.NextMatchID == nil && match.Bracket != bracket.BracketLower:
+			final = &matches[i]
+		}
+	}
+	return final
+}
+
+// MatchStart returns when a match is due to start. MatchTime is read as "HH:MM" with an
+// optional WIB/WITA/WIT suffix, falling back to loc; when it cannot be read the match is
+// taken to run until the end of its day.
+func MatchStart(match model.Match, loc *time.Location) time.Time {
+	year, month, day := match.MatchDate.In(loc).Date()
+
+	parts := matchTimePattern.FindStringSubmatch(strings.TrimSpace(match.MatchTime))
+	if parts == nil {
+		return time.Date(year, month, day, 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	}
+
+	hour, _ := strconv.Atoi(parts[1])
+	minute, _ := strconv.Atoi(parts[2])
+	if hour > 23 || minute > 59 {
+		return time.Date(year, month, day, 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	}
+
+	zone := loc
+	if offset, ok := zoneOffsets[strings.ToUpper(parts[3])]; ok {
+		zone = time.FixedZone(strings.ToUpper(parts[3]), offset*3600)
+	}
+	return time.Date(year, month, day, hour, minute, 0, 0, zone)
+}
+
+// Overdue reports whether a match that has not started yet is past its start time by
+// more than the grace period.
+func Overdue(match model.Match, now time.Time, grace time.Duration, loc *time.Location) bool {
+	if match.Status != matchstate.Scheduled && match.Status != matchstate.CheckedIn {
+		return false
+	}
+	return now.After(MatchStart(match, loc).Add(grace))
+}
@@ -0,0 +1,255 @@
+package scheduler
+
+import (
+	"context"
+	"embeck/model"
+	"embeck/pkg/bracket"
+	"embeck/pkg/matchstate"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	startDate = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	endDate   = time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC)
+)
+
+// steppedClock is a fake Clock that only moves when the test advances it.
+type steppedClock struct {
+	now time.Time
+}
+
+func (c *steppedClock) Now() time.Time {
+	return c.now
+}
+
+func (c *steppedClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// fakeStore keeps one tournament and its matches in memory.
+type fakeStore struct {
+	tournament model.Tournament
+	matches    []model.Match
+}
+
+func (s *fakeStore) ActiveTournaments(ctx context.Context) ([]model.Tournament, error) {
+	if s.tournament.Status == TournamentCompleted {
+		return nil, nil
+	}
+	return []model.Tournament{s.tournament}, nil
+}
+
+func (s *fakeStore) TournamentMatches(ctx context.Context, tournamentID primitive.ObjectID) ([]model.Match, error) {
+	return s.matches, nil
+}
+
+func (s *fakeStore) SetTournamentStatus(ctx context.Context, tournamentID primitive.ObjectID, from, to string) error {
+	if s.tournament.Status == from {
+		s.tournament.Status = to
+	}
+	return nil
+}
+
+func (s *fakeStore) OpenMatches(ctx context.Context) ([]model.Match, error) {
+	return nil, nil
+}
+
+func (s *fakeStore) SetMatchOverdue(ctx context.Context, matchID primitive.ObjectID, overdue bool) error {
+	return nil
+}
+
+func newTournament(status string) model.Tournament {
+	return model.Tournament{
+		ID:        primitive.NewObjectID(),
+		Status:    status,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+}
+
+func match(status string) model.Match {
+	return model.Match{ID: primitive.NewObjectID(), Status: status}
+}
+
+func groupMatch(status string) model.Match {
+	m := match(status)
+	m.Group = "A"
+	return m
+}
+
+func swissMatch(round int, status string) model.Match {
+	m := match(status)
+	m.SwissRound = round
+	return m
+}
+
+func bracketMatch(section string, round int, next *primitive.ObjectID, status string) model.Match {
+	m := match(status)
+	m.Bracket = section
+	m.BracketRound = round
+	m.NextMatchID = next
+	return m
+}
+
+// singleElimination returns the two semifinals and the final of a four-team bracket.
+func singleElimination(semis, final string) []model.Match {
+	f := bracketMatch(bracket.BracketMain, 2, nil, final)
+	return []model.Match{
+		bracketMatch(bracket.BracketMain, 1, &f.ID, semis),
+		bracketMatch(bracket.BracketMain, 1, &f.ID, semis),
+		f,
+	}
+}
+
+func tick(t *testing.T, s *Scheduler) {
+	t.Helper()
+	if err := s.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+}
+
+func TestTournamentStatusStart(t *testing.T) {
+	clock := &steppedClock{now: startDate.Add(-time.Hour)}
+	store := &fakeStore{
+		tournament: newTournament(TournamentUpcoming),
+		matches:    []model.Match{match(matchstate.Scheduled)},
+	}
+	s := &Scheduler{Store: store, Clock: clock}
+
+	tick(t, s)
+	if store.tournament.Status != TournamentUpcoming {
+		t.Fatalf("before start date: got %s, want %s", store.tournament.Status, TournamentUpcoming)
+	}
+
+	clock.Advance(time.Hour)
+	tick(t, s)
+	if store.tournament.Status != TournamentOngoing {
+		t.Fatalf("at start date: got %s, want %s", store.tournament.Status, TournamentOngoing)
+	}
+}
+
+func TestTournamentStatusGroupStageBeforePlayoffs(t *testing.T) {
+	clock := &steppedClock{now: startDate.Add(48 * time.Hour)}
+	store := &fakeStore{
+		tournament: newTournament(TournamentOngoing),
+		matches: []model.Match{
+			groupMatch(matchstate.Completed),
+			groupMatch(matchstate.Completed),
+			groupMatch(matchstate.Forfeited),
+		},
+	}
+	store.tournament.GroupStage = &model.GroupStage{}
+	s := &Scheduler{Store: store, Clock: clock}
+
+	tick(t, s)
+	if store.tournament.Status != TournamentOngoing {
+		t.Fatalf("finished group stage: got %s, want %s", store.tournament.Status, TournamentOngoing)
+	}
+
+	// Playoffs are generated and played out
+	store.matches = append(store.matches, singleElimination(matchstate.Completed, matchstate.Scheduled)...)
+	tick(t, s)
+	if store.tournament.Status != TournamentOngoing {
+		t.Fatalf("final not played: got %s, want %s", store.tournament.Status, TournamentOngoing)
+	}
+
+	store.matches[len(store.matches)-1].Status = matchstate.Completed
+	tick(t, s)
+	if store.tournament.Status != TournamentCompleted {
+		t.Fatalf("final played: got %s, want %s", store.tournament.Status, TournamentCompleted)
+	}
+}
+
+func TestTournamentStatusSwissRounds(t *testing.T) {
+	clock := &steppedClock{now: startDate.Add(24 * time.Hour)}
+	store := &fakeStore{
+		tournament: newTournament(TournamentOngoing),
+		matches:    []model.Match{swissMatch(1, matchstate.Completed), swissMatch(1, matchstate.Completed)},
+	}
+	store.tournament.Swiss = &model.SwissStage{TotalRounds: 2, CurrentRound: 1}
+	s := &Scheduler{Store: store, Clock: clock}
+
+	tick(t, s)
+	if store.tournament.Status != TournamentOngoing {
+		t.Fatalf("between rounds: got %s, want %s", store.tournament.Status, TournamentOngoing)
+	}
+
+	store.tournament.Swiss.CurrentRound = 2
+	store.matches = append(store.matches, swissMatch(2, matchstate.Ongoing), swissMatch(2, matchstate.Completed))
+	clock.Advance(24 * time.Hour)
+	tick(t, s)
+	if store.tournament.Status != TournamentOngoing {
+		t.Fatalf("last round under way: got %s, want %s", store.tournament.Status, TournamentOngoing)
+	}
+
+	store.matches[2].Status = matchstate.Completed
+	tick(t, s)
+	if store.tournament.Status != TournamentCompleted {
+		t.Fatalf("last round played: got %s, want %s", store.tournament.Status, TournamentCompleted)
+	}
+}
+
+func TestTournamentStatusMatchesCreatedStepByStep(t *testing.T) {
+	clock := &steppedClock{now: startDate.Add(2 * time.Hour)}
+	store := &fakeStore{
+		tournament: newTournament(TournamentOngoing),
+		matches:    []model.Match{match(matchstate.Completed)},
+	}
+	s := &Scheduler{Store: store, Clock: clock}
+
+	tick(t, s)
+	if store.tournament.Status != TournamentOngoing {
+		t.Fatalf("before end date: got %s, want %s", store.tournament.Status, TournamentOngoing)
+	}
+
+	store.matches = append(store.matches, match(matchstate.Scheduled))
+	clock.now = endDate.Add(time.Hour)
+	tick(t, s)
+	if store.tournament.Status != TournamentOngoing {
+		t.Fatalf("match left after end date: got %s, want %s", store.tournament.Status, TournamentOngoing)
+	}
+
+	store.matches[1].Status = matchstate.Completed
+	tick(t, s)
+	if store.tournament.Status != TournamentCompleted {
+		t.Fatalf("all matches played after end date: got %s, want %s", store.tournament.Status, TournamentCompleted)
+	}
+}
+
+func TestTournamentStatusGrandFinalReset(t *testing.T) {
+	now := startDate.Add(72 * time.Hour)
+	tournament := newTournament(TournamentOngoing)
+
+	grandFinal := bracketMatch(bracket.BracketGrandFinal, 1, nil, matchstate.Completed)
+	upperFinal := bracketMatch(bracket.BracketUpper, 1, &grandFinal.ID, matchstate.Completed)
+	lowerFinal := bracketMatch(bracket.BracketLower, 1, &grandFinal.ID, matchstate.Completed)
+
+	tests := []struct {
+		name  string
+		reset string
+		want  string
+	}{
+		{"reset still to play", matchstate.Scheduled, TournamentOngoing},
+		{"reset not needed", matchstate.Cancelled, TournamentCompleted},
+		{"reset played", matchstate.Completed, TournamentCompleted},
+	}
+	for _, tt := range tests {
+		reset := bracketMatch(bracket.BracketGrandFinalReset, 2, nil, tt.reset)
+		matches := []model.Match{upperFinal, lowerFinal, grandFinal, reset}
+		if got := TournamentStatus(tournament, matches, now); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTournamentStatusNeedsDecidedMatch(t *testing.T) {
+	tournament := newTournament(TournamentOngoing)
+	matches := []model.Match{match(matchstate.Cancelled), match(matchstate.Cancelled)}
+
+	if got := TournamentStatus(tournament, matches, endDate.Add(time.Hour)); got != TournamentOngoing {
+		t.Errorf("only cancelled matches: got %s, want %s", got, TournamentOngoing)
+	}
+}
@@ -0,0 +1,74 @@
+package series
+
+import (
+	"embeck/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Game statuses.
+const (
+	GameValid = "valid"
+	GameVoid  = "void"
+)
+
+// ValidBestOf lists the series lengths a match can use. A best of 2 can end in a draw and is
+// only allowed where a match may be drawn.
+var ValidBestOf = map[int]bool{1: true, 2: true, 3: true, 5: true, 7: true}
+
+// WinsNeeded returns how many game wins decide a best-of-N series.
+func WinsNeeded(bestOf int) int {
+	return bestOf/2 + 1
+}
+
+// Score counts the valid games won by each team and returns the series winner once a
+// team has reached the required number of wins. Without a best_of it returns no winner.
+func Score(match model.Match) (scoreA, scoreB int, winner *primitive.ObjectID) {
+	for _, game := range match.Games {
+		if game.Status == GameVoid {
+			continue
+		}
+		switch game.WinnerTeamID {
+		case match.TeamAID:
+			scoreA++
+		case match.TeamBID:
+			scoreB++
+		}
+	}
+
+	if match.BestOf == 0 {
+		return scoreA, scoreB, nil
+	}
+
+	needed := WinsNeeded(match.BestOf)
+	switch {
+	case scoreA >= needed:
+		teamA := match.TeamAID
+		winner = &teamA
+	case scoreB >= needed:
+		teamB := match.TeamBID
+		winner = &teamB
+	}
+	return scoreA, scoreB, winner
+}
+
+// Decided reports whether a series already has a winner from its games.
+func Decided(match model.Match) bool {
+	_, _, winner := Score(match)
+	return winner != nil
+}
+
+// Drawn reports whether an even best-of series has played all its games without a winner,
+// e.g. a best of 2 ending 1-1.
+func Drawn(match model.Match) bool {
+	if match.BestOf == 0 || match.BestOf%2 != 0 {
+		return false
+	}
+	scoreA, scoreB, _ := Score(match)
+	return scoreA == scoreB && scoreA+scoreB == match.BestOf
+}
+
+// Finished reports whether the games of a series are over, with a winner or drawn.
+func Finished(match model.Match) bool {
+	return Decided(match) || Drawn(match)
+}
@@ -0,0 +1,76 @@
+package series
+
+import (
+	"embeck/model"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWinsNeeded(t *testing.T) {
+	tests := []struct {
+		bestOf int
+		want   int
+	}{
+		{1, 1},
+		{2, 2},
+		{3, 2},
+		{5, 3},
+		{7, 4},
+	}
+	for _, tt := range tests {
+		if got := WinsNeeded(tt.bestOf); got != tt.want {
+			t.Errorf("WinsNeeded(%d) = %d, want %d", tt.bestOf, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	series := func(bestOf int, games ...model.MatchGame) model.Match {
+		return model.Match{TeamAID: a, TeamBID: b, BestOf: bestOf, Games: games}
+	}
+	won := func(team primitive.ObjectID) model.MatchGame {
+		return model.MatchGame{WinnerTeamID: team, Status: GameValid}
+	}
+	void := func(team primitive.ObjectID) model.MatchGame {
+		return model.MatchGame{WinnerTeamID: team, Status: GameVoid}
+	}
+
+	tests := []struct {
+		name           string
+		match          model.Match
+		scoreA, scoreB int
+		winner         *primitive.ObjectID
+		drawn          bool
+	}{
+		{"no games", series(3), 0, 0, nil, false},
+		{"under way", series(3, won(a), won(b)), 1, 1, nil, false},
+		{"team A takes best of 3", series(3, won(a), won(b), won(a)), 2, 1, &a, false},
+		{"team B sweeps best of 5", series(5, won(b), won(b), won(b)), 0, 3, &b, false},
+		{"void games do not count", series(3, won(a), void(a), won(b)), 1, 1, nil, false},
+		{"void game replayed", series(1, void(a), won(b)), 0, 1, &b, false},
+		{"best of 2 drawn", series(2, won(a), won(b)), 1, 1, nil, true},
+		{"best of 2 won", series(2, won(b), won(b)), 0, 2, &b, false},
+		{"best of 2 under way", series(2, won(a)), 1, 0, nil, false},
+		{"without best of there is no winner", series(0, won(a), won(a)), 2, 0, nil, false},
+	}
+	for _, tt := range tests {
+		scoreA, scoreB, winner := Score(tt.match)
+		if scoreA != tt.scoreA || scoreB != tt.scoreB {
+			t.Errorf("%s: got %d-%d, want %d-%d", tt.name, scoreA, scoreB, tt.scoreA, tt.scoreB)
+		}
+		if (winner == nil) != (tt.winner == nil) || winner != nil && *winner != *tt.winner {
+			t.Errorf("%s: got winner %v, want %v", tt.name, winner, tt.winner)
+		}
+		if got := Decided(tt.match); got != (tt.winner != nil) {
+			t.Errorf("%s: Decided = %v", tt.name, got)
+		}
+		if got := Drawn(tt.match); got != tt.drawn {
+			t.Errorf("%s: Drawn = %v, want %v", tt.name, got, tt.drawn)
+		}
+		if got, want := Finished(tt.match), tt.winner != nil || tt.drawn; got != want {
+			t.Errorf("%s: Finished = %v, want %v", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package swiss
+
+import (
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"errors"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrNotEnoughTeams is returned when a Swiss round is requested for fewer than two teams.
+var ErrNotEnoughTeams = errors.New("minimal 2 team dibutuhkan untuk Swiss round")
+
+// Record is a team's running record across completed Swiss rounds.
+type Record struct {
+	Wins      int
+	Losses    int
+	Byes      int
+	GamesWon  int
+	GamesLost int
+	Opponents []primitive.ObjectID
+}
+
+// Rounds returns the recommended number of Swiss rounds for a field, ceil(log2(teams)).
+func Rounds(teams int) int {
+	rounds := 0
+	for n := 1; n < teams; n *= 2 {
+		rounds++
+	}
+	return rounds
+}
+
+// Winner returns the winner of a completed or forfeited match, taken from WinnerTeamID or else from the scores.
+func Winner(match model.Match) (primitive.ObjectID, bool) {
+	if !matchstate.Decided(match.Status) {
+		return primitive.NilObjectID, false
+	}
+	if match.WinnerTeamID != nil {
+		return *match.WinnerTeamID, true
+	}
+	if match.ResultTeamAScore == nil || match.ResultTeamBScore == nil {
+		return primitive.NilObjectID, false
+	}
+	switch {
+	case *match.ResultTeamAScore > *match.ResultTeamBScore:
+		return match.TeamAID, true
+	case *match.ResultTeamBScore > *match.ResultTeamAScore:
+		return match.TeamBID, true
+	}
+	return primitive.NilObjectID, false
+}
+
+// Records builds every team's record from the Swiss matches played so far.
+// A bye counts as a win without an opponent.
+func Records(teams []primitive.ObjectID, matches []model.Match) map[primitive.ObjectID]*Record {
+	records := make(map[primitive.ObjectID]*Record, len(teams))
+	for _, teamID := range teams {
+		records[teamID] = &Record{}
+	}
+
+	for _, match := range matches {
+		a, okA := records[match.TeamAID]
+		if !okA {
+			continue
+		}
+
+		if match.IsBye {
+			if match.Status == "completed" {
+				a.Wins++
+				a.Byes++
+			}
+			continue
+		}
+
+		b, okB := records[match.TeamBID]
+		if !okB {
+			continue
+		}
+		a.Opponents = append(a.Opponents, match.TeamBID)
+		b.Opponents = append(b.Opponents, match.TeamAID)
+
+		if match.ResultTeamAScore != nil && match.ResultTeamBScore != nil {
+			a.GamesWon += *match.ResultTeamAScore
+			a.GamesLost += *match.ResultTeamBScore
+			b.GamesWon += *match.ResultTeamBScore
+			b.GamesLost += *match.ResultTeamAScore
+		}
+
+		winner, ok := Winner(match)
+		if !ok {
+			continue
+		}
+		if winner == match.TeamAID {
+			a.Wins++
+			b.Losses++
+		} else {
+			b.Wins++
+			a.Losses++
+		}
+	}
+	return records
+}
+
+// Buchholz returns the sum of the wins of every opponent a team has faced.
+func Buchholz(teamID primitive.ObjectID, records map[primitive.ObjectID]*Record) int {
+	total := 0
+	for _, opponent := range records[teamID].Opponents {
+		if record, ok := records[opponent]; ok {
+			total += record.Wins
+		}
+	}
+	return total
+}
+
+// Rank orders teams by wins, then Buchholz, then game difference. Teams that are
+// still level keep their order from the given slice, so seeding breaks the final tie.
+func Rank(teams []primitive.ObjectID, records map[primitive.ObjectID]*Record) []primitive.ObjectID {
+	ranked := make([]primitive.ObjectID, len(teams))
+	copy(ranked, teams)
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := records[ranked[i]], records[ranked[j]]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		buchholzA, buchholzB := Buchholz(ranked[i], records), Buchholz(ranked[j], records)
+		if buchholzA != buchholzB {
+			return buchholzA > buchholzB
+		}
+		return a.GamesWon-a.GamesLost > b.GamesWon-b.GamesLost
+	})
+	return ranked
+}
+
+// Pair pairs teams for the next round. Teams are ranked first so that teams with equal
+// records meet; rematches are avoided whenever any rematch-free pairing exists. With an
+// odd number of teams the lowest-ranked team that has not had a bye yet sits out.
+func Pair(teams []primitive.ObjectID, records map[primitive.ObjectID]*Record) (pairs [][2]primitive.ObjectID, bye primitive.ObjectID, err error) {
+	if len(teams) < 2 {
+		return nil, primitive.NilObjectID, ErrNotEnoughTeams
+	}
+
+	ranked := Rank(teams, records)
+
+	if len(ranked)%2 == 1 {
+		byeIndex := len(ranked) - 1
+		for i := len(ranked) - 1; i >= 0; i-- {
+			if records[ranked[i]].Byes == 0 {
+				byeIndex = i
+				break
+			}
+		}
+		bye = ranked[byeIndex]
+		ranked = append(ranked[:byeIndex:byeIndex], ranked[byeIndex+1:]...)
+	}
+
+	played := func(a, b primitive.ObjectID) bool {
+		for _, opponent := range records[a].Opponents {
+			if opponent == b {
+				return true
+			}
+		}
+		return false
+	}
+
+	if pairs, ok := pairUp(ranked, played); ok {
+		return pairs, bye, nil
+	}
+
+	// Every pairing needs a rematch; fall back to pairing down the ranking
+	never := func(a, b primitive.ObjectID) bool { return false }
+	pairs, _ = pairUp(ranked, never)
+	return pairs, bye, nil
+}
+
+// pairUp pairs the first team with the closest-ranked team it may face and
+// backtracks when the remaining teams cannot all be paired.
+func pairUp(ranked []primitive.ObjectID, played func(a, b primitive.ObjectID) bool) ([][2]primitive.ObjectID, bool) {
+	if len(ranked) == 0 {
+		return nil, true
+	}
+
+	first := ranked[0]
+	for i := 1; i < len(ranked); i++ {
+		if played(first, ranked[i]) {
+			continue
+		}
+
+		rest := make([]primitive.ObjectID, 0, len(ranked)-2)
+		rest = append(rest, ranked[1:i]...)
+		rest = append(rest, ranked[i+1:]...)
+
+		if pairs, ok := pairUp(rest, played); ok {
+			return append([][2]primitive.ObjectID{{first, ranked[i]}}, pairs...), true
+		}
+	}
+	return nil, false
+}
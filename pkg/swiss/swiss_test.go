@@ -0,0 +1,257 @@
+package swiss
+
+import (
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTeams(n int) []primitive.ObjectID {
+	teams := make([]primitive.ObjectID, n)
+	for i := range teams {
+		teams[i] = primitive.NewObjectID()
+	}
+	return teams
+}
+
+func played(a, b primitive.ObjectID, status string, scoreA, scoreB int, winner *primitive.ObjectID) model.Match {
+	return model.Match{
+		TeamAID:          a,
+		TeamBID:          b,
+		Status:           status,
+		ResultTeamAScore: &scoreA,
+		ResultTeamBScore: &scoreB,
+		WinnerTeamID:     winner,
+	}
+}
+
+// indexes maps teams back to their position in the field.
+func indexes(teams []primitive.ObjectID) map[primitive.ObjectID]int {
+	index := make(map[primitive.ObjectID]int, len(teams))
+	for i, team := range teams {
+		index[team] = i
+	}
+	return index
+}
+
+func TestRounds(t *testing.T) {
+	tests := []struct {
+		teams int
+		want  int
+	}{
+		{2, 1},
+		{3, 2},
+		{4, 2},
+		{8, 3},
+		{9, 4},
+		{16, 4},
+		{32, 5},
+	}
+	for _, tt := range tests {
+		if got := Rounds(tt.teams); got != tt.want {
+			t.Errorf("Rounds(%d) = %d, want %d", tt.teams, got, tt.want)
+		}
+	}
+}
+
+func TestWinner(t *testing.T) {
+	teams := newTeams(2)
+	a, b := teams[0], teams[1]
+
+	tests := []struct {
+		name  string
+		match model.Match
+		want  primitive.ObjectID
+		ok    bool
+	}{
+		{"winner set", played(a, b, matchstate.Completed, 0, 0, &b), b, true},
+		{"from scores", played(a, b, matchstate.Completed, 2, 1, nil), a, true},
+		{"forfeit", played(a, b, matchstate.Forfeited, 0, 1, nil), b, true},
+		{"level scores", played(a, b, matchstate.Completed, 1, 1, nil), primitive.NilObjectID, false},
+		{"not decided", played(a, b, matchstate.Ongoing, 2, 0, &a), primitive.NilObjectID, false},
+		{"no scores", model.Match{TeamAID: a, TeamBID: b, Status: matchstate.Completed}, primitive.NilObjectID, false},
+	}
+	for _, tt := range tests {
+		got, ok := Winner(tt.match)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %v %v, want %v %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRecordsAndBuchholz(t *testing.T) {
+	teams := newTeams(5)
+	matches := []model.Match{
+		played(teams[0], teams[1], matchstate.Completed, 2, 1, &teams[0]),
+		played(teams[2], teams[3], matchstate.Forfeited, 1, 0, &teams[2]),
+		{TeamAID: teams[4], IsBye: true, Status: matchstate.Completed},
+		// Round 2 is under way: the pairing counts, the result does not yet
+		played(teams[0], teams[2], matchstate.Ongoing, 1, 0, nil),
+		// Teams outside the field are ignored
+		played(teams[1], primitive.NewObjectID(), matchstate.Completed, 2, 0, &teams[1]),
+	}
+	records := Records(teams, matches)
+
+	tests := []struct {
+		team      int
+		wins      int
+		losses    int
+		byes      int
+		games     [2]int
+		opponents int
+		buchholz  int
+	}{
+		{0, 1, 0, 0, [2]int{3, 1}, 2, 1},
+		{1, 0, 1, 0, [2]int{1, 2}, 1, 1},
+		{2, 1, 0, 0, [2]int{1, 1}, 2, 1},
+		{3, 0, 1, 0, [2]int{0, 1}, 1, 1},
+		{4, 1, 0, 1, [2]int{0, 0}, 0, 0},
+	}
+	for _, tt := range tests {
+		r := records[teams[tt.team]]
+		if r.Wins != tt.wins || r.Losses != tt.losses || r.Byes != tt.byes {
+			t.Errorf("team %d: got %d-%d with %d byes, want %d-%d with %d byes", tt.team, r.Wins, r.Losses, r.Byes, tt.wins, tt.losses, tt.byes)
+		}
+		if r.GamesWon != tt.games[0] || r.GamesLost != tt.games[1] {
+			t.Errorf("team %d: got games %d-%d, want %d-%d", tt.team, r.GamesWon, r.GamesLost, tt.games[0], tt.games[1])
+		}
+		if len(r.Opponents) != tt.opponents {
+			t.Errorf("team %d: got %d opponents, want %d", tt.team, len(r.Opponents), tt.opponents)
+		}
+		if got := Buchholz(teams[tt.team], records); got != tt.buchholz {
+			t.Errorf("team %d: got Buchholz %d, want %d", tt.team, got, tt.buchholz)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	teams := newTeams(6)
+	records := map[primitive.ObjectID]*Record{
+		teams[0]: {Wins: 1, Opponents: []primitive.ObjectID{teams[3]}},
+		teams[1]: {Wins: 2},
+		teams[2]: {Wins: 1, Opponents: []primitive.ObjectID{teams[1]}},
+		teams[3]: {},
+		teams[4]: {Wins: 1, GamesWon: 2, Opponents: []primitive.ObjectID{teams[3]}},
+		teams[5]: {Wins: 1, Opponents: []primitive.ObjectID{teams[3]}},
+	}
+
+	// Wins first, then Buchholz (team 2 faced the leader), then game difference (team 4),
+	// then the seeding keeps team 0 ahead of team 5
+	want := []int{1, 2, 4, 0, 5, 3}
+	index := indexes(teams)
+	for i, team := range Rank(teams, records) {
+		if index[team] != want[i] {
+			t.Errorf("rank %d: got team %d, want team %d", i+1, index[team], want[i])
+		}
+	}
+}
+
+func TestPair(t *testing.T) {
+	tests := []struct {
+		name    string
+		teams   int
+		records func(teams []primitive.ObjectID) map[primitive.ObjectID]*Record
+		pairs   [][2]int
+		bye     int
+	}{
+		{
+			name:  "first round pairs down the seeding",
+			teams: 4,
+			pairs: [][2]int{{0, 1}, {2, 3}},
+			bye:   -1,
+		},
+		{
+			name:  "teams with equal records meet",
+			teams: 4,
+			records: func(teams []primitive.ObjectID) map[primitive.ObjectID]*Record {
+				return map[primitive.ObjectID]*Record{
+					teams[0]: {Wins: 1, Opponents: []primitive.ObjectID{teams[1]}},
+					teams[1]: {Losses: 1, Opponents: []primitive.ObjectID{teams[0]}},
+					teams[2]: {Wins: 1, Opponents: []primitive.ObjectID{teams[3]}},
+					teams[3]: {Losses: 1, Opponents: []primitive.ObjectID{teams[2]}},
+				}
+			},
+			pairs: [][2]int{{0, 2}, {1, 3}},
+			bye:   -1,
+		},
+		{
+			name:  "rematches are avoided",
+			teams: 4,
+			records: func(teams []primitive.ObjectID) map[primitive.ObjectID]*Record {
+				return map[primitive.ObjectID]*Record{
+					teams[0]: {Wins: 2, Opponents: []primitive.ObjectID{teams[1], teams[2]}},
+					teams[1]: {Wins: 1, Losses: 1, Opponents: []primitive.ObjectID{teams[0], teams[3]}},
+					teams[2]: {Wins: 1, Losses: 1, Opponents: []primitive.ObjectID{teams[3], teams[0]}},
+					teams[3]: {Losses: 2, Opponents: []primitive.ObjectID{teams[2], teams[1]}},
+				}
+			},
+			pairs: [][2]int{{0, 3}, {1, 2}},
+			bye:   -1,
+		},
+		{
+			name:  "rematch when every pairing is one",
+			teams: 2,
+			records: func(teams []primitive.ObjectID) map[primitive.ObjectID]*Record {
+				return map[primitive.ObjectID]*Record{
+					teams[0]: {Wins: 1, Opponents: []primitive.ObjectID{teams[1]}},
+					teams[1]: {Losses: 1, Opponents: []primitive.ObjectID{teams[0]}},
+				}
+			},
+			pairs: [][2]int{{0, 1}},
+			bye:   -1,
+		},
+		{
+			name:  "bye for the lowest-ranked team",
+			teams: 3,
+			pairs: [][2]int{{0, 1}},
+			bye:   2,
+		},
+		{
+			name:  "bye skips a team that had one",
+			teams: 3,
+			records: func(teams []primitive.ObjectID) map[primitive.ObjectID]*Record {
+				return map[primitive.ObjectID]*Record{
+					teams[0]: {Wins: 2},
+					teams[1]: {Wins: 1, GamesWon: 2},
+					teams[2]: {Wins: 1, Byes: 1},
+				}
+			},
+			pairs: [][2]int{{0, 2}},
+			bye:   1,
+		},
+	}
+	for _, tt := range tests {
+		teams := newTeams(tt.teams)
+		records := Records(teams, nil)
+		if tt.records != nil {
+			records = tt.records(teams)
+		}
+
+		pairs, bye, err := Pair(teams, records)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		index := indexes(teams)
+		if tt.bye < 0 && !bye.IsZero() || tt.bye >= 0 && bye != teams[tt.bye] {
+			t.Errorf("%s: got bye for team %d, want %d", tt.name, index[bye], tt.bye)
+		}
+		if len(pairs) != len(tt.pairs) {
+			t.Errorf("%s: got %d pairs, want %d", tt.name, len(pairs), len(tt.pairs))
+			continue
+		}
+		for i, pair := range pairs {
+			if pair[0] != teams[tt.pairs[i][0]] || pair[1] != teams[tt.pairs[i][1]] {
+				t.Errorf("%s: pair %d is %d vs %d, want %v", tt.name, i+1, index[pair[0]], index[pair[1]], tt.pairs[i])
+			}
+		}
+	}
+}
+
+func TestPairNotEnoughTeams(t *testing.T) {
+	teams := newTeams(1)
+	if _, _, err := Pair(teams, Records(teams, nil)); err != ErrNotEnoughTeams {
+		t.Fatalf("got %v, want %v", err, ErrNotEnoughTeams)
+	}
+}
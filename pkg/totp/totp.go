@@ -0,0 +1,115 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RFC 6238 parameters understood by every authenticator app
+const (
+	Period = 30
+	Digits = 6
+)
+
+// Skew is how many periods before and after the current one are still accepted, to allow for
+// clock drift and codes typed just as they roll over
+const Skew = 1
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random 160-bit secret, base32 encoded
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI returns the otpauth:// URI that authenticator apps read from a QR code
+func ProvisioningURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(Period))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Step returns the time step a moment falls into
+func Step(now time.Time) int64 {
+	return now.Unix() / Period
+}
+
+// Code returns the code of a secret at a time step
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret")
+	}
+
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1000000), nil
+}
+
+// Validate checks a code against the steps around now and returns the step it matched.
+// Callers store the step and refuse codes of the same or earlier steps so a code works only once
+func Validate(secret, code string, now time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(now)
+	for step := current - Skew; step <= current+Skew; step++ {
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes returns n single-use recovery codes formatted as xxxxx-xxxxx
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		encoded := hex.EncodeToString(raw)
+		codes[i] = encoded[:5] + "-" + encoded[5:]
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode lowercases a recovery code and restores its dash, so codes typed with
+// other casing or without the dash still match
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) != 10 {
+		return code
+	}
+	return code[:5] + "-" + code[5:]
+}
@@ -0,0 +1,133 @@
+package totp
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors, "12345678901234567890", base32 encoded
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Errorf("invalid secret: want an error")
+	}
+	if _, err := Code(strings.ToLower(rfcSecret), 1); err != nil {
+		t.Errorf("lowercase secret: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+	codeAt := func(step int64) string {
+		code, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name string
+		code string
+		step int64
+		ok   bool
+	}{
+		{"current step", codeAt(current), current, true},
+		{"previous step", codeAt(current - 1), current - 1, true},
+		{"next step", codeAt(current + 1), current + 1, true},
+		{"two steps old", codeAt(current - 2), 0, false},
+		{"two steps ahead", codeAt(current + 2), 0, false},
+		{"typed with spaces", " " + codeAt(current)[:3] + " " + codeAt(current)[3:] + " ", current, true},
+		{"too short", codeAt(current)[:5], 0, false},
+		{"too long", codeAt(current) + "0", 0, false},
+		{"wrong code", "000000", 0, false},
+	}
+	for _, tt := range tests {
+		step, ok := Validate(rfcSecret, tt.code, now)
+		if ok != tt.ok || step != tt.step {
+			t.Errorf("%s: got step %d ok %v, want step %d ok %v", tt.name, step, ok, tt.step, tt.ok)
+		}
+	}
+
+	if _, ok := Validate("not base32!", codeAt(current), now); ok {
+		t.Errorf("invalid secret: code accepted")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 160 bits are 32 base32 characters without padding
+	if len(secret) != 32 {
+		t.Errorf("got %d characters, want 32", len(secret))
+	}
+	code, err := Code(secret, Step(time.Now()))
+	if err != nil {
+		t.Fatalf("generated secret is not usable: %v", err)
+	}
+	if _, ok := Validate(secret, code, time.Now()); !ok {
+		t.Errorf("code of a generated secret is not accepted")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	format := regexp.MustCompile(`^[0-9a-f]{5}-[0-9a-f]{5}$`)
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q is not formatted as xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("code %q generated twice", code)
+		}
+		seen[code] = true
+	}
+
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"ab12c-3de45", "ab12c-3de45"},
+		{"AB12C-3DE45", "ab12c-3de45"},
+		{"ab12c3de45", "ab12c-3de45"},
+		{"  ab12c-3de45 ", "ab12c-3de45"},
+		{"ab12", "ab12"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeRecoveryCode(tt.code); got != tt.want {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package transfers
+
+import (
+	"embeck/model"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Build derives transfers from membership periods. A player's period on a team that
+// follows a period ending on another team is a transfer between them; a period with
+// no earlier one is a join, and a period that ends without a later one is a release.
+// Role changes within a team, where one period ends as the next starts, are skipped;
+// leaving a team and rejoining it later is a release and a join, not a transfer.
+// The result is sorted newest first.
+func Build(memberships []model.TeamMembership) []model.Transfer {
+	byPlayer := make(map[primitive.ObjectID][]model.TeamMembership)
+	for _, membership := range memberships {
+		byPlayer[membership.PlayerID] = append(byPlayer[membership.PlayerID], membership)
+	}
+
+	var result []model.Transfer
+	for _, periods := range byPlayer {
+		result = append(result, playerTransfers(periods)...)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date.After(result[j].Date)
+	})
+	return result
+}
+
+// playerTransfers derives the transfers of a single player from their periods
+func playerTransfers(periods []model.TeamMembership) []model.Transfer {
+	sort.SliceStable(periods, func(i, j int) bool {
+		return periods[i].JoinedAt.Before(periods[j].JoinedAt)
+	})
+
+	var result []model.Transfer
+	var previous *model.TeamMembership
+	for i := range periods {
+		period := periods[i]
+
+		switch {
+		case previous == nil:
+			result = append(result, transfer(model.TransferJoin, nil, &period))
+		case previous.TeamID == period.TeamID && previous.LeftAt != nil && previous.LeftAt.Equal(period.JoinedAt):
+			// Role change within the same team
+		case previous.LeftAt == nil || previous.LeftAt.After(period.JoinedAt):
+			// Overlapping periods only happen with inconsistent data; treat it as a join
+			result = append(result, transfer(model.TransferJoin, nil, &period))
+		case previous.TeamID == period.TeamID:
+			// Leaving a team and coming back later is a release followed by a new join
+			result = append(result, transfer(model.TransferRelease, previous, nil), transfer(model.TransferJoin, nil, &period))
+		default:
+			result = append(result, transfer(model.TransferMove, previous, &period))
+		}
+
+		// A period followed by a later one is closed by that transition, not a release
+		if i == len(periods)-1 && period.LeftAt != nil {
+			result = append(result, transfer(model.TransferRelease, &period, nil))
+		}
+		previous = &periods[i]
+	}
+	return result
+}
+
+// transfer builds a transfer between two periods; either may be nil
+func transfer(kind string, from, to *model.TeamMembership) model.Transfer {
+	t := model.Transfer{Kind: kind}
+	if from != nil {
+		t.PlayerID = from.PlayerID.Hex()
+		t.Player = from.Player
+		t.FromTeam = teamInfo(*from)
+		t.Role = from.Role
+		t.Date = *from.LeftAt
+	}
+	if to != nil {
+		t.PlayerID = to.PlayerID.Hex()
+		t.Player = to.Player
+		t.ToTeam = teamInfo(*to)
+		t.Role = to.Role
+		t.Date = to.JoinedAt
+	}
+	return t
+}
+
+// teamInfo returns the looked-up team of a period, or just its ID when the team is gone
+func teamInfo(period model.TeamMembership) *model.TeamBasicInfo {
+	if period.Team != nil && !period.Team.ID.IsZero() {
+		return period.Team
+	}
+	return &model.TeamBasicInfo{ID: period.TeamID}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InsertAuditLog stores an audit log entry
+func InsertAuditLog(ctx context.Context, log model.AuditLog) error {
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+	if _, err := config.AuditLogsCollection.InsertOne(ctx, log); err != nil {
+		fmt.Printf("InsertAuditLog: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// auditCollection returns the collection holding an audited entity type
+func auditCollection(entityType string) *mongo.Collection {
+	switch entityType {
+	case "registration":
+		return config.RegistrationsCollection
+	case "player_link":
+		return config.PlayerLinksCollection
+	case "role_assignment":
+		return config.RoleAssignmentsCollection
+	case "ticket":
+		return config.UserTicketsCollection
+	}
+	collection, _, _, err := trashCollection(entityType)
+	if err != nil {
+		return nil
+	}
+	return collection
+}
+
+// GetAuditSnapshot retrieves the raw document of an audited entity, trashed or not. Unknown
+// entity types and missing documents give a nil snapshot
+func GetAuditSnapshot(ctx context.Context, entityType string, id string) (bson.M, error) {
+	collection := auditCollection(entityType)
+	objID, err := primitive.ObjectIDFromHex(id)
+	if collection == nil || err != nil {
+		return nil, nil
+	}
+
+	var snapshot bson.M
+	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&snapshot)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		fmt.Printf("GetAuditSnapshot: %v\n", err)
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// GetAuditLogs retrieves a page of audit logs matching the filter, newest first
+func GetAuditLogs(ctx context.Context, filter model.AuditLogFilter) (*model.AuditLogList, error) {
+	match := bson.M{}
+	if filter.ActorID != nil {
+		match["actor_id"] = *filter.ActorID
+	}
+	if filter.EntityType != "" {
+		match["entity_type"] = filter.EntityType
+	}
+	if filter.EntityID != "" {
+		match["entity_id"] = filter.EntityID
+	}
+	if filter.Action != "" {
+		match["action"] = filter.Action
+	}
+	if filter.From != nil || filter.To != nil {
+		createdAt := bson.M{}
+		if filter.From != nil {
+			createdAt["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			createdAt["$lte"] = *filter.To
+		}
+		match["created_at"] = createdAt
+	}
+
+	total, err := config.AuditLogsCollection.CountDocuments(ctx, match)
+	if err != nil {
+		fmt.Println("GetAuditLogs (Count):", err)
+		return nil, err
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.M{"created_at": -1}},
+		{"$skip": (filter.Page - 1) * filter.Limit},
+		{"$limit": filter.Limit},
+	}
+	cursor, err := config.AuditLogsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Println("GetAuditLogs (Aggregate):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	logs := []model.AuditLog{}
+	if err := cursor.All(ctx, &logs); err != nil {
+		fmt.Println("GetAuditLogs (Decode):", err)
+		return nil, err
+	}
+
+	return &model.AuditLogList{
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Logs:  logs,
+	}, nil
+}
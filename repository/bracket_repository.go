@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/bracket"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateBracket inserts all generated bracket matches for a tournament. The check and the
+// insert run in one transaction that also writes the tournament, so of two concurrent
+// generations one conflicts, retries and then finds the bracket already there
+func CreateBracket(ctx context.Context, tournamentID primitive.ObjectID, matches []model.Match) (insertedIDs []primitive.ObjectID, err error) {
+	docs := make([]interface{}, len(matches))
+	for i, match := range matches {
+		docs[i] = match
+	}
+
+	err = withTransaction(ctx, func(sc context.Context) error {
+		insertedIDs = nil
+
+		// Refuse to generate a second bracket for the same tournament
+		filter := bson.M{"tournament_id": tournamentID, "bracket_round": bson.M{"$exists": true}}
+		count, err := config.MatchesCollection.CountDocuments(sc, filter)
+		if err != nil {
+			fmt.Printf("CreateBracket - Count Bracket Matches: %v\n", err)
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("Bracket untuk tournament %s sudah dibuat", tournamentID.Hex())
+		}
+
+		_, err = config.TournamentsCollection.UpdateOne(sc,
+			bson.M{"_id": tournamentID},
+			bson.M{"$set": bson.M{"updated_at": time.Now()}},
+		)
+		if err != nil {
+			fmt.Printf("CreateBracket - Lock Tournament: %v\n", err)
+			return err
+		}
+
+		result, err := config.MatchesCollection.InsertMany(sc, docs)
+		if err != nil {
+			fmt.Printf("CreateBracket - Insert: %v\n", err)
+			return err
+		}
+		for _, id := range result.InsertedIDs {
+			insertedIDs = append(insertedIDs, id.(primitive.ObjectID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return insertedIDs, nil
+}
+
+// AdvanceWinner places the winner (and, in double elimination, the loser) of a bracket
+// match into the matches they advance to
+func AdvanceWinner(ctx context.Context, matchID primitive.ObjectID) error {
+	var match model.Match
+	err := config.MatchesCollection.FindOne(ctx, bson.M{"_id": matchID}).Decode(&match)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	if match.WinnerTeamID == nil {
+		return nil
+	}
+	winner := *match.WinnerTeamID
+
+	if match.Bracket == bracket.BracketGrandFinal {
+		return resolveGrandFinalReset(ctx, match)
+	}
+
+	if match.NextMatchID != nil {
+		if err := placeTeamInMatch(ctx, match, *match.NextMatchID, match.NextMatchSlot, winner); err != nil {
+			return err
+		}
+	}
+
+	if match.LoserNextMatchID != nil && !match.IsBye {
+		loser := match.TeamAID
+		if winner == match.TeamAID {
+			loser = match.TeamBID
+		}
+		if err := placeTeamInMatch(ctx, match, *match.LoserNextMatchID, match.LoserNextMatchSlot, loser); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errNextMatchStarted is returned when a corrected result would change a team in a bracket
+// match that is already under way
+var errNextMatchStarted = fmt.Errorf("Match berikutnya di bracket sudah dimulai, pemenang match ini tidak dapat diubah")
+
+// checkNextMatchesOpen refuses a winner correction once a bracket match the result feeds
+// into has started, so a team is never swapped out of a match it is already playing
+func checkNextMatchesOpen(ctx context.Context, match model.Match) error {
+	var filter bson.M
+	switch {
+	case match.Bracket == bracket.BracketGrandFinal:
+		filter = bson.M{"tournament_id": match.TournamentID, "bracket": bracket.BracketGrandFinalReset}
+	case match.NextMatchID != nil || match.LoserNextMatchID != nil:
+		var ids []primitive.ObjectID
+		for _, id := range []*primitive.ObjectID{match.NextMatchID, match.LoserNextMatchID} {
+			if id != nil {
+				ids = append(ids, *id)
+			}
+		}
+		filter = bson.M{"_id": bson.M{"$in": ids}}
+	default:
+		return nil
+	}
+
+	filter["status"] = bson.M{"$nin": []string{"scheduled", "cancelled"}}
+	count, err := config.MatchesCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		fmt.Printf("checkNextMatchesOpen: %v\n", err)
+		return err
+	}
+	if count > 0 {
+		return errNextMatchStarted
+	}
+	return nil
+}
+
+// placeTeamInMatch sets a team into a slot of a bracket match. The slot is only written while
+// the match is still scheduled and the slot is empty or holds one of the teams of the match
+// feeding it, so a corrected result never replaces a team in a match that has started. If
+// that match is a bye waiting for its only team, it is completed straight away and the team
+// moves on.
+func placeTeamInMatch(ctx context.Context, from model.Match, matchID primitive.ObjectID, slot string, teamID primitive.ObjectID) error {
+	slotField := "team_a_id"
+	if slot == bracket.SlotTeamB {
+		slotField = "team_b_id"
+	}
+
+	filter := bson.M{
+		"_id":     matchID,
+		"status":  "scheduled",
+		slotField: bson.M{"$in": []interface{}{nil, primitive.NilObjectID, from.TeamAID, from.TeamBID}},
+	}
+	var next model.Match
+	err := config.MatchesCollection.FindOneAndUpdate(ctx,
+		filter,
+		bumpVersion(bson.M{"$set": bson.M{slotField: teamID, "updated_at": time.Now()}}),
+	).Decode(&next)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return missingOrStarted(ctx, matchID)
+		}
+		return err
+	}
+
+	// next holds the match as it was before the update
+	if slot == bracket.SlotTeamB {
+		next.TeamBID = teamID
+	} else {
+		next.TeamAID = teamID
+	}
+	if !next.IsBye {
+		return forfeitPendingDisqualification(ctx, next)
+	}
+	if next.Status != "scheduled" {
+		return nil
+	}
+
+	_, err = config.MatchesCollection.UpdateOne(ctx,
+		bson.M{"_id": matchID},
+		bumpVersion(bson.M{"$set": bson.M{"status": "completed", "winner_team_id": teamID, "updated_at": time.Now()}}),
+	)
+	if err != nil {
+		return err
+	}
+	return AdvanceWinner(ctx, matchID)
+}
+
+// resolveGrandFinalReset schedules the bracket reset when the lower-bracket champion
+// (team B) wins the grand final, and cancels it when the upper-bracket champion wins
+func resolveGrandFinalReset(ctx context.Context, grandFinal model.Match) error {
+	filter := bson.M{
+		"tournament_id": grandFinal.TournamentID,
+		"bracket":       bracket.BracketGrandFinalReset,
+		"status":        bson.M{"$in": []string{"scheduled", "cancelled"}},
+	}
+
+	update := bson.M{"status": "cancelled", "updated_at": time.Now()}
+	if *grandFinal.WinnerTeamID != grandFinal.TeamAID {
+		update = bson.M{
+			"team_a_id":  grandFinal.TeamAID,
+			"team_b_id":  grandFinal.TeamBID,
+			"status":     "scheduled",
+			"updated_at": time.Now(),
+		}
+	}
+
+	var reset model.Match
+	err := config.MatchesCollection.FindOneAndUpdate(ctx, filter, bumpVersion(bson.M{"$set": update}),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&reset)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			count, err := config.MatchesCollection.CountDocuments(ctx, bson.M{"tournament_id": grandFinal.TournamentID, "bracket": bracket.BracketGrandFinalReset})
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return errNextMatchStarted
+			}
+			return nil
+		}
+		return err
+	}
+	return forfeitPendingDisqualification(ctx, reset)
+}
+
+// missingOrStarted tells apart a next match that no longer exists, which is skipped, from one
+// whose slot could not be written because it has started
+func missingOrStarted(ctx context.Context, matchID primitive.ObjectID) error {
+	count, err := config.MatchesCollection.CountDocuments(ctx, bson.M{"_id": matchID})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errNextMatchStarted
+	}
+	return nil
+}
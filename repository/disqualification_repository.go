@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DisqualifyTeam records a team's disqualification from a tournament and forfeits every
+// remaining match of the team there. Bracket matches still waiting for the opponent are
+// forfeited once the opponent is placed.
+func DisqualifyTeam(ctx context.Context, tournamentID primitive.ObjectID, dq model.TeamDisqualification) (forfeited []primitive.ObjectID, err error) {
+	filter := bson.M{
+		"_id":                       tournamentID,
+		"teams_participating":       dq.TeamID,
+		"disqualifications.team_id": bson.M{"$ne": dq.TeamID},
+	}
+	result, err := config.TournamentsCollection.UpdateOne(ctx, filter, bumpVersion(bson.M{
+		"$push": bson.M{"disqualifications": dq},
+	}))
+	if err != nil {
+		fmt.Printf("DisqualifyTeam - Update Tournament: %v\n", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		var tournament model.Tournament
+		err := config.TournamentsCollection.FindOne(ctx, bson.M{"_id": tournamentID}).Decode(&tournament)
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("Tournament dengan ID %s tidak ditemukan", tournamentID.Hex())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isDisqualified(tournament, dq.TeamID) {
+			return nil, fmt.Errorf("Team %s sudah didiskualifikasi dari tournament ini", dq.TeamID.Hex())
+		}
+		return nil, fmt.Errorf("Team %s tidak terdaftar di tournament ini", dq.TeamID.Hex())
+	}
+
+	matchFilter := notDeleted(bson.M{
+		"tournament_id": tournamentID,
+		"$or":           []bson.M{{"team_a_id": dq.TeamID}, {"team_b_id": dq.TeamID}},
+		"status": bson.M{"$in": []string{
+			matchstate.Scheduled, matchstate.CheckedIn, matchstate.Ongoing, matchstate.Postponed,
+		}},
+	})
+	cursor, err := config.MatchesCollection.Find(ctx, matchFilter)
+	if err != nil {
+		fmt.Printf("DisqualifyTeam - Find Matches: %v\n", err)
+		return nil, err
+	}
+	var matches []model.Match
+	if err := cursor.All(ctx, &matches); err != nil {
+		fmt.Printf("DisqualifyTeam - Decode Matches: %v\n", err)
+		return nil, err
+	}
+
+	for _, match := range matches {
+		if match.IsBye || match.TeamAID.IsZero() || match.TeamBID.IsZero() {
+			continue
+		}
+		if err := forfeitDisqualified(ctx, match, dq); err != nil {
+			return forfeited, err
+		}
+		forfeited = append(forfeited, match.ID)
+	}
+	return forfeited, nil
+}
+
+// forfeitPendingDisqualification forfeits a match that just got both of its teams when one
+// of them has been disqualified from the tournament
+func forfeitPendingDisqualification(ctx context.Context, match model.Match) error {
+	if match.IsBye || match.TeamAID.IsZero() || match.TeamBID.IsZero() || matchstate.Final(match.Status) {
+		return nil
+	}
+
+	var tournament model.Tournament
+	filter := bson.M{
+		"_id":                       match.TournamentID,
+		"disqualifications.team_id": bson.M{"$in": []primitive.ObjectID{match.TeamAID, match.TeamBID}},
+	}
+	err := config.TournamentsCollection.FindOne(ctx, filter).Decode(&tournament)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	for _, dq := range tournament.Disqualifications {
+		if dq.TeamID == match.TeamAID || dq.TeamID == match.TeamBID {
+			return forfeitDisqualified(ctx, match, dq)
+		}
+	}
+	return nil
+}
+
+// forfeitDisqualified forfeits a match against the disqualified team
+func forfeitDisqualified(ctx context.Context, match model.Match, dq model.TeamDisqualification) error {
+	_, err := TransitionMatch(ctx, match.ID.Hex(), model.MatchTransition{
+		To:               matchstate.Forfeited,
+		Reason:           dq.Reason,
+		ChangedBy:        dq.DisqualifiedBy,
+		Outcome:          matchstate.OutcomeDisqualification,
+		ForfeitingTeamID: &dq.TeamID,
+	})
+	if err != nil {
+		fmt.Printf("forfeitDisqualified - Match %s: %v\n", match.ID.Hex(), err)
+	}
+	return err
+}
+
+// isDisqualified reports whether a team has been disqualified from the tournament
+func isDisqualified(tournament model.Tournament, teamID primitive.ObjectID) bool {
+	for _, dq := range tournament.Disqualifications {
+		if dq.TeamID == teamID {
+			return true
+		}
+	}
+	return false
+}
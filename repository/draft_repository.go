@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/draft"
+	"embeck/pkg/series"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SetGameDraft validates and stores the hero draft of one game in a match
+func SetGameDraft(ctx context.Context, matchID, gameID string, gameDraft model.GameDraft) error {
+	match, err := findMatchForGames(ctx, matchID)
+	if err != nil {
+		return err
+	}
+
+	game, err := findMatchGame(*match, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status == series.GameVoid {
+		return fmt.Errorf("Game %s sudah di-void dan tidak dapat diubah", gameID)
+	}
+
+	if err := draft.Validate(gameDraft, match.TeamAID, match.TeamBID); err != nil {
+		return err
+	}
+
+	// Every player who picked a hero must play for the team that picked it
+	members, err := getTeamMembers(ctx, match.TournamentID, []primitive.ObjectID{match.TeamAID, match.TeamBID})
+	if err != nil {
+		return err
+	}
+	for _, pick := range gameDraft.Picks {
+		if pick.PlayerID == nil {
+			continue
+		}
+		if !members[pick.TeamID][*pick.PlayerID] {
+			return fmt.Errorf("Player dengan ID %s bukan anggota team %s", pick.PlayerID.Hex(), pick.TeamID.Hex())
+		}
+	}
+
+	_, err = config.MatchesCollection.UpdateOne(ctx,
+		bson.M{"_id": match.ID, "games._id": game.ID},
+		bumpVersion(bson.M{"$set": bson.M{
+			"games.$.draft":      gameDraft,
+			"games.$.updated_at": time.Now(),
+		}}),
+	)
+	if err != nil {
+		fmt.Printf("SetGameDraft: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// GetTournamentDraftMatches retrieves the matches of a tournament that have at least one recorded draft
+func GetTournamentDraftMatches(ctx context.Context, tournamentID primitive.ObjectID) ([]model.Match, error) {
+	filter := notDeleted(bson.M{"tournament_id": tournamentID, "games.draft": bson.M{"$exists": true}})
+	cursor, err := config.MatchesCollection.Find(ctx, filter)
+	if err != nil {
+		fmt.Println("GetTournamentDraftMatches (Find):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var matches []model.Match
+	if err := cursor.All(ctx, &matches); err != nil {
+		fmt.Println("GetTournamentDraftMatches (Decode):", err)
+		return nil, err
+	}
+	return matches, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateGroupStage stores the group configuration on the tournament and inserts all group matches
+func CreateGroupStage(ctx context.Context, tournamentID primitive.ObjectID, stage model.GroupStage, matches []model.Match) (insertedCount int, err error) {
+	// Refuse to generate a second group stage for the same tournament
+	filter := bson.M{"tournament_id": tournamentID, "group": bson.M{"$exists": true}}
+	count, err := config.MatchesCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		fmt.Printf("CreateGroupStage - Count Group Matches: %v\n", err)
+		return 0, err
+	}
+	if count > 0 {
+		return 0, fmt.Errorf("Group stage untuk tournament %s sudah dibuat", tournamentID.Hex())
+	}
+
+	_, err = config.TournamentsCollection.UpdateOne(ctx,
+		bson.M{"_id": tournamentID},
+		bumpVersion(bson.M{"$set": bson.M{"group_stage": stage, "updated_at": time.Now()}}),
+	)
+	if err != nil {
+		fmt.Printf("CreateGroupStage - Update Tournament: %v\n", err)
+		return 0, err
+	}
+
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	docs := make([]interface{}, len(matches))
+	for i, match := range matches {
+		docs[i] = match
+	}
+
+	result, err := config.MatchesCollection.InsertMany(ctx, docs)
+	if err != nil {
+		fmt.Printf("CreateGroupStage - Insert: %v\n", err)
+		return 0, err
+	}
+	return len(result.InsertedIDs), nil
+}
+
+// GetGroupMatches retrieves all group stage matches of a tournament
+func GetGroupMatches(ctx context.Context, tournamentID primitive.ObjectID) ([]model.Match, error) {
+	filter := notDeleted(bson.M{"tournament_id": tournamentID, "group": bson.M{"$exists": true}})
+	cursor, err := config.MatchesCollection.Find(ctx, filter)
+	if err != nil {
+		fmt.Println("GetGroupMatches (Find):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var matches []model.Match
+	if err := cursor.All(ctx, &matches); err != nil {
+		fmt.Println("GetGroupMatches (Decode):", err)
+		return nil, err
+	}
+	return matches, nil
+}
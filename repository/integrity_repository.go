@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DeleteConflictError is returned when a delete would leave documents referencing the removed record
+type DeleteConflictError struct {
+	Entity   string
+	Blockers []model.DeleteBlocker
+}
+
+func (e *DeleteConflictError) Error() string {
+	parts := make([]string, len(e.Blockers))
+	for i, blocker := range e.Blockers {
+		parts[i] = fmt.Sprintf("%s.%s (%d)", blocker.Collection, blocker.Field, blocker.Count)
+	}
+	return fmt.Sprintf("%s masih direferensikan oleh %s", e.Entity, strings.Join(parts, ", "))
+}
+
+// reference describes documents pointing at a record about to be deleted. A cascading delete
+// runs its cleanup; references without a cleanup always block the delete
+type reference struct {
+	collection *mongo.Collection
+	field      string
+	filter     bson.M
+	cascade    func(ctx context.Context) error
+}
+
+// purgeWithReferences permanently deletes a trashed document in a transaction, refusing with a
+// DeleteConflictError while references remain or, with cascade, cleaning them up first
+func purgeWithReferences(ctx context.Context, entity string, collection *mongo.Collection, id primitive.ObjectID, refs []reference, cascade bool) error {
+	return withTransaction(ctx, func(sc context.Context) error {
+		count, err := collection.CountDocuments(sc, inTrash(bson.M{"_id": id}))
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("%s dengan ID %s tidak ditemukan di trash", entity, id.Hex())
+		}
+
+		var blockers []model.DeleteBlocker
+		for _, ref := range refs {
+			if cascade && ref.cascade != nil {
+				continue
+			}
+			count, err := ref.collection.CountDocuments(sc, ref.filter)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				blockers = append(blockers, model.DeleteBlocker{
+					Collection: ref.collection.Name(),
+					Field:      ref.field,
+					Count:      count,
+				})
+			}
+		}
+		if len(blockers) > 0 {
+			return &DeleteConflictError{Entity: entity, Blockers: blockers}
+		}
+
+		if cascade {
+			for _, ref := range refs {
+				if ref.cascade == nil {
+					continue
+				}
+				if err := ref.cascade(sc); err != nil {
+					return fmt.Errorf("gagal membersihkan %s.%s: %v", ref.collection.Name(), ref.field, err)
+				}
+			}
+		}
+
+		result, err := collection.DeleteOne(sc, bson.M{"_id": id})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return fmt.Errorf("tidak ada data yang dihapus untuk %s ID %s", entity, id.Hex())
+		}
+		return nil
+	})
+}
+
+// withTransaction runs fn inside a MongoDB transaction
+func withTransaction(ctx context.Context, fn func(sc context.Context) error) error {
+	session, err := config.DB.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// archiveDocuments moves the matching documents into the archive collection
+func archiveDocuments(ctx context.Context, collection *mongo.Collection, filter bson.M, reason string) error {
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	archived := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		archived[i] = model.ArchivedDocument{
+			Collection: collection.Name(),
+			DocumentID: doc.Lookup("_id"),
+			Document:   doc,
+			Reason:     reason,
+			ArchivedAt: now,
+		}
+	}
+	if _, err := config.ArchiveCollection.InsertMany(ctx, archived); err != nil {
+		return err
+	}
+
+	_, err = collection.DeleteMany(ctx, filter)
+	return err
+}
+
+// archiver returns a cascade that archives the documents matching filter
+func archiver(collection *mongo.Collection, filter bson.M, reason string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return archiveDocuments(ctx, collection, filter, reason)
+	}
+}
+
+// archiveMatches archives the matching matches together with their tickets and stats,
+// and detaches bracket links pointing at them
+func archiveMatches(ctx context.Context, filter bson.M, reason string) error {
+	ids, err := config.MatchesCollection.Distinct(ctx, "_id", filter)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	byMatch := bson.M{"match_id": bson.M{"$in": ids}}
+	for _, collection := range []*mongo.Collection{config.UserTicketsCollection, config.PlayerStatsCollection} {
+		if err := archiveDocuments(ctx, collection, byMatch, reason); err != nil {
+			return err
+		}
+	}
+	if err := archiveDocuments(ctx, config.MatchesCollection, bson.M{"_id": bson.M{"$in": ids}}, reason); err != nil {
+		return err
+	}
+	return detachMatches(ctx, ids)
+}
+
+// detachMatches clears the bracket links of other matches that point at the given matches
+func detachMatches(ctx context.Context, ids []interface{}) error {
+	links := []struct{ id, slot string }{
+		{"next_match_id", "next_match_slot"},
+		{"loser_next_match_id", "loser_next_match_slot"},
+	}
+	for _, link := range links {
+		_, err := config.MatchesCollection.UpdateMany(ctx,
+			bson.M{link.id: bson.M{"$in": ids}},
+			bumpVersion(bson.M{"$unset": bson.M{link.id: "", link.slot: ""}}),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteReason describes why dependent documents were archived
+func deleteReason(entity string, id primitive.ObjectID) string {
+	return fmt.Sprintf("%s %s dihapus", entity, id.Hex())
+}
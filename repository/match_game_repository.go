@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/bracket"
+	"embeck/pkg/matchstate"
+	"embeck/pkg/series"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AddMatchGame appends a game to a match series and recalculates the series result
+func AddMatchGame(ctx context.Context, matchID string, game model.MatchGame, actor primitive.ObjectID) (*model.MatchGame, *model.Match, error) {
+	match, err := findMatchForGames(ctx, matchID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if series.Finished(*match) {
+		return nil, nil, fmt.Errorf("Series untuk Match ID %s sudah selesai", matchID)
+	}
+
+	if err := validateMatchGame(ctx, *match, game); err != nil {
+		return nil, nil, err
+	}
+
+	game.ID = primitive.NewObjectID()
+	game.GameNumber = len(match.Games) + 1
+	game.Status = series.GameValid
+	game.CreatedAt = time.Now()
+	game.UpdatedAt = time.Now()
+
+	after := *match
+	after.Games = append(append([]model.MatchGame{}, match.Games...), game)
+	if err := checkSeriesChange(*match, after); err != nil {
+		return nil, nil, err
+	}
+
+	// The game number and the series check above come from this version of the match, so a
+	// concurrent game submission makes this update match nothing instead of pushing twice
+	result, err := config.MatchesCollection.UpdateOne(ctx,
+		versionFilter(match.ID, match.Version),
+		bumpVersion(bson.M{"$push": bson.M{"games": game}}),
+	)
+	if err != nil {
+		fmt.Printf("AddMatchGame: %v\n", err)
+		return nil, nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil, staleOrMissing(ctx, config.MatchesCollection, "Match", match.ID)
+	}
+
+	updated, err := syncSeriesResult(ctx, match.ID, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &game, updated, nil
+}
+
+// UpdateMatchGame edits the non-empty fields of a game and recalculates the series result
+func UpdateMatchGame(ctx context.Context, matchID, gameID string, update model.MatchGame, actor primitive.ObjectID) (*model.Match, error) {
+	match, err := findMatchForGames(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := findMatchGame(*match, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status == series.GameVoid {
+		return nil, fmt.Errorf("Game %s sudah di-void dan tidak dapat diubah", gameID)
+	}
+
+	if !update.WinnerTeamID.IsZero() {
+		game.WinnerTeamID = update.WinnerTeamID
+	}
+	if update.DurationSeconds > 0 {
+		game.DurationSeconds = update.DurationSeconds
+	}
+	if update.BlueTeamID != nil {
+		game.BlueTeamID = update.BlueTeamID
+	}
+	if update.RedTeamID != nil {
+		game.RedTeamID = update.RedTeamID
+	}
+	if update.MVPPlayerID != nil {
+		game.MVPPlayerID = update.MVPPlayerID
+	}
+	game.UpdatedAt = time.Now()
+
+	if err := validateMatchGame(ctx, *match, game); err != nil {
+		return nil, err
+	}
+	if err := checkSeriesChange(*match, withGame(*match, game)); err != nil {
+		return nil, err
+	}
+
+	filter := versionFilter(match.ID, match.Version)
+	filter["games._id"] = game.ID
+	result, err := config.MatchesCollection.UpdateOne(ctx,
+		filter,
+		bumpVersion(bson.M{"$set": bson.M{"games.$": game}}),
+	)
+	if err != nil {
+		fmt.Printf("UpdateMatchGame: %v\n", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, staleOrMissing(ctx, config.MatchesCollection, "Match", match.ID)
+	}
+
+	if err := SyncGameStatsResult(ctx, game); err != nil {
+		return nil, err
+	}
+
+	return syncSeriesResult(ctx, match.ID, actor)
+}
+
+// VoidMatchGame marks a game as void so it no longer counts towards the series score
+func VoidMatchGame(ctx context.Context, matchID, gameID, reason string, actor primitive.ObjectID) (*model.Match, error) {
+	match, err := findMatchForGames(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := findMatchGame(*match, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status == series.GameVoid {
+		return nil, fmt.Errorf("Game %s sudah di-void", gameID)
+	}
+
+	voided := game
+	voided.Status = series.GameVoid
+	if err := checkSeriesChange(*match, withGame(*match, voided)); err != nil {
+		return nil, err
+	}
+
+	filter := versionFilter(match.ID, match.Version)
+	filter["games._id"] = game.ID
+	result, err := config.MatchesCollection.UpdateOne(ctx,
+		filter,
+		bumpVersion(bson.M{"$set": bson.M{
+			"games.$.status":      series.GameVoid,
+			"games.$.void_reason": reason,
+			"games.$.updated_at":  time.Now(),
+		}}),
+	)
+	if err != nil {
+		fmt.Printf("VoidMatchGame: %v\n", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, staleOrMissing(ctx, config.MatchesCollection, "Match", match.ID)
+	}
+
+	game.Status = series.GameVoid
+	if err := SyncGameStatsResult(ctx, game); err != nil {
+		return nil, err
+	}
+
+	return syncSeriesResult(ctx, match.ID, actor)
+}
+
+// findMatchForGames loads a match that is set up as a best-of-N series
+func findMatchForGames(ctx context.Context, matchID string) (*model.Match, error) {
+	match, err := GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, fmt.Errorf("Match dengan ID %s tidak ditemukan", matchID)
+	}
+	if match.BestOf == 0 {
+		return nil, fmt.Errorf("Match dengan ID %s belum memiliki best_of", matchID)
+	}
+	if !matchstate.AcceptsGames(match.Status) {
+		return nil, fmt.Errorf("Game hanya dapat dicatat untuk match dengan status checked_in, ongoing atau completed")
+	}
+	return match, nil
+}
+
+// findMatchGame looks up a game inside a match by its ID
+func findMatchGame(match model.Match, gameID string) (model.MatchGame, error) {
+	gameObjID, err := primitive.ObjectIDFromHex(gameID)
+	if err != nil {
+		return model.MatchGame{}, fmt.Errorf("invalid game ID format")
+	}
+	for _, game := range match.Games {
+		if game.ID == gameObjID {
+			return game, nil
+		}
+	}
+	return model.MatchGame{}, fmt.Errorf("Game dengan ID %s tidak ditemukan", gameID)
+}
+
+// validateMatchGame checks that the game's teams belong to the match and the MVP plays for one of them
+func validateMatchGame(ctx context.Context, match model.Match, game model.MatchGame) error {
+	isMatchTeam := func(teamID primitive.ObjectID) bool {
+		return teamID == match.TeamAID || teamID == match.TeamBID
+	}
+
+	if !isMatchTeam(game.WinnerTeamID) {
+		return fmt.Errorf("Winner team harus team A atau team B")
+	}
+	if game.BlueTeamID != nil && !isMatchTeam(*game.BlueTeamID) {
+		return fmt.Errorf("Blue side team harus team A atau team B")
+	}
+	if game.RedTeamID != nil && !isMatchTeam(*game.RedTeamID) {
+		return fmt.Errorf("Red side team harus team A atau team B")
+	}
+	if game.BlueTeamID != nil && game.RedTeamID != nil && *game.BlueTeamID == *game.RedTeamID {
+		return fmt.Errorf("Blue side dan red side harus team yang berbeda")
+	}
+
+	if game.MVPPlayerID != nil {
+		members, err := getTeamMembers(ctx, match.TournamentID, []primitive.ObjectID{match.TeamAID, match.TeamBID})
+		if err != nil {
+			return err
+		}
+		if !members[match.TeamAID][*game.MVPPlayerID] && !members[match.TeamBID][*game.MVPPlayerID] {
+			return fmt.Errorf("MVP player dengan ID %s bukan anggota team A atau team B", game.MVPPlayerID.Hex())
+		}
+	}
+	return nil
+}
+
+// withGame returns a copy of the match with one of its games replaced
+func withGame(match model.Match, game model.MatchGame) model.Match {
+	games := make([]model.MatchGame, len(match.Games))
+	for i, existing := range match.Games {
+		games[i] = existing
+		if existing.ID == game.ID {
+			games[i] = game
+		}
+	}
+	match.Games = games
+	return match
+}
+
+// checkSeriesChange refuses a game change that would reopen a series its games already
+// finished, or change the winner of a match whose winner already moved on in the bracket
+func checkSeriesChange(before, after model.Match) error {
+	if before.Status == matchstate.Completed && series.Finished(before) && !series.Finished(after) {
+		return fmt.Errorf("Match sudah completed, perubahan game ini akan membatalkan hasil series")
+	}
+
+	// Games that do not finish the series leave the current result as it is
+	_, _, winner := series.Score(after)
+	if !series.Finished(after) {
+		winner = before.WinnerTeamID
+	}
+	if before.WinnerTeamID != nil && advancesWinner(before) && (winner == nil || *winner != *before.WinnerTeamID) {
+		return fmt.Errorf("Pemenang match sudah maju ke match berikutnya, hasil series tidak dapat diubah")
+	}
+	return nil
+}
+
+// advancesWinner reports whether the result of a match places teams into other bracket matches
+func advancesWinner(match model.Match) bool {
+	return match.NextMatchID != nil || match.LoserNextMatchID != nil || match.Bracket == bracket.BracketGrandFinal
+}
+
+// syncSeriesResult derives the series score, winner and status from the match's games. Status
+// changes follow the match state machine and are recorded like manual transitions. A newly
+// decided series advances its winner through the bracket like a manual result. A result entered
+// by hand on a completed match stays until the recorded games finish the series.
+func syncSeriesResult(ctx context.Context, matchID primitive.ObjectID, actor primitive.ObjectID) (*model.Match, error) {
+	var match model.Match
+	err := config.MatchesCollection.FindOne(ctx, bson.M{"_id": matchID}).Decode(&match)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("Match dengan ID %s tidak ditemukan", matchID.Hex())
+		}
+		return nil, err
+	}
+
+	scoreA, scoreB, winner := series.Score(match)
+	finished := series.Finished(match)
+	if match.Status == matchstate.Completed && !finished {
+		return &match, nil
+	}
+
+	path, err := matchstate.SeriesPath(match.Status, scoreA+scoreB > 0, finished)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"result_team_a_score": scoreA,
+		"result_team_b_score": scoreB,
+		"updated_at":          now,
+	}
+	update := bson.M{"$set": set}
+	if winner != nil {
+		set["winner_team_id"] = *winner
+	} else if match.WinnerTeamID != nil {
+		update["$unset"] = bson.M{"winner_team_id": ""}
+	}
+
+	var changes []model.MatchStatusChange
+	from := match.Status
+	for _, to := range path {
+		changes = append(changes, model.MatchStatusChange{
+			From:      from,
+			To:        to,
+			ChangedBy: actor,
+			ChangedAt: now,
+			Reason:    "Hasil series diperbarui",
+		})
+		from = to
+	}
+	if len(changes) > 0 {
+		set["status"] = from
+		update["$push"] = bson.M{"status_history": bson.M{"$each": changes}}
+	}
+
+	// Filtering on the current status keeps a concurrent transition from being overwritten
+	result, err := config.MatchesCollection.UpdateOne(ctx, bson.M{"_id": matchID, "status": match.Status}, bumpVersion(update))
+	if err != nil {
+		fmt.Printf("syncSeriesResult: %v\n", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("Status match sudah berubah, silakan muat ulang data match")
+	}
+
+	newWinner := winner != nil && (match.WinnerTeamID == nil || *match.WinnerTeamID != *winner)
+	if newWinner {
+		if err := AdvanceWinner(ctx, matchID); err != nil {
+			fmt.Printf("syncSeriesResult - Advance Winner: %v\n", err)
+			return nil, err
+		}
+	}
+
+	match.ResultTeamAScore = &scoreA
+	match.ResultTeamBScore = &scoreB
+	match.WinnerTeamID = winner
+	match.Status = from
+	match.StatusHistory = append(match.StatusHistory, changes...)
+	return &match, nil
+}
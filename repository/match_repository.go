@@ -4,6 +4,7 @@ import (
 	"context"
 	"embeck/config"
 	"embeck/model"
+	"embeck/pkg/matchstate"
 	"fmt"
 	"time"
 
@@ -14,61 +15,74 @@ import (
 
 // CreateMatch creates a new match
 func CreateMatch(ctx context.Context, match model.Match) (insertedID interface{}, err error) {
+	if err := validateMatchTeams(ctx, match); err != nil {
+		return nil, err
+	}
+
+	// Set timestamps
+	match.CreatedAt = time.Now()
+	match.UpdatedAt = time.Now()
+
+	// Insert match
+	insertResult, err := config.MatchesCollection.InsertOne(ctx, match)
+	if err != nil {
+		fmt.Printf("CreateMatch - Insert: %v\n", err)
+		return nil, err
+	}
+
+	return insertResult.InsertedID, nil
+}
+
+// validateMatchTeams checks that the tournament and the teams of a new match exist and are not
+// deleted, and that the teams are different. A bye has no opponent, so only team A is checked
+func validateMatchTeams(ctx context.Context, match model.Match) error {
 	// Validate tournament exists
-	tournamentFilter := bson.M{"_id": match.TournamentID}
+	tournamentFilter := notDeleted(bson.M{"_id": match.TournamentID})
 	tournamentCount, err := config.TournamentsCollection.CountDocuments(ctx, tournamentFilter)
 	if err != nil {
-		fmt.Printf("CreateMatch - Check Tournament: %v\n", err)
-		return nil, err
+		fmt.Printf("validateMatchTeams - Check Tournament: %v\n", err)
+		return err
 	}
 	if tournamentCount == 0 {
-		return nil, fmt.Errorf("Tournament dengan ID %s tidak ditemukan", match.TournamentID.Hex())
+		return fmt.Errorf("Tournament dengan ID %s tidak ditemukan", match.TournamentID.Hex())
 	}
 
 	// Validate team A exists
-	teamAFilter := bson.M{"_id": match.TeamAID}
+	teamAFilter := notDeleted(bson.M{"_id": match.TeamAID})
 	teamACount, err := config.TeamsCollection.CountDocuments(ctx, teamAFilter)
 	if err != nil {
-		fmt.Printf("CreateMatch - Check Team A: %v\n", err)
-		return nil, err
+		fmt.Printf("validateMatchTeams - Check Team A: %v\n", err)
+		return err
 	}
 	if teamACount == 0 {
-		return nil, fmt.Errorf("Team A dengan ID %s tidak ditemukan", match.TeamAID.Hex())
+		return fmt.Errorf("Team A dengan ID %s tidak ditemukan", match.TeamAID.Hex())
+	}
+
+	if match.IsBye {
+		return nil
 	}
 
 	// Validate team B exists
-	teamBFilter := bson.M{"_id": match.TeamBID}
+	teamBFilter := notDeleted(bson.M{"_id": match.TeamBID})
 	teamBCount, err := config.TeamsCollection.CountDocuments(ctx, teamBFilter)
 	if err != nil {
-		fmt.Printf("CreateMatch - Check Team B: %v\n", err)
-		return nil, err
+		fmt.Printf("validateMatchTeams - Check Team B: %v\n", err)
+		return err
 	}
 	if teamBCount == 0 {
-		return nil, fmt.Errorf("Team B dengan ID %s tidak ditemukan", match.TeamBID.Hex())
+		return fmt.Errorf("Team B dengan ID %s tidak ditemukan", match.TeamBID.Hex())
 	}
 
 	// Validate teams are different
 	if match.TeamAID == match.TeamBID {
-		return nil, fmt.Errorf("Team A dan Team B harus berbeda")
+		return fmt.Errorf("Team A dan Team B harus berbeda")
 	}
-
-	// Set timestamps
-	match.CreatedAt = time.Now()
-	match.UpdatedAt = time.Now()
-
-	// Insert match
-	insertResult, err := config.MatchesCollection.InsertOne(ctx, match)
-	if err != nil {
-		fmt.Printf("CreateMatch - Insert: %v\n", err)
-		return nil, err
-	}
-
-	return insertResult.InsertedID, nil
+	return nil
 }
 
 // GetAllMatches retrieves all matches with populated team details
 func GetAllMatches(ctx context.Context, tournamentID string) ([]model.MatchWithDetails, error) {
-	pipeline := []bson.M{}
+	filter := notDeleted(bson.M{})
 
 	// Add tournament filter if provided
 	if tournamentID != "" && tournamentID != "all" {
@@ -76,8 +90,9 @@ func GetAllMatches(ctx context.Context, tournamentID string) ([]model.MatchWithD
 		if err != nil {
 			return nil, fmt.Errorf("invalid tournament ID format")
 		}
-		pipeline = append(pipeline, bson.M{"$match": bson.M{"tournament_id": objID}})
+		filter["tournament_id"] = objID
 	}
+	pipeline := []bson.M{{"$match": filter}}
 
 	// Add stages to lookup team details
 	lookupStages := []bson.M{
@@ -99,20 +114,39 @@ func GetAllMatches(ctx context.Context, tournamentID string) ([]model.MatchWithD
 		},
 		{
 			"$project": bson.M{
-				"_id":                 1,
-				"tournament_id":       1,
-				"team_a_id":           1,
-				"team_b_id":           1,
-				"match_date":          1,
-				"match_time":          1,
-				"location":            1,
-				"round":               1,
-				"result_team_a_score": 1,
-				"result_team_b_score": 1,
-				"winner_team_id":      1,
-				"status":              1,
-				"created_at":          1,
-				"updated_at":          1,
+				"_id":                   1,
+				"tournament_id":         1,
+				"team_a_id":             1,
+				"team_b_id":             1,
+				"match_date":            1,
+				"match_time":            1,
+				"location":              1,
+				"round":                 1,
+				"result_team_a_score":   1,
+				"result_team_b_score":   1,
+				"winner_team_id":        1,
+				"status":                1,
+				"bracket":               1,
+				"bracket_round":         1,
+				"bracket_position":      1,
+				"next_match_id":         1,
+				"next_match_slot":       1,
+				"loser_next_match_id":   1,
+				"loser_next_match_slot": 1,
+				"is_bye":                1,
+				"group":                 1,
+				"matchday":              1,
+				"swiss_round":           1,
+				"best_of":               1,
+				"games":                 1,
+				"status_history":        1,
+				"outcome":               1,
+				"outcome_reason":        1,
+				"forfeiting_team_id":    1,
+				"overdue":               1,
+				"created_at":            1,
+				"updated_at":            1,
+				"version":               1,
 				"team_a": bson.M{
 					"$arrayElemAt": []interface{}{
 						bson.M{
@@ -163,7 +197,7 @@ func GetMatchByID(ctx context.Context, id string) (*model.Match, error) {
 	}
 
 	var match model.Match
-	filter := bson.M{"_id": objID}
+	filter := notDeleted(bson.M{"_id": objID})
 	err = config.MatchesCollection.FindOne(ctx, filter).Decode(&match)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -183,7 +217,7 @@ func GetMatchWithDetailsByID(ctx context.Context, id string) (*model.MatchWithDe
 
 	pipeline := []bson.M{
 		{
-			"$match": bson.M{"_id": objID},
+			"$match": notDeleted(bson.M{"_id": objID}),
 		},
 		{
 			"$lookup": bson.M{
@@ -203,20 +237,39 @@ func GetMatchWithDetailsByID(ctx context.Context, id string) (*model.MatchWithDe
 		},
 		{
 			"$project": bson.M{
-				"_id":                 1,
-				"tournament_id":       1,
-				"team_a_id":           1,
-				"team_b_id":           1,
-				"match_date":          1,
-				"match_time":          1,
-				"location":            1,
-				"round":               1,
-				"result_team_a_score": 1,
-				"result_team_b_score": 1,
-				"winner_team_id":      1,
-				"status":              1,
-				"created_at":          1,
-				"updated_at":          1,
+				"_id":                   1,
+				"tournament_id":         1,
+				"team_a_id":             1,
+				"team_b_id":             1,
+				"match_date":            1,
+				"match_time":            1,
+				"location":              1,
+				"round":                 1,
+				"result_team_a_score":   1,
+				"result_team_b_score":   1,
+				"winner_team_id":        1,
+				"status":                1,
+				"bracket":               1,
+				"bracket_round":         1,
+				"bracket_position":      1,
+				"next_match_id":         1,
+				"next_match_slot":       1,
+				"loser_next_match_id":   1,
+				"loser_next_match_slot": 1,
+				"is_bye":                1,
+				"group":                 1,
+				"matchday":              1,
+				"swiss_round":           1,
+				"best_of":               1,
+				"games":                 1,
+				"status_history":        1,
+				"outcome":               1,
+				"outcome_reason":        1,
+				"forfeiting_team_id":    1,
+				"overdue":               1,
+				"created_at":            1,
+				"updated_at":            1,
+				"version":               1,
 				"team_a": bson.M{
 					"$arrayElemAt": []interface{}{
 						bson.M{
@@ -269,8 +322,9 @@ func GetMatchWithDetailsByID(ctx context.Context, id string) (*model.MatchWithDe
 	return &matches[0], nil
 }
 
-// UpdateMatch updates match data
-func UpdateMatch(ctx context.Context, id string, update bson.M) (updatedID string, err error) {
+// UpdateMatch updates match data. The update only applies while the match is still at the given
+// version, otherwise ErrStaleVersion is returned
+func UpdateMatch(ctx context.Context, id string, update bson.M, version int64) (updatedID string, err error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", fmt.Errorf("invalid match ID format")
@@ -295,43 +349,132 @@ func UpdateMatch(ctx context.Context, id string, update bson.M) (updatedID strin
 		return "", fmt.Errorf("Team A dan Team B harus berbeda")
 	}
 
-	// Note: More complex validations like checking if team IDs exist in the teams collection,
-	// or validating winner_team_id against the participants might be needed here if not handled at the handler level.
-	// For now, we assume handler-level validation is sufficient for partial updates.
+	current, err := GetMatchByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if current == nil {
+		return "", fmt.Errorf("Match dengan ID %s tidak ditemukan", id)
+	}
+
+	// A match stays in its tournament, so staff scoped to one tournament cannot move it into another
+	if v, ok := update["tournament_id"].(primitive.ObjectID); ok && v != current.TournamentID {
+		return "", fmt.Errorf("Match tidak dapat dipindahkan ke tournament lain")
+	}
+
+	// Status changes go through the transition endpoints so their preconditions are checked
+	if status, ok := update["status"]; ok && status != current.Status {
+		return "", fmt.Errorf("Status match hanya dapat diubah melalui endpoint transisi status")
+	}
+
+	// Validate the match as it will look after this partial update
+	merged := *current
+	if v, ok := update["team_a_id"].(primitive.ObjectID); ok {
+		merged.TeamAID = v
+	}
+	if v, ok := update["team_b_id"].(primitive.ObjectID); ok {
+		merged.TeamBID = v
+	}
+	if v, ok := update["result_team_a_score"].(*int); ok {
+		merged.ResultTeamAScore = v
+	}
+	if v, ok := update["result_team_b_score"].(*int); ok {
+		merged.ResultTeamBScore = v
+	}
+	if v, ok := update["best_of"].(int); ok {
+		merged.BestOf = v
+		if v%2 == 0 && !matchstate.DrawAllowed(merged) {
+			return "", fmt.Errorf("Match bracket dan Swiss harus memakai best_of ganjil karena tidak boleh berakhir seri")
+		}
+	}
+	_, winnerChanged := update["winner_team_id"]
+	if v, ok := update["winner_team_id"].(*primitive.ObjectID); ok {
+		merged.WinnerTeamID = v
+	}
+
+	if winnerChanged && current.Status != matchstate.Completed && current.Status != matchstate.Forfeited {
+		return "", fmt.Errorf("Winner team hanya dapat diubah untuk match yang sudah completed atau forfeited")
+	}
+	if merged.WinnerTeamID != nil && *merged.WinnerTeamID != merged.TeamAID && *merged.WinnerTeamID != merged.TeamBID {
+		return "", fmt.Errorf("Winner team harus team A atau team B")
+	}
+	if merged.Status == matchstate.Completed && !merged.IsBye {
+		if err := matchstate.ValidateResult(merged); err != nil {
+			return "", err
+		}
+	}
+
+	// A corrected winner moves teams in the bracket, which is only possible until the matches
+	// they were placed into have started
+	if winnerChanged && current.WinnerTeamID != nil && (merged.WinnerTeamID == nil || *merged.WinnerTeamID != *current.WinnerTeamID) {
+		if err := checkNextMatchesOpen(ctx, *current); err != nil {
+			return "", err
+		}
+	}
 
 	update["updated_at"] = time.Now()
 
-	filter := bson.M{"_id": objID}
-	updateData := bson.M{"$set": update}
+	filter := notDeleted(versionFilter(objID, version))
+	updateData := bumpVersion(bson.M{"$set": update})
 
 	result, err := config.MatchesCollection.UpdateOne(ctx, filter, updateData)
 	if err != nil {
 		fmt.Printf("UpdateMatch: %v\n", err)
 		return "", err
 	}
-	if result.ModifiedCount == 0 {
-		// This could also mean the data sent was the same as the existing data.
-		// To differentiate, a find and compare would be needed, but for now, this is acceptable.
-		return "", fmt.Errorf("tidak ada data yang diupdate untuk Match ID %s, atau data yang dikirim sama", id)
+	if result.MatchedCount == 0 {
+		return "", staleOrMissing(ctx, config.MatchesCollection, "Match", objID)
+	}
+
+	// Move the winner into the next bracket match, if this match is part of a bracket
+	if winnerChanged {
+		if err := AdvanceWinner(ctx, objID); err != nil {
+			fmt.Printf("UpdateMatch - Advance Winner: %v\n", err)
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+// DeleteMatch moves a match to the trash
+func DeleteMatch(ctx context.Context, id string, actor primitive.ObjectID) (deletedID string, err error) {
+	if err := moveToTrash(ctx, model.TrashMatch, id, actor); err != nil {
+		fmt.Printf("DeleteMatch: %v\n", err)
+		return "", err
 	}
 	return id, nil
 }
 
-// DeleteMatch deletes match by ID
-func DeleteMatch(ctx context.Context, id string) (deletedID string, err error) {
+// PurgeMatch permanently deletes a trashed match. Without cascade the purge is refused while
+// tickets, stats or bracket links still reference the match; with cascade they are archived or detached
+func PurgeMatch(ctx context.Context, id string, cascade bool) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return "", fmt.Errorf("invalid match ID format")
+		return fmt.Errorf("invalid match ID format")
 	}
 
-	filter := bson.M{"_id": objID}
-	result, err := config.MatchesCollection.DeleteOne(ctx, filter)
+	err = purgeWithReferences(ctx, "Match", config.MatchesCollection, objID, matchReferences(objID), cascade)
 	if err != nil {
-		fmt.Printf("DeleteMatch: %v\n", err)
-		return "", err
+		fmt.Printf("PurgeMatch: %v\n", err)
 	}
-	if result.DeletedCount == 0 {
-		return "", fmt.Errorf("tidak ada data yang dihapus untuk Match ID %s", id)
+	return err
+}
+
+// matchReferences lists the records that point at a match
+func matchReferences(matchID primitive.ObjectID) []reference {
+	reason := deleteReason("match", matchID)
+	byMatch := bson.M{"match_id": matchID}
+
+	return []reference{
+		{config.UserTicketsCollection, "match_id", byMatch, archiver(config.UserTicketsCollection, byMatch, reason)},
+		{config.PlayerStatsCollection, "match_id", byMatch, archiver(config.PlayerStatsCollection, byMatch, reason)},
+		{
+			collection: config.MatchesCollection,
+			field:      "next_match_id/loser_next_match_id",
+			filter:     bson.M{"$or": []bson.M{{"next_match_id": matchID}, {"loser_next_match_id": matchID}}},
+			cascade: func(ctx context.Context) error {
+				return detachMatches(ctx, []interface{}{matchID})
+			},
+		},
 	}
-	return id, nil
 }
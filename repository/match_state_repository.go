@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"embeck/pkg/series"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TransitionMatch moves a match to a new status after checking that the transition is
+// allowed and its preconditions hold, and records who made the change
+func TransitionMatch(ctx context.Context, matchID string, t model.MatchTransition) (*model.Match, error) {
+	match, err := GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, fmt.Errorf("Match dengan ID %s tidak ditemukan", matchID)
+	}
+
+	from := match.Status
+	if !matchstate.CanTransition(from, t.To) {
+		return nil, fmt.Errorf("Status match tidak dapat diubah dari %s ke %s", from, t.To)
+	}
+
+	set := bson.M{"status": t.To}
+	switch t.To {
+	case matchstate.CheckedIn:
+		if match.IsBye || match.TeamAID.IsZero() || match.TeamBID.IsZero() {
+			return nil, fmt.Errorf("Kedua team harus sudah ditentukan sebelum check-in")
+		}
+
+	case matchstate.Scheduled:
+		if t.MatchDate.IsZero() {
+			return nil, fmt.Errorf("match_date baru wajib diisi untuk menjadwalkan ulang match")
+		}
+		set["match_date"] = t.MatchDate
+		if t.MatchTime != "" {
+			set["match_time"] = t.MatchTime
+		}
+
+	case matchstate.Postponed, matchstate.Cancelled:
+		if strings.TrimSpace(t.Reason) == "" {
+			return nil, fmt.Errorf("Alasan wajib diisi")
+		}
+
+	case matchstate.Completed:
+		// A series with recorded games derives its result from them unless scores are given
+		if match.BestOf > 0 && len(match.Games) > 0 && t.ResultTeamAScore == nil && t.ResultTeamBScore == nil {
+			scoreA, scoreB, winner := series.Score(*match)
+			match.ResultTeamAScore, match.ResultTeamBScore = &scoreA, &scoreB
+			if winner != nil {
+				match.WinnerTeamID = winner
+			}
+		}
+		applyMatchResult(match, t)
+		if err := matchstate.ValidateResult(*match); err != nil {
+			return nil, err
+		}
+		set["result_team_a_score"] = match.ResultTeamAScore
+		set["result_team_b_score"] = match.ResultTeamBScore
+		set["winner_team_id"] = match.WinnerTeamID
+
+	case matchstate.Forfeited:
+		if strings.TrimSpace(t.Reason) == "" {
+			return nil, fmt.Errorf("Alasan wajib diisi")
+		}
+		if match.IsBye || match.TeamAID.IsZero() || match.TeamBID.IsZero() {
+			return nil, fmt.Errorf("Forfeit hanya dapat dicatat jika kedua team sudah ditentukan")
+		}
+		if err := applyForfeit(match, t); err != nil {
+			return nil, err
+		}
+		set["winner_team_id"] = match.WinnerTeamID
+		set["result_team_a_score"] = match.ResultTeamAScore
+		set["result_team_b_score"] = match.ResultTeamBScore
+		set["outcome"] = match.Outcome
+		set["outcome_reason"] = match.OutcomeReason
+		set["forfeiting_team_id"] = match.ForfeitingTeamID
+	}
+
+	now := time.Now()
+	set["updated_at"] = now
+	change := model.MatchStatusChange{
+		From:      from,
+		To:        t.To,
+		ChangedBy: t.ChangedBy,
+		ChangedAt: now,
+		Reason:    t.Reason,
+	}
+
+	// Filtering on the current status keeps two concurrent transitions from both succeeding
+	result, err := config.MatchesCollection.UpdateOne(ctx,
+		bson.M{"_id": match.ID, "status": from},
+		bumpVersion(bson.M{"$set": set, "$push": bson.M{"status_history": change}}),
+	)
+	if err != nil {
+		fmt.Printf("TransitionMatch: %v\n", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("Status match sudah berubah, silakan muat ulang data match")
+	}
+
+	if t.To == matchstate.Completed || t.To == matchstate.Forfeited {
+		if err := AdvanceWinner(ctx, match.ID); err != nil {
+			fmt.Printf("TransitionMatch - Advance Winner: %v\n", err)
+			return nil, err
+		}
+	}
+
+	match.Status = t.To
+	match.StatusHistory = append(match.StatusHistory, change)
+	return match, nil
+}
+
+// applyForfeit works out the forfeiting team and the winner of a forfeit and awards the
+// default score unless both scores are given
+func applyForfeit(match *model.Match, t model.MatchTransition) error {
+	outcome := t.Outcome
+	if outcome == "" {
+		outcome = matchstate.OutcomeForfeit
+	}
+	if !matchstate.ManualOutcomes[outcome] && outcome != matchstate.OutcomeDisqualification {
+		return fmt.Errorf("Outcome forfeit tidak valid: %s", outcome)
+	}
+
+	// Either the forfeiting team or the winner identifies both sides
+	forfeiting := t.ForfeitingTeamID
+	if forfeiting == nil && t.WinnerTeamID != nil {
+		other := match.TeamAID
+		if *t.WinnerTeamID == match.TeamAID {
+			other = match.TeamBID
+		}
+		forfeiting = &other
+	}
+	if forfeiting == nil {
+		return fmt.Errorf("forfeiting_team_id atau winner_team_id wajib diisi")
+	}
+
+	var winner primitive.ObjectID
+	switch *forfeiting {
+	case match.TeamAID:
+		winner = match.TeamBID
+	case match.TeamBID:
+		winner = match.TeamAID
+	default:
+		return fmt.Errorf("Team yang forfeit harus team A atau team B")
+	}
+	if t.WinnerTeamID != nil && *t.WinnerTeamID != winner {
+		return fmt.Errorf("Winner team harus lawan dari team yang forfeit")
+	}
+
+	scoreA, scoreB := t.ResultTeamAScore, t.ResultTeamBScore
+	if scoreA == nil || scoreB == nil {
+		winnerScore, loserScore := matchstate.ForfeitScore(match.BestOf)
+		if winner == match.TeamAID {
+			scoreA, scoreB = &winnerScore, &loserScore
+		} else {
+			scoreA, scoreB = &loserScore, &winnerScore
+		}
+	}
+
+	match.WinnerTeamID = &winner
+	match.ResultTeamAScore = scoreA
+	match.ResultTeamBScore = scoreB
+	match.Outcome = outcome
+	match.OutcomeReason = t.Reason
+	match.ForfeitingTeamID = forfeiting
+	return matchstate.ValidateResult(*match)
+}
+
+// applyMatchResult copies the scores and winner given with a transition onto the match
+func applyMatchResult(match *model.Match, t model.MatchTransition) {
+	if t.ResultTeamAScore != nil {
+		match.ResultTeamAScore = t.ResultTeamAScore
+	}
+	if t.ResultTeamBScore != nil {
+		match.ResultTeamBScore = t.ResultTeamBScore
+	}
+	if t.WinnerTeamID != nil {
+		match.WinnerTeamID = t.WinnerTeamID
+	}
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/roster"
+	"embeck/pkg/transfers"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// syncTeamMemberships brings the open membership periods of a team in line with its current
+// roster: periods of players who left or changed role are closed and new ones are opened.
+// It is called in the same transaction as every roster change, so the periods never fall out
+// of step with the roster; a deleted team has all its periods closed.
+// Teams that never had a period recorded get their first periods from the team's creation date
+func syncTeamMemberships(ctx context.Context, teamID primitive.ObjectID) error {
+	var team model.Team
+	err := config.TeamsCollection.FindOne(ctx, notDeleted(bson.M{"_id": teamID})).Decode(&team)
+	if err != nil && err != mongo.ErrNoDocuments {
+		fmt.Printf("syncTeamMemberships - Find Team: %v\n", err)
+		return err
+	}
+
+	cursor, err := config.TeamMembershipsCollection.Find(ctx, bson.M{"team_id": teamID, "left_at": bson.M{"$exists": false}})
+	if err != nil {
+		fmt.Printf("syncTeamMemberships - Find Open: %v\n", err)
+		return err
+	}
+	var open []model.TeamMembership
+	if err := cursor.All(ctx, &open); err != nil {
+		fmt.Printf("syncTeamMemberships - Decode Open: %v\n", err)
+		return err
+	}
+
+	now := time.Now()
+	joinedAt := now
+	if len(open) == 0 && !team.CreatedAt.IsZero() {
+		count, err := config.TeamMembershipsCollection.CountDocuments(ctx, bson.M{"team_id": teamID})
+		if err != nil {
+			fmt.Printf("syncTeamMemberships - Count: %v\n", err)
+			return err
+		}
+		if count == 0 {
+			joinedAt = team.CreatedAt
+		}
+	}
+
+	current := make(map[primitive.ObjectID]string)
+	teamRoster := roster.Of(team)
+	for _, member := range teamRoster {
+		current[member.PlayerID] = member.Role
+	}
+
+	var closed []primitive.ObjectID
+	for _, membership := range open {
+		if role, ok := current[membership.PlayerID]; ok && role == membership.Role {
+			delete(current, membership.PlayerID)
+			continue
+		}
+		closed = append(closed, membership.ID)
+	}
+	if len(closed) > 0 {
+		_, err = config.TeamMembershipsCollection.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": closed}, "left_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"left_at": now}},
+		)
+		if err != nil {
+			fmt.Printf("syncTeamMemberships - Close: %v\n", err)
+			return err
+		}
+	}
+
+	var opened []interface{}
+	for _, member := range teamRoster {
+		if _, ok := current[member.PlayerID]; !ok {
+			continue
+		}
+		opened = append(opened, model.TeamMembership{
+			ID:       primitive.NewObjectID(),
+			TeamID:   teamID,
+			PlayerID: member.PlayerID,
+			Role:     member.Role,
+			JoinedAt: joinedAt,
+		})
+	}
+	if len(opened) > 0 {
+		if _, err := config.TeamMembershipsCollection.InsertMany(ctx, opened); err != nil {
+			fmt.Printf("syncTeamMemberships - Open: %v\n", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillTeamMemberships records the current roster of a team that has no membership
+// periods yet, so a roster change about to happen is recorded against the old roster. Like
+// syncTeamMemberships it runs in the transaction of the roster change
+func backfillTeamMemberships(ctx context.Context, teamID primitive.ObjectID) error {
+	count, err := config.TeamMembershipsCollection.CountDocuments(ctx, bson.M{"team_id": teamID})
+	if err != nil {
+		fmt.Printf("backfillTeamMemberships - Count: %v\n", err)
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return syncTeamMemberships(ctx, teamID)
+}
+
+// GetPlayerTransferHistory retrieves the membership periods and transfers of a player. Periods are
+// recorded when rosters change, so reading them never writes
+func GetPlayerTransferHistory(ctx context.Context, playerID primitive.ObjectID) (*model.PlayerTransferHistory, error) {
+	memberships, err := findMemberships(ctx, bson.M{"player_id": playerID})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &model.PlayerTransferHistory{
+		PlayerID:    playerID.Hex(),
+		Memberships: memberships,
+		Transfers:   transfers.Build(memberships),
+	}
+	if history.Memberships == nil {
+		history.Memberships = []model.TeamMembership{}
+	}
+	if history.Transfers == nil {
+		history.Transfers = []model.Transfer{}
+	}
+	return history, nil
+}
+
+// GetTeamTransferHistory retrieves the membership periods of a team and the transfers in and out of it
+func GetTeamTransferHistory(ctx context.Context, teamID primitive.ObjectID) (*model.TeamTransferHistory, error) {
+	memberships, err := findMemberships(ctx, bson.M{"team_id": teamID})
+	if err != nil {
+		return nil, err
+	}
+
+	// Transfers in and out need the players' periods on other teams as well
+	playerIDs := make([]primitive.ObjectID, 0, len(memberships))
+	for _, membership := range memberships {
+		playerIDs = append(playerIDs, membership.PlayerID)
+	}
+	careers, err := findMemberships(ctx, bson.M{"player_id": bson.M{"$in": playerIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &model.TeamTransferHistory{
+		TeamID:      teamID.Hex(),
+		Memberships: memberships,
+		Transfers:   []model.Transfer{},
+	}
+	if history.Memberships == nil {
+		history.Memberships = []model.TeamMembership{}
+	}
+	for _, transfer := range transfers.Build(careers) {
+		if involvesTeam(transfer, teamID) {
+			history.Transfers = append(history.Transfers, transfer)
+		}
+	}
+	return history, nil
+}
+
+// GetRecentTransfers retrieves the transfers since the given time, newest first
+func GetRecentTransfers(ctx context.Context, since time.Time, limit int) ([]model.Transfer, error) {
+	recent := bson.M{"$or": []bson.M{
+		{"joined_at": bson.M{"$gte": since}},
+		{"left_at": bson.M{"$gte": since}},
+	}}
+	playerIDs, err := config.TeamMembershipsCollection.Distinct(ctx, "player_id", recent)
+	if err != nil {
+		fmt.Println("GetRecentTransfers (Distinct):", err)
+		return nil, err
+	}
+	if len(playerIDs) == 0 {
+		return []model.Transfer{}, nil
+	}
+
+	memberships, err := findMemberships(ctx, bson.M{"player_id": bson.M{"$in": playerIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	feed := []model.Transfer{}
+	for _, transfer := range transfers.Build(memberships) {
+		if transfer.Date.Before(since) {
+			break
+		}
+		feed = append(feed, transfer)
+		if len(feed) == limit {
+			break
+		}
+	}
+	return feed, nil
+}
+
+// involvesTeam reports whether a transfer moves a player into or out of the team
+func involvesTeam(transfer model.Transfer, teamID primitive.ObjectID) bool {
+	return (transfer.FromTeam != nil && transfer.FromTeam.ID == teamID) ||
+		(transfer.ToTeam != nil && transfer.ToTeam.ID == teamID)
+}
+
+// findMemberships retrieves membership periods with their team and player attached, newest first
+func findMemberships(ctx context.Context, match bson.M) ([]model.TeamMembership, error) {
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.M{"joined_at": -1}},
+		{
+			"$lookup": bson.M{
+				"from":         "teams",
+				"localField":   "team_id",
+				"foreignField": "_id",
+				"as":           "team_details",
+			},
+		},
+		{
+			"$lookup": bson.M{
+				"from":         "players",
+				"localField":   "player_id",
+				"foreignField": "_id",
+				"as":           "player_details",
+			},
+		},
+		{
+			"$addFields": bson.M{
+				"team": bson.M{
+					"$let": bson.M{
+						"vars": bson.M{"t": bson.M{"$arrayElemAt": []interface{}{"$team_details", 0}}},
+						"in": bson.M{
+							"_id":       "$$t._id",
+							"team_name": "$$t.team_name",
+							"logo_url":  "$$t.logo_url",
+						},
+					},
+				},
+				"player": bson.M{
+					"$let": bson.M{
+						"vars": bson.M{"p": bson.M{"$arrayElemAt": []interface{}{"$player_details", 0}}},
+						"in": bson.M{
+							"_id":         "$$p._id",
+							"ml_nickname": "$$p.ml_nickname",
+							"ml_id":       "$$p.ml_id",
+							"avatar_url":  "$$p.avatar_url",
+						},
+					},
+				},
+			},
+		},
+		{"$project": bson.M{"team_details": 0, "player_details": 0}},
+	}
+
+	cursor, err := config.TeamMembershipsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Println("findMemberships (Aggregate):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var memberships []model.TeamMembership
+	if err := cursor.All(ctx, &memberships); err != nil {
+		fmt.Println("findMemberships (Decode):", err)
+		return nil, err
+	}
+	return memberships, nil
+}
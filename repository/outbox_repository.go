@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/mailer"
+	"fmt"
+	"time"
+)
+
+// OutboxMailer stores messages in the mail_outbox collection instead of sending them, for a
+// separate worker to deliver or for developers to read
+type OutboxMailer struct{}
+
+// Send stores the message as pending in the outbox
+func (OutboxMailer) Send(ctx context.Context, message mailer.Message) error {
+	_, err := config.MailOutboxCollection.InsertOne(ctx, model.OutboxMessage{
+		To:        message.To,
+		Subject:   message.Subject,
+		Body:      message.Body,
+		Status:    "pending",
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		fmt.Printf("OutboxMailer.Send: %v\n", err)
+	}
+	return err
+}
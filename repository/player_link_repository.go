@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RequestPlayerLink asks for the user to be linked to the player with the given ML ID.
+// The link takes effect once an admin verifies it.
+func RequestPlayerLink(ctx context.Context, userID primitive.ObjectID, mlID string) (insertedID interface{}, err error) {
+	var user model.User
+	err = config.UsersCollection.FindOne(ctx, notDeleted(bson.M{"_id": userID})).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("User dengan ID %s tidak ditemukan", userID.Hex())
+		}
+		return nil, err
+	}
+	if user.PlayerID != nil {
+		return nil, fmt.Errorf("User sudah terhubung dengan player")
+	}
+
+	var player model.Player
+	err = config.PlayersCollection.FindOne(ctx, notDeleted(bson.M{"ml_id": mlID})).Decode(&player)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("Player dengan ML ID %s tidak ditemukan", mlID)
+		}
+		return nil, err
+	}
+
+	linkedCount, err := config.UsersCollection.CountDocuments(ctx, bson.M{"player_id": player.ID})
+	if err != nil {
+		fmt.Printf("RequestPlayerLink - Check Linked Player: %v\n", err)
+		return nil, err
+	}
+	if linkedCount > 0 {
+		return nil, fmt.Errorf("Player dengan ML ID %s sudah terhubung dengan user lain", mlID)
+	}
+
+	pendingCount, err := config.PlayerLinksCollection.CountDocuments(ctx, bson.M{"user_id": userID, "status": model.PlayerLinkPending})
+	if err != nil {
+		fmt.Printf("RequestPlayerLink - Check Pending Link: %v\n", err)
+		return nil, err
+	}
+	if pendingCount > 0 {
+		return nil, fmt.Errorf("User sudah memiliki permintaan link yang menunggu verifikasi")
+	}
+
+	link := model.PlayerLink{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		PlayerID:  player.ID,
+		Status:    model.PlayerLinkPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	result, err := config.PlayerLinksCollection.InsertOne(ctx, link)
+	if err != nil {
+		fmt.Printf("RequestPlayerLink - Insert: %v\n", err)
+		return nil, err
+	}
+	return result.InsertedID, nil
+}
+
+// GetUserPlayerLinks retrieves the link requests of a user, newest first
+func GetUserPlayerLinks(ctx context.Context, userID primitive.ObjectID) ([]model.PlayerLink, error) {
+	return findPlayerLinks(ctx, bson.M{"user_id": userID}, -1)
+}
+
+// GetPlayerLinks retrieves link requests for admins, optionally filtered by status, oldest first
+func GetPlayerLinks(ctx context.Context, status string) ([]model.PlayerLink, error) {
+	match := bson.M{}
+	if status != "" {
+		match["status"] = status
+	}
+	return findPlayerLinks(ctx, match, 1)
+}
+
+// findPlayerLinks retrieves link requests with the username and player identity attached
+func findPlayerLinks(ctx context.Context, match bson.M, order int) ([]model.PlayerLink, error) {
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.M{"created_at": order}},
+		{
+			"$lookup": bson.M{
+				"from":         "users",
+				"localField":   "user_id",
+				"foreignField": "_id",
+				"as":           "user_details",
+			},
+		},
+		{
+			"$lookup": bson.M{
+				"from":         "players",
+				"localField":   "player_id",
+				"foreignField": "_id",
+				"as":           "player_details",
+			},
+		},
+		{
+			"$addFields": bson.M{
+				"username":    bson.M{"$arrayElemAt": []interface{}{"$user_details.username", 0}},
+				"ml_nickname": bson.M{"$arrayElemAt": []interface{}{"$player_details.ml_nickname", 0}},
+				"ml_id":       bson.M{"$arrayElemAt": []interface{}{"$player_details.ml_id", 0}},
+			},
+		},
+		{"$project": bson.M{"user_details": 0, "player_details": 0}},
+	}
+
+	cursor, err := config.PlayerLinksCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Println("findPlayerLinks (Aggregate):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var links []model.PlayerLink
+	if err := cursor.All(ctx, &links); err != nil {
+		fmt.Println("findPlayerLinks (Decode):", err)
+		return nil, err
+	}
+	return links, nil
+}
+
+// VerifyPlayerLink verifies a pending link request and links the user to the player
+func VerifyPlayerLink(ctx context.Context, id string, reviewer primitive.ObjectID) (*model.PlayerLink, error) {
+	link, err := reviewPlayerLink(ctx, id, reviewer, model.PlayerLinkVerified, "")
+	if err != nil {
+		return nil, err
+	}
+
+	linkedCount, err := config.UsersCollection.CountDocuments(ctx, bson.M{"player_id": link.PlayerID})
+	if err != nil {
+		return nil, revertPlayerLink(ctx, link.ID, err)
+	}
+	if linkedCount > 0 {
+		return nil, revertPlayerLink(ctx, link.ID, fmt.Errorf("Player sudah terhubung dengan user lain"))
+	}
+
+	result, err := config.UsersCollection.UpdateOne(ctx,
+		bson.M{"_id": link.UserID, "player_id": bson.M{"$exists": false}},
+		bumpVersion(bson.M{"$set": bson.M{"player_id": link.PlayerID, "updated_at": time.Now()}}),
+	)
+	if err != nil {
+		fmt.Printf("VerifyPlayerLink - Link User: %v\n", err)
+		return nil, revertPlayerLink(ctx, link.ID, err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, revertPlayerLink(ctx, link.ID, fmt.Errorf("User sudah terhubung dengan player atau tidak ditemukan"))
+	}
+	return link, nil
+}
+
+// RejectPlayerLink rejects a pending link request with a reason shown to the user
+func RejectPlayerLink(ctx context.Context, id string, reviewer primitive.ObjectID, reason string) (*model.PlayerLink, error) {
+	return reviewPlayerLink(ctx, id, reviewer, model.PlayerLinkRejected, reason)
+}
+
+// UnlinkPlayer removes the player link of a user
+func UnlinkPlayer(ctx context.Context, userID string) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID format")
+	}
+
+	result, err := config.UsersCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "player_id": bson.M{"$exists": true}},
+		bumpVersion(bson.M{"$unset": bson.M{"player_id": ""}, "$set": bson.M{"updated_at": time.Now()}}),
+	)
+	if err != nil {
+		fmt.Printf("UnlinkPlayer: %v\n", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("User dengan ID %s tidak ditemukan atau belum terhubung dengan player", userID)
+	}
+	return nil
+}
+
+// reviewPlayerLink moves a pending link request to its reviewed status
+func reviewPlayerLink(ctx context.Context, id string, reviewer primitive.ObjectID, status, reason string) (*model.PlayerLink, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid link ID format")
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"status":      status,
+		"reviewed_by": reviewer,
+		"reviewed_at": now,
+		"updated_at":  now,
+	}
+	if reason != "" {
+		set["rejection_reason"] = reason
+	}
+
+	var link model.PlayerLink
+	err = config.PlayerLinksCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objID, "status": model.PlayerLinkPending},
+		bson.M{"$set": set},
+	).Decode(&link)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			fmt.Printf("reviewPlayerLink: %v\n", err)
+			return nil, err
+		}
+		count, err := config.PlayerLinksCollection.CountDocuments(ctx, bson.M{"_id": objID})
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("Permintaan link dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("Permintaan link dengan ID %s sudah diproses", id)
+	}
+
+	link.Status = status
+	link.RejectionReason = reason
+	link.ReviewedBy = &reviewer
+	link.ReviewedAt = &now
+	return &link, nil
+}
+
+// revertPlayerLink puts a link request back in the queue after its verification failed
+func revertPlayerLink(ctx context.Context, id primitive.ObjectID, cause error) error {
+	_, err := config.PlayerLinksCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": model.PlayerLinkPending, "updated_at": time.Now()},
+		"$unset": bson.M{"reviewed_by": "", "reviewed_at": ""},
+	})
+	if err != nil {
+		fmt.Printf("revertPlayerLink: %v\n", err)
+	}
+	return cause
+}
+
+// IsTeamCaptain reports whether the user is linked to the player who captains the team
+func IsTeamCaptain(ctx context.Context, userID string, team model.Team) (bool, error) {
+	user, err := GetUserByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user == nil || user.PlayerID == nil {
+		return false, nil
+	}
+	return *user.PlayerID == team.CaptainID, nil
+}
@@ -52,7 +52,7 @@ func InsertPlayer(ctx context.Context, player model.Player) (insertedID interfac
 
 // GetAllPlayers retrieves all players
 func GetAllPlayers(ctx context.Context) ([]model.Player, error) {
-	filter := bson.M{}
+	filter := notDeleted(bson.M{})
 
 	cursor, err := config.PlayersCollection.Find(ctx, filter)
 	if err != nil {
@@ -76,7 +76,7 @@ func GetPlayerByID(ctx context.Context, id string) (player *model.Player, err er
 		return nil, fmt.Errorf("invalid player ID format")
 	}
 
-	filter := bson.M{"_id": objID}
+	filter := notDeleted(bson.M{"_id": objID})
 	err = config.PlayersCollection.FindOne(ctx, filter).Decode(&player)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -87,8 +87,9 @@ func GetPlayerByID(ctx context.Context, id string) (player *model.Player, err er
 	return player, nil
 }
 
-// UpdatePlayer updates player data
-func UpdatePlayer(ctx context.Context, id string, update model.Player) (updatedID string, err error) {
+// UpdatePlayer updates player data. The update only applies while the player is still at the
+// given version, otherwise ErrStaleVersion is returned
+func UpdatePlayer(ctx context.Context, id string, update model.Player, version int64) (updatedID string, err error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", fmt.Errorf("invalid player ID format")
@@ -97,35 +98,110 @@ func UpdatePlayer(ctx context.Context, id string, update model.Player) (updatedI
 	// Set updated timestamp
 	update.UpdatedAt = time.Now()
 
-	filter := bson.M{"_id": objID}
-	updateData := bson.M{"$set": update}
+	// The version is bumped below, so it must not be part of the $set
+	update.Version = 0
+
+	filter := notDeleted(versionFilter(objID, version))
+	updateData := bumpVersion(bson.M{"$set": update})
 
 	result, err := config.PlayersCollection.UpdateOne(ctx, filter, updateData)
 	if err != nil {
 		fmt.Printf("UpdatePlayer: %v\n", err)
 		return "", err
 	}
-	if result.ModifiedCount == 0 {
-		return "", fmt.Errorf("tidak ada data yang diupdate untuk Player ID %s", id)
+	if result.MatchedCount == 0 {
+		return "", staleOrMissing(ctx, config.PlayersCollection, "Player", objID)
+	}
+	return id, nil
+}
+
+// DeletePlayer moves a player to the trash
+func DeletePlayer(ctx context.Context, id string, actor primitive.ObjectID) (deletedID string, err error) {
+	if err := moveToTrash(ctx, model.TrashPlayer, id, actor); err != nil {
+		fmt.Printf("DeletePlayer: %v\n", err)
+		return "", err
 	}
 	return id, nil
 }
 
-// DeletePlayer deletes player by ID
-func DeletePlayer(ctx context.Context, id string) (deletedID string, err error) {
+// PurgePlayer permanently deletes a trashed player. Without cascade the purge is refused while
+// teams, users or other records still reference the player; with cascade the player is removed
+// from rosters, unlinked from users and their dependent records are archived. Captains always
+// block the purge
+func PurgePlayer(ctx context.Context, id string, cascade bool) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return "", fmt.Errorf("invalid player ID format")
+		return fmt.Errorf("invalid player ID format")
 	}
 
-	filter := bson.M{"_id": objID}
-	result, err := config.PlayersCollection.DeleteOne(ctx, filter)
+	// Teams losing the player get their membership periods closed afterwards
+	byMember := bson.M{"$or": []bson.M{{"members": objID}, {"roster.player_id": objID}}}
+	teamIDs, err := config.TeamsCollection.Distinct(ctx, "_id", byMember)
 	if err != nil {
-		fmt.Printf("DeletePlayer: %v\n", err)
-		return "", err
+		fmt.Printf("PurgePlayer - Find Teams: %v\n", err)
+		return err
 	}
-	if result.DeletedCount == 0 {
-		return "", fmt.Errorf("tidak ada data yang dihapus untuk Player ID %s", id)
+
+	err = purgeWithReferences(ctx, "Player", config.PlayersCollection, objID, playerReferences(objID), cascade)
+	if err != nil {
+		fmt.Printf("PurgePlayer: %v\n", err)
+		return err
+	}
+
+	for _, teamID := range teamIDs {
+		if teamID, ok := teamID.(primitive.ObjectID); ok {
+			if err := syncTeamMemberships(ctx, teamID); err != nil {
+				fmt.Printf("PurgePlayer - Close Memberships: %v\n", err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// playerReferences lists the records that point at a player
+func playerReferences(playerID primitive.ObjectID) []reference {
+	reason := deleteReason("player", playerID)
+	byPlayer := bson.M{"player_id": playerID}
+	byMember := bson.M{"$or": []bson.M{{"members": playerID}, {"roster.player_id": playerID}}}
+
+	return []reference{
+		{config.TeamsCollection, "captain_id", bson.M{"captain_id": playerID}, nil},
+		{
+			collection: config.TeamsCollection,
+			field:      "members/roster",
+			filter:     byMember,
+			cascade: func(ctx context.Context) error {
+				_, err := config.TeamsCollection.UpdateMany(ctx, byMember, bumpVersion(bson.M{
+					"$pull": bson.M{"members": playerID, "roster": bson.M{"player_id": playerID}},
+					"$set":  bson.M{"updated_at": time.Now()},
+				}))
+				return err
+			},
+		},
+		{
+			collection: config.UsersCollection,
+			field:      "player_id",
+			filter:     byPlayer,
+			cascade: func(ctx context.Context) error {
+				_, err := config.UsersCollection.UpdateMany(ctx, byPlayer, bumpVersion(bson.M{"$unset": bson.M{"player_id": ""}}))
+				return err
+			},
+		},
+		{
+			collection: config.TournamentRostersCollection,
+			field:      "roster.player_id",
+			filter:     bson.M{"roster.player_id": playerID},
+			cascade: func(ctx context.Context) error {
+				_, err := config.TournamentRostersCollection.UpdateMany(ctx,
+					bson.M{"roster.player_id": playerID},
+					bson.M{"$pull": bson.M{"roster": bson.M{"player_id": playerID}}},
+				)
+				return err
+			},
+		},
+		{config.PlayerLinksCollection, "player_id", byPlayer, archiver(config.PlayerLinksCollection, byPlayer, reason)},
+		{config.TeamInvitationsCollection, "player_id", byPlayer, archiver(config.TeamInvitationsCollection, byPlayer, reason)},
+		{config.PlayerStatsCollection, "player_id", byPlayer, archiver(config.PlayerStatsCollection, byPlayer, reason)},
 	}
-	return id, nil
 }
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/series"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReplaceGameStats replaces all player stat lines of one game in a match
+func ReplaceGameStats(ctx context.Context, matchID, gameID string, lines []model.PlayerGameStat) error {
+	match, err := findMatchForGames(ctx, matchID)
+	if err != nil {
+		return err
+	}
+
+	game, err := findMatchGame(*match, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status == series.GameVoid {
+		return fmt.Errorf("Game %s sudah di-void dan tidak dapat diubah", gameID)
+	}
+
+	members, err := getTeamMembers(ctx, match.TournamentID, []primitive.ObjectID{match.TeamAID, match.TeamBID})
+	if err != nil {
+		return err
+	}
+
+	// Heroes already recorded in the draft fill in stat lines that leave the hero empty
+	draftHeroes := map[primitive.ObjectID]string{}
+	if game.Draft != nil {
+		for _, pick := range game.Draft.Picks {
+			if pick.PlayerID != nil {
+				draftHeroes[*pick.PlayerID] = pick.Hero
+			}
+		}
+	}
+
+	seen := map[primitive.ObjectID]bool{}
+	mvpCount := 0
+	docs := make([]interface{}, 0, len(lines))
+	for _, line := range lines {
+		if seen[line.PlayerID] {
+			return fmt.Errorf("Stat untuk player %s dikirim lebih dari sekali", line.PlayerID.Hex())
+		}
+		seen[line.PlayerID] = true
+
+		switch {
+		case members[match.TeamAID][line.PlayerID]:
+			line.TeamID = match.TeamAID
+		case members[match.TeamBID][line.PlayerID]:
+			line.TeamID = match.TeamBID
+		default:
+			return fmt.Errorf("Player dengan ID %s bukan anggota team A atau team B", line.PlayerID.Hex())
+		}
+
+		if line.Kills < 0 || line.Deaths < 0 || line.Assists < 0 || line.Gold < 0 || line.HeroDamage < 0 || line.TurretDamage < 0 {
+			return fmt.Errorf("Stat player %s tidak boleh bernilai negatif", line.PlayerID.Hex())
+		}
+
+		if line.MVP {
+			mvpCount++
+		}
+		if strings.TrimSpace(line.Hero) == "" {
+			line.Hero = draftHeroes[line.PlayerID]
+		}
+
+		line.ID = primitive.NewObjectID()
+		line.TournamentID = match.TournamentID
+		line.MatchID = match.ID
+		line.GameID = game.ID
+		line.Win = line.TeamID == game.WinnerTeamID
+		line.CreatedAt = time.Now()
+		line.UpdatedAt = time.Now()
+		docs = append(docs, line)
+	}
+	if mvpCount > 1 {
+		return fmt.Errorf("Hanya satu player yang dapat menjadi MVP dalam satu game")
+	}
+
+	filter := bson.M{"match_id": match.ID, "game_id": game.ID}
+	if _, err := config.PlayerStatsCollection.DeleteMany(ctx, filter); err != nil {
+		fmt.Printf("ReplaceGameStats - Delete: %v\n", err)
+		return err
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+	if _, err := config.PlayerStatsCollection.InsertMany(ctx, docs); err != nil {
+		fmt.Printf("ReplaceGameStats - Insert: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// SyncGameStatsResult updates the win flag of a game's stat lines after its winner changes
+// and flags them as void when the game is voided
+func SyncGameStatsResult(ctx context.Context, game model.MatchGame) error {
+	filter := bson.M{"game_id": game.ID}
+
+	_, err := config.PlayerStatsCollection.UpdateMany(ctx, filter, bson.A{
+		bson.M{"$set": bson.M{
+			"win":        bson.M{"$eq": bson.A{"$team_id", game.WinnerTeamID}},
+			"void":       game.Status == series.GameVoid,
+			"updated_at": time.Now(),
+		}},
+	})
+	if err != nil {
+		fmt.Printf("SyncGameStatsResult: %v\n", err)
+	}
+	return err
+}
+
+// GetPlayerTournamentStats aggregates a player's stat lines per tournament, excluding voided games
+func GetPlayerTournamentStats(ctx context.Context, playerID primitive.ObjectID) ([]model.TournamentPlayerStats, error) {
+	countIf := func(field string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{field, 1, 0}}}
+	}
+
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{"player_id": playerID, "void": bson.M{"$ne": true}},
+		},
+		{
+			"$group": bson.M{
+				"_id":           "$tournament_id",
+				"games":         bson.M{"$sum": 1},
+				"wins":          countIf("$win"),
+				"mvp_count":     countIf("$mvp"),
+				"kills":         bson.M{"$sum": "$kills"},
+				"deaths":        bson.M{"$sum": "$deaths"},
+				"assists":       bson.M{"$sum": "$assists"},
+				"gold":          bson.M{"$sum": "$gold"},
+				"hero_damage":   bson.M{"$sum": "$hero_damage"},
+				"turret_damage": bson.M{"$sum": "$turret_damage"},
+			},
+		},
+		{
+			"$lookup": bson.M{
+				"from":         "tournaments",
+				"localField":   "_id",
+				"foreignField": "_id",
+				"as":           "tournament_details",
+			},
+		},
+		{
+			"$addFields": bson.M{
+				"tournament_name": bson.M{
+					"$arrayElemAt": []interface{}{"$tournament_details.name", 0},
+				},
+			},
+		},
+		{
+			"$sort": bson.M{"_id": 1},
+		},
+	}
+
+	cursor, err := config.PlayerStatsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Println("GetPlayerTournamentStats (Aggregate):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var stats []model.TournamentPlayerStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		fmt.Println("GetPlayerTournamentStats (Decode):", err)
+		return nil, err
+	}
+	return stats, nil
+}
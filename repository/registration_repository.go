@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ApplyForTournament submits a team's application to a tournament once the registration
+// window is open, the team meets the minimum roster size and the tournament is not full
+func ApplyForTournament(ctx context.Context, registration model.TournamentRegistration) (insertedID interface{}, err error) {
+	var tournament model.Tournament
+	err = config.TournamentsCollection.FindOne(ctx, notDeleted(bson.M{"_id": registration.TournamentID})).Decode(&tournament)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("Tournament dengan ID %s tidak ditemukan", registration.TournamentID.Hex())
+		}
+		return nil, err
+	}
+
+	window := tournament.Registration
+	now := time.Now()
+	if window == nil {
+		return nil, fmt.Errorf("Tournament ini tidak membuka registrasi team")
+	}
+	if now.Before(window.OpensAt) {
+		return nil, fmt.Errorf("Registrasi tournament belum dibuka")
+	}
+	if now.After(window.ClosesAt) {
+		return nil, fmt.Errorf("Registrasi tournament sudah ditutup")
+	}
+
+	var team model.Team
+	err = config.TeamsCollection.FindOne(ctx, notDeleted(bson.M{"_id": registration.TeamID})).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("Team dengan ID %s tidak ditemukan", registration.TeamID.Hex())
+		}
+		return nil, err
+	}
+	if len(team.Members) < window.MinRosterSize {
+		return nil, fmt.Errorf("Team harus memiliki minimal %d pemain untuk mendaftar", window.MinRosterSize)
+	}
+
+	for _, teamID := range tournament.TeamsParticipating {
+		if teamID == team.ID {
+			return nil, fmt.Errorf("Team sudah terdaftar di tournament ini")
+		}
+	}
+	if isDisqualified(tournament, team.ID) {
+		return nil, fmt.Errorf("Team sudah didiskualifikasi dari tournament ini")
+	}
+	if window.MaxTeams > 0 && len(tournament.TeamsParticipating) >= window.MaxTeams {
+		return nil, fmt.Errorf("Kuota tournament sudah penuh (%d team)", window.MaxTeams)
+	}
+
+	filter := bson.M{
+		"tournament_id": tournament.ID,
+		"team_id":       team.ID,
+		"status":        bson.M{"$in": []string{model.RegistrationPending, model.RegistrationApproved}},
+	}
+	count, err := config.RegistrationsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		fmt.Printf("ApplyForTournament - Count Registrations: %v\n", err)
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("Team sudah memiliki pendaftaran yang sedang diproses untuk tournament ini")
+	}
+
+	registration.ID = primitive.NewObjectID()
+	registration.Status = model.RegistrationPending
+	registration.CreatedAt = now
+	registration.UpdatedAt = now
+
+	result, err := config.RegistrationsCollection.InsertOne(ctx, registration)
+	if err != nil {
+		fmt.Printf("ApplyForTournament - Insert: %v\n", err)
+		return nil, err
+	}
+	return result.InsertedID, nil
+}
+
+// GetTournamentRegistrations retrieves the applications of a tournament, optionally filtered by status
+func GetTournamentRegistrations(ctx context.Context, tournamentID primitive.ObjectID, status string) ([]model.TournamentRegistration, error) {
+	match := bson.M{"tournament_id": tournamentID}
+	if status != "" {
+		match["status"] = status
+	}
+	return findRegistrations(ctx, match)
+}
+
+// GetUserRegistrations retrieves the applications submitted by a user
+func GetUserRegistrations(ctx context.Context, userID primitive.ObjectID) ([]model.TournamentRegistration, error) {
+	return findRegistrations(ctx, bson.M{"applied_by": userID})
+}
+
+// GetTeamRegistrations retrieves the applications of a team
+func GetTeamRegistrations(ctx context.Context, teamID primitive.ObjectID) ([]model.TournamentRegistration, error) {
+	return findRegistrations(ctx, bson.M{"team_id": teamID})
+}
+
+// findRegistrations retrieves applications with their team and tournament attached, oldest first
+func findRegistrations(ctx context.Context, match bson.M) ([]model.TournamentRegistration, error) {
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.M{"created_at": 1}},
+		{
+			"$lookup": bson.M{
+				"from":         "teams",
+				"localField":   "team_id",
+				"foreignField": "_id",
+				"as":           "team_details",
+			},
+		},
+		{
+			"$lookup": bson.M{
+				"from":         "tournaments",
+				"localField":   "tournament_id",
+				"foreignField": "_id",
+				"as":           "tournament_details",
+			},
+		},
+		{
+			"$addFields": bson.M{
+				"team": bson.M{
+					"$let": bson.M{
+						"vars": bson.M{"t": bson.M{"$arrayElemAt": []interface{}{"$team_details", 0}}},
+						"in": bson.M{
+							"_id":       "$$t._id",
+							"team_name": "$$t.team_name",
+							"logo_url":  "$$t.logo_url",
+						},
+					},
+				},
+				"tournament": bson.M{
+					"$let": bson.M{
+						"vars": bson.M{"t": bson.M{"$arrayElemAt": []interface{}{"$tournament_details", 0}}},
+						"in": bson.M{
+							"_id":  "$$t._id",
+							"name": "$$t.name",
+						},
+					},
+				},
+			},
+		},
+		{"$project": bson.M{"team_details": 0, "tournament_details": 0}},
+	}
+
+	cursor, err := config.RegistrationsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Println("findRegistrations (Aggregate):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var registrations []model.TournamentRegistration
+	if err := cursor.All(ctx, &registrations); err != nil {
+		fmt.Println("findRegistrations (Decode):", err)
+		return nil, err
+	}
+	return registrations, nil
+}
+
+// ApproveRegistration approves a pending application and adds the team to the tournament,
+// as long as the tournament still has room for it
+func ApproveRegistration(ctx context.Context, id string, reviewer primitive.ObjectID) (*model.TournamentRegistration, error) {
+	registration, err := reviewRegistration(ctx, id, reviewer, model.RegistrationApproved, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var tournament model.Tournament
+	err = config.TournamentsCollection.FindOne(ctx, notDeleted(bson.M{"_id": registration.TournamentID})).Decode(&tournament)
+	if err != nil {
+		return nil, revertRegistration(ctx, registration.ID, err)
+	}
+
+	// Filtering on the size of teams_participating keeps concurrent approvals within max_teams
+	filter := bson.M{"_id": tournament.ID}
+	if tournament.Registration != nil && tournament.Registration.MaxTeams > 0 {
+		filter[fmt.Sprintf("teams_participating.%d", tournament.Registration.MaxTeams-1)] = bson.M{"$exists": false}
+	}
+	result, err := config.TournamentsCollection.UpdateOne(ctx, filter, bumpVersion(bson.M{
+		"$addToSet": bson.M{"teams_participating": registration.TeamID},
+		"$set":      bson.M{"updated_at": time.Now()},
+	}))
+	if err != nil {
+		fmt.Printf("ApproveRegistration - Add Team: %v\n", err)
+		return nil, revertRegistration(ctx, registration.ID, err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, revertRegistration(ctx, registration.ID,
+			fmt.Errorf("Kuota tournament sudah penuh (%d team)", tournament.Registration.MaxTeams))
+	}
+
+	// A team approved after the tournament started plays with the roster it has now
+	if tournament.RostersLockedAt != nil {
+		if _, err := lockTeamRoster(ctx, tournament.ID, registration.TeamID, &reviewer, time.Now()); err != nil {
+			fmt.Printf("ApproveRegistration - Lock Roster: %v\n", err)
+			return nil, err
+		}
+	}
+	return registration, nil
+}
+
+// RejectRegistration rejects a pending application with a reason shown to the applicant
+func RejectRegistration(ctx context.Context, id string, reviewer primitive.ObjectID, reason string) (*model.TournamentRegistration, error) {
+	return reviewRegistration(ctx, id, reviewer, model.RegistrationRejected, reason)
+}
+
+// reviewRegistration moves a pending application to its reviewed status
+func reviewRegistration(ctx context.Context, id string, reviewer primitive.ObjectID, status, reason string) (*model.TournamentRegistration, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registration ID format")
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"status":      status,
+		"reviewed_by": reviewer,
+		"reviewed_at": now,
+		"updated_at":  now,
+	}
+	if reason != "" {
+		set["rejection_reason"] = reason
+	}
+
+	var registration model.TournamentRegistration
+	err = config.RegistrationsCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objID, "status": model.RegistrationPending},
+		bson.M{"$set": set},
+	).Decode(&registration)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			fmt.Printf("reviewRegistration: %v\n", err)
+			return nil, err
+		}
+		count, err := config.RegistrationsCollection.CountDocuments(ctx, bson.M{"_id": objID})
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("Pendaftaran dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("Pendaftaran dengan ID %s sudah diproses", id)
+	}
+
+	registration.Status = status
+	registration.RejectionReason = reason
+	registration.ReviewedBy = &reviewer
+	registration.ReviewedAt = &now
+	return &registration, nil
+}
+
+// revertRegistration puts an application back in the queue after its approval failed
+func revertRegistration(ctx context.Context, id primitive.ObjectID, cause error) error {
+	_, err := config.RegistrationsCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": model.RegistrationPending, "updated_at": time.Now()},
+		"$unset": bson.M{"reviewed_by": "", "reviewed_at": ""},
+	})
+	if err != nil {
+		fmt.Printf("revertRegistration: %v\n", err)
+	}
+	return cause
+}
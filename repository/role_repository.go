@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDuplicateRoleAssignment is returned when a user already has the role in the same scope
+var ErrDuplicateRoleAssignment = errors.New("User sudah memiliki role ini untuk cakupan yang sama")
+
+// GetUserRoleAssignments retrieves the staff roles of a user
+func GetUserRoleAssignments(ctx context.Context, userID primitive.ObjectID) ([]model.RoleAssignment, error) {
+	return findRoleAssignments(ctx, bson.M{"user_id": userID})
+}
+
+// GetRoleAssignments retrieves role assignments, optionally only those of one user or tournament
+func GetRoleAssignments(ctx context.Context, userID *primitive.ObjectID, tournamentID *primitive.ObjectID) ([]model.RoleAssignment, error) {
+	filter := bson.M{}
+	if userID != nil {
+		filter["user_id"] = *userID
+	}
+	if tournamentID != nil {
+		filter["tournament_id"] = *tournamentID
+	}
+	return findRoleAssignments(ctx, filter)
+}
+
+func findRoleAssignments(ctx context.Context, filter bson.M) ([]model.RoleAssignment, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := config.RoleAssignmentsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		fmt.Printf("findRoleAssignments - Find: %v\n", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	assignments := []model.RoleAssignment{}
+	if err := cursor.All(ctx, &assignments); err != nil {
+		fmt.Printf("findRoleAssignments - Decode: %v\n", err)
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// CreateRoleAssignment gives a user a staff role after checking that the user and, for a scoped
+// role, the tournament exist
+func CreateRoleAssignment(ctx context.Context, assignment model.RoleAssignment) (*model.RoleAssignment, error) {
+	count, err := config.UsersCollection.CountDocuments(ctx, notDeleted(bson.M{"_id": assignment.UserID}))
+	if err != nil {
+		fmt.Printf("CreateRoleAssignment - Check User: %v\n", err)
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("User dengan ID %s tidak ditemukan", assignment.UserID.Hex())
+	}
+
+	if assignment.TournamentID != nil {
+		count, err := config.TournamentsCollection.CountDocuments(ctx, notDeleted(bson.M{"_id": *assignment.TournamentID}))
+		if err != nil {
+			fmt.Printf("CreateRoleAssignment - Check Tournament: %v\n", err)
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("Tournament dengan ID %s tidak ditemukan", assignment.TournamentID.Hex())
+		}
+	}
+
+	duplicate := bson.M{"user_id": assignment.UserID, "role": assignment.Role, "tournament_id": bson.M{"$exists": false}}
+	if assignment.TournamentID != nil {
+		duplicate["tournament_id"] = *assignment.TournamentID
+	}
+	count, err = config.RoleAssignmentsCollection.CountDocuments(ctx, duplicate)
+	if err != nil {
+		fmt.Printf("CreateRoleAssignment - Check Duplicate: %v\n", err)
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrDuplicateRoleAssignment
+	}
+
+	assignment.CreatedAt = time.Now()
+	result, err := config.RoleAssignmentsCollection.InsertOne(ctx, assignment)
+	if err != nil {
+		fmt.Printf("CreateRoleAssignment - Insert: %v\n", err)
+		return nil, err
+	}
+	assignment.ID = result.InsertedID.(primitive.ObjectID)
+	return &assignment, nil
+}
+
+// DeleteRoleAssignment takes a staff role away again
+func DeleteRoleAssignment(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid role assignment ID format")
+	}
+
+	result, err := config.RoleAssignmentsCollection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		fmt.Printf("DeleteRoleAssignment: %v\n", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("Role assignment dengan ID %s tidak ditemukan", id)
+	}
+	return nil
+}
+
+// TournamentOf returns the tournament a match, registration or ticket belongs to. Invalid IDs and
+// missing documents give nil, leaving the handler to report them
+func TournamentOf(ctx context.Context, entityType string, id string) (*primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	collection := config.MatchesCollection
+	switch entityType {
+	case "registration":
+		collection = config.RegistrationsCollection
+	case "ticket":
+		var ticket model.UserTicket
+		err := config.UserTicketsCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&ticket)
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		if err != nil {
+			fmt.Printf("TournamentOf - Find Ticket: %v\n", err)
+			return nil, err
+		}
+		objID = ticket.MatchID
+	}
+
+	var owner struct {
+		TournamentID primitive.ObjectID `bson:"tournament_id"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"tournament_id": 1})
+	err = collection.FindOne(ctx, bson.M{"_id": objID}, opts).Decode(&owner)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		fmt.Printf("TournamentOf - Find %s: %v\n", entityType, err)
+		return nil, err
+	}
+	return &owner.TournamentID, nil
+}
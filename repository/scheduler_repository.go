@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/matchstate"
+	"embeck/pkg/scheduler"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SchedulerStore gives the background scheduler access to tournaments and matches
+type SchedulerStore struct{}
+
+var _ scheduler.Store = SchedulerStore{}
+
+// ActiveTournaments returns the tournaments that are upcoming or ongoing
+func (SchedulerStore) ActiveTournaments(ctx context.Context) ([]model.Tournament, error) {
+	filter := notDeleted(bson.M{"status": bson.M{"$in": []string{scheduler.TournamentUpcoming, scheduler.TournamentOngoing}}})
+	cursor, err := config.TournamentsCollection.Find(ctx, filter)
+	if err != nil {
+		fmt.Printf("ActiveTournaments - Find: %v\n", err)
+		return nil, err
+	}
+
+	var tournaments []model.Tournament
+	if err := cursor.All(ctx, &tournaments); err != nil {
+		fmt.Printf("ActiveTournaments - Decode: %v\n", err)
+		return nil, err
+	}
+	return tournaments, nil
+}
+
+// TournamentMatches returns every match of a tournament
+func (SchedulerStore) TournamentMatches(ctx context.Context, tournamentID primitive.ObjectID) ([]model.Match, error) {
+	cursor, err := config.MatchesCollection.Find(ctx, notDeleted(bson.M{"tournament_id": tournamentID}))
+	if err != nil {
+		fmt.Printf("TournamentMatches - Find: %v\n", err)
+		return nil, err
+	}
+
+	var matches []model.Match
+	if err := cursor.All(ctx, &matches); err != nil {
+		fmt.Printf("TournamentMatches - Decode: %v\n", err)
+		return nil, err
+	}
+	return matches, nil
+}
+
+// SetTournamentStatus moves a tournament from one status to another, leaving it alone if
+// an admin changed the status in the meantime. Starting a tournament locks its rosters
+func (SchedulerStore) SetTournamentStatus(ctx context.Context, tournamentID primitive.ObjectID, from, to string) error {
+	result, err := config.TournamentsCollection.UpdateOne(ctx,
+		bson.M{"_id": tournamentID, "status": from},
+		bumpVersion(bson.M{"$set": bson.M{"status": to, "updated_at": time.Now()}}),
+	)
+	if err != nil {
+		fmt.Printf("SetTournamentStatus: %v\n", err)
+		return err
+	}
+
+	// Rosters are locked as the tournament starts
+	if to == scheduler.TournamentOngoing && result.ModifiedCount > 0 {
+		if _, err := LockTournamentRosters(ctx, tournamentID, nil); err != nil {
+			fmt.Printf("SetTournamentStatus - Lock Rosters: %v\n", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// OpenMatches returns the matches that have not started yet or are flagged as overdue
+func (SchedulerStore) OpenMatches(ctx context.Context) ([]model.Match, error) {
+	filter := notDeleted(bson.M{"$or": []bson.M{
+		{"status": bson.M{"$in": []string{matchstate.Scheduled, matchstate.CheckedIn}}, "is_bye": bson.M{"$ne": true}},
+		{"overdue": true},
+	}})
+	cursor, err := config.MatchesCollection.Find(ctx, filter)
+	if err != nil {
+		fmt.Printf("OpenMatches - Find: %v\n", err)
+		return nil, err
+	}
+
+	var matches []model.Match
+	if err := cursor.All(ctx, &matches); err != nil {
+		fmt.Printf("OpenMatches - Decode: %v\n", err)
+		return nil, err
+	}
+	return matches, nil
+}
+
+// SetMatchOverdue sets or clears the overdue flag of a match. The flag is derived from the
+// schedule rather than edited, so it leaves the version alone and does not invalidate an
+// admin's If-Match on every tick
+func (SchedulerStore) SetMatchOverdue(ctx context.Context, matchID primitive.ObjectID, overdue bool) error {
+	update := bson.M{"$set": bson.M{"overdue": true}}
+	if !overdue {
+		update = bson.M{"$unset": bson.M{"overdue": ""}}
+	}
+
+	_, err := config.MatchesCollection.UpdateOne(ctx, bson.M{"_id": matchID}, update)
+	if err != nil {
+		fmt.Printf("SetMatchOverdue: %v\n", err)
+	}
+	return err
+}
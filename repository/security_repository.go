@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/lockout"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// loginPolicy returns the lockout policy of a throttle scope
+func loginPolicy(scope string) lockout.Policy {
+	policy := lockout.Policy{
+		MaxFailures:  config.LoginMaxFailures(),
+		Window:       config.LoginFailureWindow(),
+		LockDuration: config.LoginLockDuration(),
+		BaseDelay:    config.LoginBaseDelay(),
+		MaxDelay:     config.LoginMaxDelay(),
+	}
+	if scope == model.ThrottleIP {
+		policy.MaxFailures = config.LoginIPMaxFailures()
+	}
+	return policy
+}
+
+// throttleKey names one login throttle
+type throttleKey struct {
+	scope string
+	value string
+}
+
+// throttleKeys returns the throttles a login attempt counts against: the account, and the
+// client IP when per-IP throttling is enabled
+func throttleKeys(email string, ip string) []throttleKey {
+	keys := []throttleKey{{model.ThrottleAccount, email}}
+	if config.LoginIPThrottle() {
+		keys = append(keys, throttleKey{model.ThrottleIP, ip})
+	}
+	return keys
+}
+
+// CheckLoginThrottle returns the throttle that blocks a login attempt for the email or IP right
+// now, or nil when the attempt may go ahead. When both block, the one lasting longest wins
+func CheckLoginThrottle(ctx context.Context, email string, ip string) (*model.LoginThrottle, error) {
+	now := time.Now()
+	var scopes []bson.M
+	for _, key := range throttleKeys(email, ip) {
+		scopes = append(scopes, bson.M{"scope": key.scope, "key": key.value})
+	}
+	filter := bson.M{
+		"$or":             scopes,
+		"next_attempt_at": bson.M{"$gt": now},
+	}
+	opts := options.FindOne().SetSort(bson.M{"next_attempt_at": -1})
+
+	var throttle model.LoginThrottle
+	err := config.LoginThrottlesCollection.FindOne(ctx, filter, opts).Decode(&throttle)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		fmt.Printf("CheckLoginThrottle: %v\n", err)
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// RecordLoginFailure counts a failed login against the email and, when enabled, the client IP, delays their
+// next attempt and locks them out once their policy threshold is reached. userID is empty for
+// unknown emails, which are throttled all the same so lockouts do not reveal which accounts exist
+func RecordLoginFailure(ctx context.Context, email string, userID primitive.ObjectID, ip string, userAgent string) error {
+	event := model.SecurityEvent{
+		UserID:    userID,
+		Email:     email,
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+
+	for _, key := range throttleKeys(email, ip) {
+		throttle, err := countLoginFailure(ctx, key.scope, key.value)
+		if err != nil {
+			return err
+		}
+		if key.scope == model.ThrottleAccount {
+			event.Type = model.SecurityLoginFailed
+			event.Detail = fmt.Sprintf("%d failed attempts", throttle.Failures)
+			_ = RecordSecurityEvent(ctx, event)
+		}
+		if throttle.LockedUntil == nil {
+			continue
+		}
+
+		event.Type = model.SecurityAccountLocked
+		event.Detail = fmt.Sprintf("locked until %s after %d failed attempts", throttle.LockedUntil.Format(time.RFC3339), throttle.Failures)
+		if key.scope == model.ThrottleIP {
+			event.Type = model.SecurityIPLocked
+		}
+		_ = RecordSecurityEvent(ctx, event)
+	}
+	return nil
+}
+
+// countLoginFailure increments the failures of one throttle and applies its policy. Failures
+// older than the policy window start the count again
+func countLoginFailure(ctx context.Context, scope string, key string) (*model.LoginThrottle, error) {
+	policy := loginPolicy(scope)
+	now := time.Now()
+
+	// The count happens in a single update so concurrent attempts cannot slip under the threshold
+	update := bson.A{bson.M{"$set": bson.M{
+		"failures": bson.M{"$cond": bson.A{
+			bson.M{"$gt": bson.A{"$last_failure_at", policy.WindowStart(now)}},
+			bson.M{"$add": bson.A{"$failures", 1}},
+			1,
+		}},
+		"last_failure_at": now,
+	}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var throttle model.LoginThrottle
+	err := config.LoginThrottlesCollection.FindOneAndUpdate(ctx, bson.M{"scope": scope, "key": key}, update, opts).Decode(&throttle)
+	if err != nil {
+		fmt.Printf("countLoginFailure - Count: %v\n", err)
+		return nil, err
+	}
+
+	decision := policy.AfterFailure(throttle.Failures, now)
+	set := bson.M{"next_attempt_at": decision.NextAttemptAt}
+	if decision.LockedUntil != nil {
+		set["locked_until"] = *decision.LockedUntil
+	}
+	if _, err := config.LoginThrottlesCollection.UpdateOne(ctx, bson.M{"_id": throttle.ID}, bson.M{"$set": set}); err != nil {
+		fmt.Printf("countLoginFailure - Delay: %v\n", err)
+		return nil, err
+	}
+
+	throttle.NextAttemptAt = decision.NextAttemptAt
+	throttle.LockedUntil = decision.LockedUntil
+	return &throttle, nil
+}
+
+// ClearLoginFailures forgets the failed logins of an account after a successful login. The
+// failures of the client IP keep counting, they may target other accounts
+func ClearLoginFailures(ctx context.Context, email string) error {
+	filter := bson.M{"scope": model.ThrottleAccount, "key": email}
+	if _, err := config.LoginThrottlesCollection.DeleteOne(ctx, filter); err != nil {
+		fmt.Printf("ClearLoginFailures: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// GetAccountLock returns until when an account is locked out, or nil when it is not locked
+func GetAccountLock(ctx context.Context, email string) (*time.Time, error) {
+	filter := bson.M{
+		"scope":        model.ThrottleAccount,
+		"key":          email,
+		"locked_until": bson.M{"$gt": time.Now()},
+	}
+	var throttle model.LoginThrottle
+	err := config.LoginThrottlesCollection.FindOne(ctx, filter).Decode(&throttle)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		fmt.Printf("GetAccountLock: %v\n", err)
+		return nil, err
+	}
+	return throttle.LockedUntil, nil
+}
+
+// UnlockAccount lifts the lockout and login delay of a user and records who did it
+func UnlockAccount(ctx context.Context, user model.User, actorUsername string) error {
+	filter := bson.M{"scope": model.ThrottleAccount, "key": user.Email}
+	if _, err := config.LoginThrottlesCollection.DeleteOne(ctx, filter); err != nil {
+		fmt.Printf("UnlockAccount: %v\n", err)
+		return err
+	}
+
+	return RecordSecurityEvent(ctx, model.SecurityEvent{
+		UserID: user.ID,
+		Email:  user.Email,
+		Type:   model.SecurityAccountUnlocked,
+		Detail: "unlocked by " + actorUsername,
+	})
+}
+
+// RecordSecurityEvent stores a security event
+func RecordSecurityEvent(ctx context.Context, event model.SecurityEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if _, err := config.SecurityEventsCollection.InsertOne(ctx, event); err != nil {
+		fmt.Printf("RecordSecurityEvent: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// GetSecurityEvents retrieves a page of the security events of a user, newest first
+func GetSecurityEvents(ctx context.Context, userID primitive.ObjectID, page int, limit int) (*model.SecurityEventList, error) {
+	filter := bson.M{"user_id": userID}
+
+	total, err := config.SecurityEventsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		fmt.Println("GetSecurityEvents (Count):", err)
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := config.SecurityEventsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		fmt.Println("GetSecurityEvents (Find):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []model.SecurityEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		fmt.Println("GetSecurityEvents (Decode):", err)
+		return nil, err
+	}
+
+	return &model.SecurityEventList{
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+		Events: events,
+	}, nil
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"crypto/subtle"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/auth"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrInvalidSession is returned for refresh tokens and access tokens whose session is unknown,
+// expired or revoked
+var ErrInvalidSession = errors.New("Session tidak valid atau sudah berakhir, silakan login ulang")
+
+// ErrRoleChanged is returned for access tokens issued before the user's role changed
+var ErrRoleChanged = errors.New("Role user sudah berubah, perbarui token lewat /api/auth/refresh")
+
+// activeSession matches a session that is neither revoked nor expired
+func activeSession(id primitive.ObjectID) bson.M {
+	return bson.M{
+		"_id":        id,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+}
+
+// CreateSession starts a login session for a user and returns it with its refresh token
+func CreateSession(ctx context.Context, userID primitive.ObjectID, userAgent, ip string) (*model.Session, string, error) {
+	session := model.Session{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		UserAgent: userAgent,
+		IP:        ip,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(config.RefreshTokenTTL()),
+	}
+	refreshToken, hash, err := auth.NewRefreshToken(session.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	session.RefreshTokenHash = hash
+
+	if _, err := config.SessionsCollection.InsertOne(ctx, session); err != nil {
+		fmt.Printf("CreateSession: %v\n", err)
+		return nil, "", err
+	}
+	return &session, refreshToken, nil
+}
+
+// findSession looks up the active session of a refresh token and reports whether the token
+// is the current one of that session
+func findSession(ctx context.Context, refreshToken string) (*model.Session, bool, error) {
+	sessionID, err := auth.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, false, ErrInvalidSession
+	}
+
+	var session model.Session
+	err = config.SessionsCollection.FindOne(ctx, activeSession(sessionID)).Decode(&session)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, ErrInvalidSession
+	}
+	if err != nil {
+		fmt.Println("findSession (Find):", err)
+		return nil, false, err
+	}
+
+	current := subtle.ConstantTimeCompare([]byte(session.RefreshTokenHash), []byte(auth.HashToken(refreshToken))) == 1
+	return &session, current, nil
+}
+
+// RefreshSession rotates the refresh token of a session and returns the session, its user and
+// the new refresh token. A refresh token that was already rotated away has leaked, so presenting
+// it revokes the whole session
+func RefreshSession(ctx context.Context, refreshToken string) (*model.Session, *model.User, string, error) {
+	session, current, err := findSession(ctx, refreshToken)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if !current {
+		if err := revokeSessions(ctx, bson.M{"_id": session.ID}, model.SessionTokenReuse); err != nil {
+			fmt.Printf("RefreshSession - Revoke Reused: %v\n", err)
+		}
+		_ = RecordSecurityEvent(ctx, model.SecurityEvent{
+			UserID: session.UserID,
+			Type:   model.SecurityRefreshTokenReuse,
+			Detail: "session " + session.ID.Hex() + " revoked",
+		})
+		return nil, nil, "", ErrInvalidSession
+	}
+
+	var user model.User
+	err = config.UsersCollection.FindOne(ctx, notDeleted(bson.M{"_id": session.UserID})).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		if err := revokeSessions(ctx, bson.M{"_id": session.ID}, model.SessionUserDeleted); err != nil {
+			fmt.Printf("RefreshSession - Revoke Deleted User: %v\n", err)
+		}
+		return nil, nil, "", ErrInvalidSession
+	}
+	if err != nil {
+		fmt.Printf("RefreshSession - Find User: %v\n", err)
+		return nil, nil, "", err
+	}
+
+	newToken, hash, err := auth.NewRefreshToken(session.ID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	// Filtering on the old hash lets only one of two concurrent refreshes rotate the token
+	now := time.Now()
+	filter := activeSession(session.ID)
+	filter["refresh_token_hash"] = session.RefreshTokenHash
+	result, err := config.SessionsCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"refresh_token_hash": hash,
+		"refreshed_at":       now,
+		"expires_at":         now.Add(config.RefreshTokenTTL()),
+	}})
+	if err != nil {
+		fmt.Printf("RefreshSession - Rotate: %v\n", err)
+		return nil, nil, "", err
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil, "", ErrInvalidSession
+	}
+
+	session.RefreshTokenHash = hash
+	session.RefreshedAt = &now
+	session.ExpiresAt = now.Add(config.RefreshTokenTTL())
+	return session, &user, newToken, nil
+}
+
+// RevokeSession ends the session of a refresh token. With all, every session of its user ends
+func RevokeSession(ctx context.Context, refreshToken string, all bool) error {
+	session, current, err := findSession(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+	if !current {
+		return ErrInvalidSession
+	}
+
+	if all {
+		return RevokeUserSessions(ctx, session.UserID, model.SessionLogoutAll)
+	}
+	return revokeSessions(ctx, bson.M{"_id": session.ID}, model.SessionLogout)
+}
+
+// RevokeUserSessions ends every active session of a user
+func RevokeUserSessions(ctx context.Context, userID primitive.ObjectID, reason string) error {
+	return revokeSessions(ctx, bson.M{"user_id": userID}, reason)
+}
+
+// revokeSessions marks the matching active sessions as revoked
+func revokeSessions(ctx context.Context, filter bson.M, reason string) error {
+	filter["revoked_at"] = bson.M{"$exists": false}
+	_, err := config.SessionsCollection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"revoked_at":     time.Now(),
+		"revoked_reason": reason,
+	}})
+	if err != nil {
+		fmt.Printf("revokeSessions: %v\n", err)
+	}
+	return err
+}
+
+// CheckSession verifies that the session of an access token is still active and that its user
+// still exists with the role the token was issued with, and returns that user
+func CheckSession(ctx context.Context, claims *model.TokenClaims) (*model.User, error) {
+	sessionID, err := primitive.ObjectIDFromHex(claims.SessionID)
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+	userID, err := primitive.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+
+	filter := activeSession(sessionID)
+	filter["user_id"] = userID
+	count, err := config.SessionsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		fmt.Printf("CheckSession - Count Session: %v\n", err)
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrInvalidSession
+	}
+
+	var user model.User
+	err = config.UsersCollection.FindOne(ctx, notDeleted(bson.M{"_id": userID})).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidSession
+	}
+	if err != nil {
+		fmt.Printf("CheckSession - Find User: %v\n", err)
+		return nil, err
+	}
+	if user.Role != claims.Role {
+		return nil, ErrRoleChanged
+	}
+	return &user, nil
+}
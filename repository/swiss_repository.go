@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetSwissMatches retrieves all Swiss round matches of a tournament
+func GetSwissMatches(ctx context.Context, tournamentID primitive.ObjectID) ([]model.Match, error) {
+	filter := notDeleted(bson.M{"tournament_id": tournamentID, "swiss_round": bson.M{"$exists": true}})
+	cursor, err := config.MatchesCollection.Find(ctx, filter)
+	if err != nil {
+		fmt.Println("GetSwissMatches (Find):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var matches []model.Match
+	if err := cursor.All(ctx, &matches); err != nil {
+		fmt.Println("GetSwissMatches (Decode):", err)
+		return nil, err
+	}
+	return matches, nil
+}
+
+// CreateSwissRound validates and inserts the matches of a new Swiss round and stores the updated Swiss stage
+// state on the tournament in one transaction, so a failure leaves neither and the round can be
+// generated again. The tournament write also makes concurrent generations of a round conflict
+func CreateSwissRound(ctx context.Context, tournamentID primitive.ObjectID, stage model.SwissStage, matches []model.Match) (insertedIDs []primitive.ObjectID, err error) {
+	now := time.Now()
+	docs := make([]interface{}, len(matches))
+	for i, match := range matches {
+		match.CreatedAt = now
+		match.UpdatedAt = now
+		docs[i] = match
+	}
+
+	err = withTransaction(ctx, func(sc context.Context) error {
+		insertedIDs = nil
+
+		// Make sure the round does not exist yet, e.g. from a double submit
+		filter := notDeleted(bson.M{"tournament_id": tournamentID, "swiss_round": stage.CurrentRound})
+		count, err := config.MatchesCollection.CountDocuments(sc, filter)
+		if err != nil {
+			fmt.Printf("CreateSwissRound - Count Round Matches: %v\n", err)
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("Swiss round %d untuk tournament %s sudah dibuat", stage.CurrentRound, tournamentID.Hex())
+		}
+
+		// Pairings are checked like any new match, and only teams still taking part can play
+		var tournament model.Tournament
+		err = config.TournamentsCollection.FindOne(sc, notDeleted(bson.M{"_id": tournamentID})).Decode(&tournament)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return fmt.Errorf("Tournament dengan ID %s tidak ditemukan", tournamentID.Hex())
+			}
+			return err
+		}
+		participating := make(map[primitive.ObjectID]bool, len(tournament.TeamsParticipating))
+		for _, teamID := range tournament.TeamsParticipating {
+			participating[teamID] = true
+		}
+		for _, match := range matches {
+			if err := validateMatchTeams(sc, match); err != nil {
+				return err
+			}
+			for _, teamID := range []primitive.ObjectID{match.TeamAID, match.TeamBID} {
+				if match.IsBye && teamID == match.TeamBID {
+					continue
+				}
+				if !participating[teamID] {
+					return fmt.Errorf("Team %s tidak terdaftar di tournament %s", teamID.Hex(), tournamentID.Hex())
+				}
+			}
+		}
+
+		result, err := config.TournamentsCollection.UpdateOne(sc,
+			notDeleted(bson.M{"_id": tournamentID}),
+			bumpVersion(bson.M{"$set": bson.M{"swiss": stage, "updated_at": now}}),
+		)
+		if err != nil {
+			fmt.Printf("CreateSwissRound - Update Tournament: %v\n", err)
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return fmt.Errorf("Tournament dengan ID %s tidak ditemukan", tournamentID.Hex())
+		}
+
+		if len(docs) == 0 {
+			return nil
+		}
+		inserted, err := config.MatchesCollection.InsertMany(sc, docs)
+		if err != nil {
+			fmt.Printf("CreateSwissRound - Insert: %v\n", err)
+			return err
+		}
+		for _, id := range inserted.InsertedIDs {
+			insertedIDs = append(insertedIDs, id.(primitive.ObjectID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return insertedIDs, nil
+}
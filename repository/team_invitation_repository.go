@@ -0,0 +1,495 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/roster"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ResolveInvitee finds the player to invite, either by ML ID or through the user linked to the player
+func ResolveInvitee(ctx context.Context, mlID, userID string) (primitive.ObjectID, error) {
+	if mlID != "" {
+		var player model.Player
+		err := config.PlayersCollection.FindOne(ctx, notDeleted(bson.M{"ml_id": mlID})).Decode(&player)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return primitive.NilObjectID, fmt.Errorf("Player dengan ML ID %s tidak ditemukan", mlID)
+			}
+			return primitive.NilObjectID, err
+		}
+		return player.ID, nil
+	}
+
+	user, err := GetUserByID(ctx, userID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if user == nil {
+		return primitive.NilObjectID, fmt.Errorf("User dengan ID %s tidak ditemukan", userID)
+	}
+	if user.PlayerID == nil {
+		return primitive.NilObjectID, fmt.Errorf("User dengan ID %s belum terhubung dengan player", userID)
+	}
+	return *user.PlayerID, nil
+}
+
+// CreateTeamInvitation stores a pending invitation or join request that expires after config.TeamInvitationTTL
+func CreateTeamInvitation(ctx context.Context, invitation model.TeamInvitation) (insertedID interface{}, err error) {
+	var team model.Team
+	err = config.TeamsCollection.FindOne(ctx, notDeleted(bson.M{"_id": invitation.TeamID})).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("Team dengan ID %s tidak ditemukan", invitation.TeamID.Hex())
+		}
+		return nil, err
+	}
+
+	playerCount, err := config.PlayersCollection.CountDocuments(ctx, notDeleted(bson.M{"_id": invitation.PlayerID}))
+	if err != nil {
+		fmt.Printf("CreateTeamInvitation - Check Player: %v\n", err)
+		return nil, err
+	}
+	if playerCount == 0 {
+		return nil, fmt.Errorf("Player dengan ID %s tidak ditemukan", invitation.PlayerID.Hex())
+	}
+
+	for _, member := range roster.Of(team) {
+		if member.PlayerID == invitation.PlayerID {
+			return nil, fmt.Errorf("Player sudah menjadi anggota team ini")
+		}
+	}
+	if invitation.Role != "" && !roster.ValidRole(invitation.Role) {
+		return nil, fmt.Errorf("Role %q tidak valid, gunakan starter, substitute atau coach", invitation.Role)
+	}
+
+	if err := expireTeamInvitations(ctx, bson.M{"team_id": team.ID, "player_id": invitation.PlayerID}); err != nil {
+		return nil, err
+	}
+	pendingFilter := bson.M{
+		"team_id":   team.ID,
+		"player_id": invitation.PlayerID,
+		"status":    model.InvitationPending,
+	}
+	pendingCount, err := config.TeamInvitationsCollection.CountDocuments(ctx, pendingFilter)
+	if err != nil {
+		fmt.Printf("CreateTeamInvitation - Check Pending: %v\n", err)
+		return nil, err
+	}
+	if pendingCount > 0 {
+		return nil, fmt.Errorf("Sudah ada undangan atau permintaan bergabung yang menunggu jawaban antara player dan team ini")
+	}
+
+	now := time.Now()
+	invitation.ID = primitive.NewObjectID()
+	invitation.Status = model.InvitationPending
+	invitation.ExpiresAt = now.Add(config.TeamInvitationTTL())
+	invitation.CreatedAt = now
+	invitation.UpdatedAt = now
+
+	result, err := config.TeamInvitationsCollection.InsertOne(ctx, invitation)
+	if err != nil {
+		fmt.Printf("CreateTeamInvitation - Insert: %v\n", err)
+		return nil, err
+	}
+	return result.InsertedID, nil
+}
+
+// GetTeamInvitations retrieves the invitations and join requests of a team, optionally filtered by kind and status
+func GetTeamInvitations(ctx context.Context, teamID primitive.ObjectID, kind, status string) ([]model.TeamInvitation, error) {
+	return findTeamInvitations(ctx, bson.M{"team_id": teamID}, kind, status)
+}
+
+// GetPlayerInvitations retrieves the invitations and join requests of a player, optionally filtered by kind and status
+func GetPlayerInvitations(ctx context.Context, playerID primitive.ObjectID, kind, status string) ([]model.TeamInvitation, error) {
+	return findTeamInvitations(ctx, bson.M{"player_id": playerID}, kind, status)
+}
+
+// findTeamInvitations retrieves invitations with their team and player attached, newest first
+func findTeamInvitations(ctx context.Context, match bson.M, kind, status string) ([]model.TeamInvitation, error) {
+	if err := expireTeamInvitations(ctx, match); err != nil {
+		return nil, err
+	}
+	if kind != "" {
+		match["kind"] = kind
+	}
+	if status != "" {
+		match["status"] = status
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.M{"created_at": -1}},
+		{
+			"$lookup": bson.M{
+				"from":         "teams",
+				"localField":   "team_id",
+				"foreignField": "_id",
+				"as":           "team_details",
+			},
+		},
+		{
+			"$lookup": bson.M{
+				"from":         "players",
+				"localField":   "player_id",
+				"foreignField": "_id",
+				"as":           "player_details",
+			},
+		},
+		{
+			"$addFields": bson.M{
+				"team": bson.M{
+					"$let": bson.M{
+						"vars": bson.M{"t": bson.M{"$arrayElemAt": []interface{}{"$team_details", 0}}},
+						"in": bson.M{
+							"_id":       "$$t._id",
+							"team_name": "$$t.team_name",
+							"logo_url":  "$$t.logo_url",
+						},
+					},
+				},
+				"player": bson.M{
+					"$let": bson.M{
+						"vars": bson.M{"p": bson.M{"$arrayElemAt": []interface{}{"$player_details", 0}}},
+						"in": bson.M{
+							"_id":         "$$p._id",
+							"ml_nickname": "$$p.ml_nickname",
+							"ml_id":       "$$p.ml_id",
+							"avatar_url":  "$$p.avatar_url",
+						},
+					},
+				},
+			},
+		},
+		{"$project": bson.M{"team_details": 0, "player_details": 0}},
+	}
+
+	cursor, err := config.TeamInvitationsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Println("findTeamInvitations (Aggregate):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []model.TeamInvitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		fmt.Println("findTeamInvitations (Decode):", err)
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation lets the invited player accept a team's invitation and join the team
+func AcceptInvitation(ctx context.Context, id string, playerID, responder primitive.ObjectID) (*model.TeamInvitation, error) {
+	invitation, err := respondToInvitation(ctx, id, bson.M{"kind": model.InvitationKindInvite, "player_id": playerID}, model.InvitationAccepted, responder)
+	if err != nil {
+		return nil, err
+	}
+	if err := joinTeam(ctx, *invitation); err != nil {
+		return nil, revertTeamInvitation(ctx, invitation.ID, err)
+	}
+	return invitation, nil
+}
+
+// AcceptJoinRequest lets the team captain accept a player's join request
+func AcceptJoinRequest(ctx context.Context, id string, teamID, responder primitive.ObjectID) (*model.TeamInvitation, error) {
+	invitation, err := respondToInvitation(ctx, id, bson.M{"kind": model.InvitationKindJoinRequest, "team_id": teamID}, model.InvitationAccepted, responder)
+	if err != nil {
+		return nil, err
+	}
+	if err := joinTeam(ctx, *invitation); err != nil {
+		return nil, revertTeamInvitation(ctx, invitation.ID, err)
+	}
+	return invitation, nil
+}
+
+// DeclineInvitation lets the invited player decline a team's invitation
+func DeclineInvitation(ctx context.Context, id string, playerID, responder primitive.ObjectID) (*model.TeamInvitation, error) {
+	return respondToInvitation(ctx, id, bson.M{"kind": model.InvitationKindInvite, "player_id": playerID}, model.InvitationDeclined, responder)
+}
+
+// DeclineJoinRequest lets the team captain decline a player's join request
+func DeclineJoinRequest(ctx context.Context, id string, teamID, responder primitive.ObjectID) (*model.TeamInvitation, error) {
+	return respondToInvitation(ctx, id, bson.M{"kind": model.InvitationKindJoinRequest, "team_id": teamID}, model.InvitationDeclined, responder)
+}
+
+// CancelInvitation lets the team captain withdraw an invitation
+func CancelInvitation(ctx context.Context, id string, teamID, responder primitive.ObjectID) (*model.TeamInvitation, error) {
+	return respondToInvitation(ctx, id, bson.M{"kind": model.InvitationKindInvite, "team_id": teamID}, model.InvitationCancelled, responder)
+}
+
+// CancelJoinRequest lets the player withdraw a join request
+func CancelJoinRequest(ctx context.Context, id string, playerID, responder primitive.ObjectID) (*model.TeamInvitation, error) {
+	return respondToInvitation(ctx, id, bson.M{"kind": model.InvitationKindJoinRequest, "player_id": playerID}, model.InvitationCancelled, responder)
+}
+
+// respondToInvitation moves a pending, unexpired invitation owned by the responding party to its final status
+func respondToInvitation(ctx context.Context, id string, owner bson.M, status string, responder primitive.ObjectID) (*model.TeamInvitation, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation ID format")
+	}
+
+	if err := expireTeamInvitations(ctx, bson.M{"_id": objID}); err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": objID}
+	for key, value := range owner {
+		filter[key] = value
+	}
+
+	var invitation model.TeamInvitation
+	err = config.TeamInvitationsCollection.FindOne(ctx, filter).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("Undangan dengan ID %s tidak ditemukan", id)
+		}
+		return nil, err
+	}
+	if invitation.Status == model.InvitationExpired {
+		return nil, fmt.Errorf("Undangan dengan ID %s sudah kedaluwarsa", id)
+	}
+
+	now := time.Now()
+	result, err := config.TeamInvitationsCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": model.InvitationPending, "expires_at": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{
+			"status":       status,
+			"responded_by": responder,
+			"responded_at": now,
+			"updated_at":   now,
+		}},
+	)
+	if err != nil {
+		fmt.Printf("respondToInvitation: %v\n", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("Undangan dengan ID %s sudah diproses", id)
+	}
+
+	invitation.Status = status
+	invitation.RespondedBy = &responder
+	invitation.RespondedAt = &now
+	return &invitation, nil
+}
+
+// revertTeamInvitation puts an invitation back to pending after joining the team failed
+func revertTeamInvitation(ctx context.Context, id primitive.ObjectID, cause error) error {
+	_, err := config.TeamInvitationsCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": model.InvitationPending, "updated_at": time.Now()},
+		"$unset": bson.M{"responded_by": "", "responded_at": ""},
+	})
+	if err != nil {
+		fmt.Printf("revertTeamInvitation: %v\n", err)
+	}
+	return cause
+}
+
+// expireTeamInvitations marks the pending invitations matching the filter whose expiry has passed as expired
+func expireTeamInvitations(ctx context.Context, match bson.M) error {
+	filter := bson.M{"status": model.InvitationPending, "expires_at": bson.M{"$lte": time.Now()}}
+	for key, value := range match {
+		filter[key] = value
+	}
+
+	_, err := config.TeamInvitationsCollection.UpdateMany(ctx, filter, bson.M{
+		"$set": bson.M{"status": model.InvitationExpired, "updated_at": time.Now()},
+	})
+	if err != nil {
+		fmt.Printf("expireTeamInvitations: %v\n", err)
+	}
+	return err
+}
+
+// joinTeam adds the player of an accepted invitation to the team roster, in the invited role
+// or as the next open starter or substitute slot. A player can only be an active member of
+// one team, so the check, the roster update and its membership periods are written in one transaction
+func joinTeam(ctx context.Context, invitation model.TeamInvitation) error {
+	var team model.Team
+	err := config.TeamsCollection.FindOne(ctx, notDeleted(bson.M{"_id": invitation.TeamID})).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("Team dengan ID %s tidak ditemukan", invitation.TeamID.Hex())
+		}
+		return err
+	}
+
+	teamRoster := roster.Of(team)
+	role := invitation.Role
+	if role == "" {
+		role, err = roster.NextRole(teamRoster)
+		if err != nil {
+			return err
+		}
+	}
+
+	playing := role != roster.Coach
+	filter := bson.M{
+		"_id":              team.ID,
+		"members":          bson.M{"$ne": invitation.PlayerID},
+		"roster.player_id": bson.M{"$ne": invitation.PlayerID},
+		"$expr": bson.M{"$lt": []interface{}{
+			bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": []interface{}{"$roster", bson.A{}}},
+				"cond":  bson.M{"$eq": []interface{}{"$$this.role", role}},
+			}}},
+			roster.Limits[role],
+		}},
+	}
+	push := bson.M{"roster": model.TeamMember{PlayerID: invitation.PlayerID, Role: role}}
+	if playing {
+		push["members"] = invitation.PlayerID
+	}
+
+	return withTransaction(ctx, func(sc context.Context) error {
+		if err := backfillTeamMemberships(sc, team.ID); err != nil {
+			return err
+		}
+		if playing {
+			if err := lockPlayers(sc, []primitive.ObjectID{invitation.PlayerID}); err != nil {
+				return err
+			}
+			if err := ensureSingleTeam(sc, team.ID, []primitive.ObjectID{invitation.PlayerID}); err != nil {
+				return err
+			}
+		}
+
+		// Teams created before member roles store their derived roster first, so the role
+		// limit below can be checked in the same update that adds the member
+		if len(team.Roster) == 0 && len(teamRoster) > 0 {
+			_, err := config.TeamsCollection.UpdateOne(sc,
+				bson.M{"_id": team.ID, "roster": bson.M{"$exists": false}},
+				bumpVersion(bson.M{"$set": bson.M{"roster": teamRoster}}),
+			)
+			if err != nil {
+				fmt.Printf("joinTeam - Store Roster: %v\n", err)
+				return err
+			}
+		}
+
+		result, err := config.TeamsCollection.UpdateOne(sc, filter, bumpVersion(bson.M{
+			"$push": push,
+			"$set":  bson.M{"updated_at": time.Now()},
+		}))
+		if err != nil {
+			fmt.Printf("joinTeam - Add Member: %v\n", err)
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return fmt.Errorf("Slot %s di roster team sudah penuh atau player sudah menjadi anggota team ini", role)
+		}
+
+		if playing {
+			// The player has a team now, so their other open invitations and join requests are void
+			_, err = config.TeamInvitationsCollection.UpdateMany(sc,
+				bson.M{"player_id": invitation.PlayerID, "status": model.InvitationPending, "_id": bson.M{"$ne": invitation.ID}},
+				bson.M{"$set": bson.M{"status": model.InvitationCancelled, "updated_at": time.Now()}},
+			)
+			if err != nil {
+				fmt.Printf("joinTeam - Cancel Other Invitations: %v\n", err)
+				return err
+			}
+		}
+		return syncTeamMemberships(sc, team.ID)
+	})
+}
+
+// lockPlayers writes the players inside a transaction, so two transactions putting the same
+// player on different teams conflict and only one of them passes ensureSingleTeam
+func lockPlayers(sc context.Context, players []primitive.ObjectID) error {
+	if len(players) == 0 {
+		return nil
+	}
+	_, err := config.PlayersCollection.UpdateMany(sc,
+		bson.M{"_id": bson.M{"$in": players}},
+		bson.M{"$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		fmt.Printf("lockPlayers: %v\n", err)
+	}
+	return err
+}
+
+// ensureSingleTeam checks that none of the players is an active member of a team other than teamID
+func ensureSingleTeam(ctx context.Context, teamID primitive.ObjectID, players []primitive.ObjectID) error {
+	if len(players) == 0 {
+		return nil
+	}
+
+	filter := notDeleted(bson.M{"members": bson.M{"$in": players}})
+	if !teamID.IsZero() {
+		filter["_id"] = bson.M{"$ne": teamID}
+	}
+
+	var other model.Team
+	err := config.TeamsCollection.FindOne(ctx, filter).Decode(&other)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		fmt.Printf("ensureSingleTeam: %v\n", err)
+		return err
+	}
+	return fmt.Errorf("Player sudah menjadi anggota aktif team %s", other.TeamName)
+}
+
+// LeaveTeam removes a player or coach from their current team. The captain has to hand over
+// the captaincy before leaving
+func LeaveTeam(ctx context.Context, playerID primitive.ObjectID) (*model.Team, error) {
+	var team model.Team
+	filter := notDeleted(bson.M{"$or": []bson.M{{"members": playerID}, {"roster.player_id": playerID}}})
+	err := config.TeamsCollection.FindOne(ctx, filter).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("Player tidak ditemukan sebagai anggota team manapun")
+		}
+		return nil, err
+	}
+	if team.CaptainID == playerID {
+		return nil, fmt.Errorf("Captain tidak dapat keluar dari team sebelum menyerahkan posisi captain")
+	}
+
+	update := bson.M{
+		"$pull": bson.M{"members": playerID, "roster": bson.M{"player_id": playerID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	// Teams created before member roles keep a derived roster without the leaving player
+	if len(team.Roster) == 0 {
+		remaining := make([]primitive.ObjectID, 0, len(team.Members))
+		for _, memberID := range team.Members {
+			if memberID != playerID {
+				remaining = append(remaining, memberID)
+			}
+		}
+		update = bson.M{"$set": bson.M{
+			"members":    remaining,
+			"roster":     roster.FromMembers(remaining),
+			"updated_at": time.Now(),
+		}}
+	}
+
+	// The roster and its membership periods are written together
+	err = withTransaction(ctx, func(sc context.Context) error {
+		if err := backfillTeamMemberships(sc, team.ID); err != nil {
+			return err
+		}
+		_, err := config.TeamsCollection.UpdateOne(sc, bson.M{"_id": team.ID, "captain_id": bson.M{"$ne": playerID}}, bumpVersion(update))
+		if err != nil {
+			fmt.Printf("LeaveTeam: %v\n", err)
+			return err
+		}
+		return syncTeamMemberships(sc, team.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"embeck/config"
 	"embeck/model"
+	"embeck/pkg/roster"
 	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // InsertTeam creates a new team
@@ -24,8 +26,17 @@ func InsertTeam(ctx context.Context, team model.Team) (insertedID interface{}, e
 		return nil, fmt.Errorf("Team name %s sudah terdaftar", team.TeamName)
 	}
 
+	// Validate roster roles and sizes: 5 starters, up to 2 substitutes and a coach
+	if len(team.Roster) == 0 {
+		team.Roster = roster.FromMembers(team.Members)
+	}
+	if err := roster.Validate(team.Roster, team.CaptainID, true); err != nil {
+		return nil, err
+	}
+	team.Members = roster.Players(team.Roster)
+
 	// Validate captain exists in players collection
-	captainFilter := bson.M{"_id": team.CaptainID}
+	captainFilter := notDeleted(bson.M{"_id": team.CaptainID})
 	captainCount, err := config.PlayersCollection.CountDocuments(ctx, captainFilter)
 	if err != nil {
 		fmt.Printf("InsertTeam - Check Captain: %v\n", err)
@@ -35,9 +46,10 @@ func InsertTeam(ctx context.Context, team model.Team) (insertedID interface{}, e
 		return nil, fmt.Errorf("Captain dengan ID %s tidak ditemukan", team.CaptainID.Hex())
 	}
 
-	// Validate all members exist in players collection
-	for _, memberID := range team.Members {
-		memberFilter := bson.M{"_id": memberID}
+	// Validate all members, including the coach, exist in players collection
+	for _, member := range team.Roster {
+		memberID := member.PlayerID
+		memberFilter := notDeleted(bson.M{"_id": memberID})
 		memberCount, err := config.PlayersCollection.CountDocuments(ctx, memberFilter)
 		if err != nil {
 			fmt.Printf("InsertTeam - Check Member: %v\n", err)
@@ -52,10 +64,31 @@ func InsertTeam(ctx context.Context, team model.Team) (insertedID interface{}, e
 	team.CreatedAt = time.Now()
 	team.UpdatedAt = time.Now()
 
-	// Insert team
-	insertResult, err := config.TeamsCollection.InsertOne(ctx, team)
+	// A player can only be an active member of one team, so the check and the insert run in one
+	// transaction that also locks the players against a concurrent join or team update
+	var insertResult *mongo.InsertOneResult
+	err = withTransaction(ctx, func(sc context.Context) error {
+		if err := lockPlayers(sc, team.Members); err != nil {
+			return err
+		}
+		if err := ensureSingleTeam(sc, primitive.NilObjectID, team.Members); err != nil {
+			return err
+		}
+
+		insertResult, err = config.TeamsCollection.InsertOne(sc, team)
+		if err != nil {
+			fmt.Printf("InsertTeam - Insert: %v\n", err)
+			return err
+		}
+
+		if teamID, ok := insertResult.InsertedID.(primitive.ObjectID); ok {
+			if err := syncTeamMemberships(sc, teamID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		fmt.Printf("InsertTeam - Insert: %v\n", err)
 		return nil, err
 	}
 
@@ -65,6 +98,9 @@ func InsertTeam(ctx context.Context, team model.Team) (insertedID interface{}, e
 // GetAllTeamsWithDetails retrieves all teams with captain details
 func GetAllTeamsWithDetails(ctx context.Context) ([]model.TeamWithDetails, error) {
 	pipeline := []bson.M{
+		{
+			"$match": notDeleted(bson.M{}),
+		},
 		{
 			"$lookup": bson.M{
 				"from":         "players",
@@ -94,9 +130,11 @@ func GetAllTeamsWithDetails(ctx context.Context) ([]model.TeamWithDetails, error
 				"team_name":  1,
 				"captain_id": 1,
 				"members":    1,
+				"roster":     1,
 				"logo_url":   1,
 				"created_at": 1,
 				"updated_at": 1,
+				"version":    1,
 				"captain_details": bson.M{
 					"_id":         "$captain_details._id",
 					"name":        "$captain_details.name",
@@ -146,7 +184,7 @@ func GetTeamByIDWithDetails(ctx context.Context, id string) (*model.TeamWithDeta
 
 	pipeline := []bson.M{
 		{
-			"$match": bson.M{"_id": objID},
+			"$match": notDeleted(bson.M{"_id": objID}),
 		},
 		{
 			"$lookup": bson.M{
@@ -177,9 +215,11 @@ func GetTeamByIDWithDetails(ctx context.Context, id string) (*model.TeamWithDeta
 				"team_name":  1,
 				"captain_id": 1,
 				"members":    1,
+				"roster":     1,
 				"logo_url":   1,
 				"created_at": 1,
 				"updated_at": 1,
+				"version":    1,
 				"captain_details": bson.M{
 					"_id":         "$captain_details._id",
 					"name":        "$captain_details.name",
@@ -222,16 +262,26 @@ func GetTeamByIDWithDetails(ctx context.Context, id string) (*model.TeamWithDeta
 	return &teams[0], nil
 }
 
-// UpdateTeam updates team data
-func UpdateTeam(ctx context.Context, id string, update model.Team) (updatedID string, err error) {
+// UpdateTeam updates team data. The update only applies while the team is still at the given
+// version, otherwise ErrStaleVersion is returned
+func UpdateTeam(ctx context.Context, id string, update model.Team, version int64) (updatedID string, err error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", fmt.Errorf("invalid team ID format")
 	}
 
+	var current model.Team
+	err = config.TeamsCollection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&current)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", fmt.Errorf("Team dengan ID %s tidak ditemukan", id)
+		}
+		return "", err
+	}
+
 	// Validate captain exists if captain_id is being updated
 	if !update.CaptainID.IsZero() {
-		captainFilter := bson.M{"_id": update.CaptainID}
+		captainFilter := notDeleted(bson.M{"_id": update.CaptainID})
 		captainCount, err := config.PlayersCollection.CountDocuments(ctx, captainFilter)
 		if err != nil {
 			fmt.Printf("UpdateTeam - Check Captain: %v\n", err)
@@ -242,53 +292,261 @@ func UpdateTeam(ctx context.Context, id string, update model.Team) (updatedID st
 		}
 	}
 
+	// Validate roster roles and sizes against the resulting captain and roster
+	if len(update.Roster) == 0 && len(update.Members) > 0 {
+		update.Roster = roster.FromMembers(update.Members)
+	}
+	if len(update.Roster) > 0 || !update.CaptainID.IsZero() {
+		captainID, teamRoster := current.CaptainID, roster.Of(current)
+		if !update.CaptainID.IsZero() {
+			captainID = update.CaptainID
+		}
+		if len(update.Roster) > 0 {
+			teamRoster = update.Roster
+		}
+		if err := roster.Validate(teamRoster, captainID, len(update.Roster) > 0); err != nil {
+			return "", err
+		}
+	}
+
 	// Validate all members exist if members are being updated
-	if len(update.Members) > 0 {
-		for _, memberID := range update.Members {
-			memberFilter := bson.M{"_id": memberID}
+	if len(update.Roster) > 0 {
+		for _, member := range update.Roster {
+			memberFilter := notDeleted(bson.M{"_id": member.PlayerID})
 			memberCount, err := config.PlayersCollection.CountDocuments(ctx, memberFilter)
 			if err != nil {
 				fmt.Printf("UpdateTeam - Check Member: %v\n", err)
 				return "", err
 			}
 			if memberCount == 0 {
-				return "", fmt.Errorf("Member dengan ID %s tidak ditemukan", memberID.Hex())
+				return "", fmt.Errorf("Member dengan ID %s tidak ditemukan", member.PlayerID.Hex())
 			}
 		}
+		update.Members = roster.Players(update.Roster)
 	}
 
-	// Set updated timestamp
-	update.UpdatedAt = time.Now()
+	// Only set the provided fields so a partial update keeps the rest of the team intact
+	set := bson.M{"updated_at": time.Now()}
+	if update.TeamName != "" {
+		set["team_name"] = update.TeamName
+	}
+	if !update.CaptainID.IsZero() {
+		set["captain_id"] = update.CaptainID
+	}
+	if len(update.Roster) > 0 {
+		set["roster"] = update.Roster
+		set["members"] = update.Members
+	}
+	if update.LogoURL != "" {
+		set["logo_url"] = update.LogoURL
+	}
+
+	filter := notDeleted(versionFilter(objID, version))
+	updateData := bumpVersion(bson.M{"$set": set})
 
-	filter := bson.M{"_id": objID}
-	updateData := bson.M{"$set": update}
+	// A player can only be an active member of one team, so the check and the write run in one
+	// transaction that also locks the players against a concurrent join or team update
+	err = withTransaction(ctx, func(sc context.Context) error {
+		if err := lockPlayers(sc, update.Members); err != nil {
+			return err
+		}
+		if err := ensureSingleTeam(sc, objID, update.Members); err != nil {
+			return err
+		}
+		if len(update.Roster) > 0 {
+			if err := backfillTeamMemberships(sc, objID); err != nil {
+				return err
+			}
+		}
+
+		result, err := config.TeamsCollection.UpdateOne(sc, filter, updateData)
+		if err != nil {
+			fmt.Printf("UpdateTeam: %v\n", err)
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return staleOrMissing(sc, config.TeamsCollection, "Team", objID)
+		}
 
-	result, err := config.TeamsCollection.UpdateOne(ctx, filter, updateData)
+		if len(update.Roster) > 0 {
+			return syncTeamMemberships(sc, objID)
+		}
+		return nil
+	})
 	if err != nil {
-		fmt.Printf("UpdateTeam: %v\n", err)
 		return "", err
 	}
-	if result.ModifiedCount == 0 {
-		return "", fmt.Errorf("tidak ada data yang diupdate untuk Team ID %s", id)
+	return id, nil
+}
+
+// DeleteTeam moves a team to the trash. Its membership periods are closed until it is restored
+func DeleteTeam(ctx context.Context, id string, actor primitive.ObjectID) (deletedID string, err error) {
+	err = withTransaction(ctx, func(sc context.Context) error {
+		if err := moveToTrash(sc, model.TrashTeam, id, actor); err != nil {
+			return err
+		}
+		objID, _ := primitive.ObjectIDFromHex(id)
+		return syncTeamMemberships(sc, objID)
+	})
+	if err != nil {
+		fmt.Printf("DeleteTeam: %v\n", err)
+		return "", err
 	}
 	return id, nil
 }
 
-// DeleteTeam deletes team by ID
-func DeleteTeam(ctx context.Context, id string) (deletedID string, err error) {
+// PurgeTeam permanently deletes a trashed team. Without cascade the purge is refused while
+// tournaments, matches or other records still reference the team; with cascade the team is
+// pulled from tournaments and its dependent records are archived
+func PurgeTeam(ctx context.Context, id string, cascade bool) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return "", fmt.Errorf("invalid team ID format")
+		return fmt.Errorf("invalid team ID format")
 	}
 
-	filter := bson.M{"_id": objID}
-	result, err := config.TeamsCollection.DeleteOne(ctx, filter)
+	err = purgeWithReferences(ctx, "Team", config.TeamsCollection, objID, teamReferences(objID), cascade)
 	if err != nil {
-		fmt.Printf("DeleteTeam: %v\n", err)
-		return "", err
+		fmt.Printf("PurgeTeam: %v\n", err)
+	}
+	return err
+}
+
+// teamReferences lists the records that point at a team
+func teamReferences(teamID primitive.ObjectID) []reference {
+	reason := deleteReason("team", teamID)
+	byTeam := bson.M{"team_id": teamID}
+	byMatchTeam := bson.M{"$or": []bson.M{{"team_a_id": teamID}, {"team_b_id": teamID}}}
+
+	return []reference{
+		{
+			collection: config.TournamentsCollection,
+			field:      "teams_participating",
+			filter:     bson.M{"teams_participating": teamID},
+			cascade: func(ctx context.Context) error {
+				_, err := config.TournamentsCollection.UpdateMany(ctx,
+					bson.M{"teams_participating": teamID},
+					bumpVersion(bson.M{"$pull": bson.M{"teams_participating": teamID}}),
+				)
+				return err
+			},
+		},
+		{
+			collection: config.MatchesCollection,
+			field:      "team_a_id/team_b_id",
+			filter:     byMatchTeam,
+			cascade: func(ctx context.Context) error {
+				return archiveMatches(ctx, byMatchTeam, reason)
+			},
+		},
+		{config.RegistrationsCollection, "team_id", byTeam, archiver(config.RegistrationsCollection, byTeam, reason)},
+		{config.TournamentRostersCollection, "team_id", byTeam, archiver(config.TournamentRostersCollection, byTeam, reason)},
+		{config.TeamInvitationsCollection, "team_id", byTeam, archiver(config.TeamInvitationsCollection, byTeam, reason)},
+		{config.PlayerStatsCollection, "team_id", byTeam, archiver(config.PlayerStatsCollection, byTeam, reason)},
 	}
-	if result.DeletedCount == 0 {
-		return "", fmt.Errorf("tidak ada data yang dihapus untuk Team ID %s", id)
+}
+
+// GetTeamsBasicInfoByIDs retrieves name and logo of the given teams, keyed by team ID
+func GetTeamsBasicInfoByIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]model.TeamBasicInfo, error) {
+	teams := make(map[primitive.ObjectID]model.TeamBasicInfo, len(ids))
+	if len(ids) == 0 {
+		return teams, nil
 	}
-	return id, nil
+
+	cursor, err := config.TeamsCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		fmt.Println("GetTeamsBasicInfoByIDs (Find):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []model.TeamBasicInfo
+	if err := cursor.All(ctx, &results); err != nil {
+		fmt.Println("GetTeamsBasicInfoByIDs (Decode):", err)
+		return nil, err
+	}
+
+	for _, team := range results {
+		teams[team.ID] = team
+	}
+	return teams, nil
+}
+
+// GetTeamByID retrieves a team without details
+func GetTeamByID(ctx context.Context, id string) (*model.Team, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid team ID format")
+	}
+
+	var team model.Team
+	err = config.TeamsCollection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		fmt.Printf("GetTeamByID: %v\n", err)
+		return nil, err
+	}
+	return &team, nil
+}
+
+// UpdateTeamRoster replaces the roster roles and captain of a team. The roster can only shrink
+// or be reshuffled; new players join through team invitations. It may drop below 5 starters,
+// which blocks tournament registrations that require a full roster until the team is complete
+func UpdateTeamRoster(ctx context.Context, team model.Team, members []model.TeamMember, captainID primitive.ObjectID) error {
+	current := make(map[primitive.ObjectID]bool, len(team.Members))
+	for _, member := range roster.Of(team) {
+		current[member.PlayerID] = true
+	}
+	for _, member := range members {
+		if !current[member.PlayerID] {
+			return fmt.Errorf("Player %s belum menjadi anggota team, gunakan undangan team untuk menambah anggota", member.PlayerID.Hex())
+		}
+	}
+
+	if err := roster.Validate(members, captainID, false); err != nil {
+		return err
+	}
+
+	// The roster and its membership periods are written together
+	return withTransaction(ctx, func(sc context.Context) error {
+		if err := backfillTeamMemberships(sc, team.ID); err != nil {
+			return err
+		}
+
+		// Filtering on the version keeps a concurrent join, leave or admin edit from being overwritten
+		result, err := config.TeamsCollection.UpdateOne(sc,
+			notDeleted(versionFilter(team.ID, team.Version)),
+			bumpVersion(bson.M{"$set": bson.M{
+				"roster":     members,
+				"members":    roster.Players(members),
+				"captain_id": captainID,
+				"updated_at": time.Now(),
+			}}),
+		)
+		if err != nil {
+			fmt.Printf("UpdateTeamRoster: %v\n", err)
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return staleOrMissing(sc, config.TeamsCollection, "Team", team.ID)
+		}
+		return syncTeamMemberships(sc, team.ID)
+	})
+}
+
+// SetTeamLogo updates the logo URL of a team
+func SetTeamLogo(ctx context.Context, teamID primitive.ObjectID, logoURL string) error {
+	result, err := config.TeamsCollection.UpdateOne(ctx, bson.M{"_id": teamID}, bumpVersion(bson.M{"$set": bson.M{
+		"logo_url":   logoURL,
+		"updated_at": time.Now(),
+	}}))
+	if err != nil {
+		fmt.Printf("SetTeamLogo: %v\n", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("Team dengan ID %s tidak ditemukan", teamID.Hex())
+	}
+	return nil
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"embeck/config"
 	"embeck/model"
+	"embeck/pkg/bracket"
+	"embeck/pkg/scheduler"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -23,12 +26,20 @@ func CreateTournament(tournament *model.Tournament) (*mongo.InsertOneResult, err
 	return result, err
 }
 
-// GetAllTournaments retrieves all tournaments (admin view) with populated team details
-func GetAllTournaments() ([]model.TournamentWithDetails, error) {
+// GetAllTournaments retrieves all tournaments (admin view) with populated team details. A non-nil
+// tournamentIDs limits the list to those tournaments
+func GetAllTournaments(tournamentIDs []primitive.ObjectID) ([]model.TournamentWithDetails, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	filter := notDeleted(bson.M{})
+	if tournamentIDs != nil {
+		filter["_id"] = bson.M{"$in": tournamentIDs}
+	}
 	pipeline := []bson.M{
+		{
+			"$match": filter,
+		},
 		{
 			"$lookup": bson.M{
 				"from":         "teams",
@@ -47,6 +58,12 @@ func GetAllTournaments() ([]model.TournamentWithDetails, error) {
 				"prize_pool":         1,
 				"rules_document_url": 1,
 				"status":             1,
+				"bracket_format":     1,
+				"bracket_reset":      1,
+				"disqualifications":  1,
+				"registration":       1,
+				"rosters_locked_at":  1,
+				"version":            1,
 				"created_by":         1,
 				"created_at":         1,
 				"updated_at":         1,
@@ -87,6 +104,9 @@ func GetAllTournamentsPublic() ([]model.TournamentPublic, error) {
 
 	// Project only public fields
 	pipeline := []bson.M{
+		{
+			"$match": notDeleted(bson.M{}),
+		},
 		{
 			"$project": bson.M{
 				"_id":                1,
@@ -97,6 +117,9 @@ func GetAllTournamentsPublic() ([]model.TournamentPublic, error) {
 				"prize_pool":         1,
 				"rules_document_url": 1,
 				"status":             1,
+				"registration":       1,
+				"rosters_locked_at":  1,
+				"version":            1,
 			},
 		},
 	}
@@ -126,7 +149,7 @@ func GetTournamentByID(id string) (*model.Tournament, error) {
 	}
 
 	var tournament model.Tournament
-	err = config.TournamentsCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&tournament)
+	err = config.TournamentsCollection.FindOne(ctx, notDeleted(bson.M{"_id": objectID})).Decode(&tournament)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +171,7 @@ func GetTournamentWithDetailsByID(id string) (*model.TournamentWithDetails, erro
 	pipeline := []bson.M{
 		// Match the tournament
 		{
-			"$match": bson.M{"_id": objectID},
+			"$match": notDeleted(bson.M{"_id": objectID}),
 		},
 		// Lookup teams participating
 		{
@@ -159,12 +182,13 @@ func GetTournamentWithDetailsByID(id string) (*model.TournamentWithDetails, erro
 				"as":           "team_details",
 			},
 		},
-		// Lookup matches for this tournament
+		// Lookup matches for this tournament, skipping trashed ones
 		{
 			"$lookup": bson.M{
 				"from":         "matches",
 				"localField":   "_id",
 				"foreignField": "tournament_id",
+				"pipeline":     []bson.M{{"$match": notDeleted(bson.M{})}},
 				"as":           "match_details",
 			},
 		},
@@ -231,6 +255,12 @@ func GetTournamentWithDetailsByID(id string) (*model.TournamentWithDetails, erro
 				"prize_pool":         1,
 				"rules_document_url": 1,
 				"status":             1,
+				"bracket_format":     1,
+				"bracket_reset":      1,
+				"disqualifications":  1,
+				"registration":       1,
+				"rosters_locked_at":  1,
+				"version":            1,
 				"teams_participating": bson.M{
 					"$map": bson.M{
 						"input": "$team_details",
@@ -247,15 +277,26 @@ func GetTournamentWithDetailsByID(id string) (*model.TournamentWithDetails, erro
 						"input": "$match_details",
 						"as":    "match",
 						"in": bson.M{
-							"_id":                 "$$match._id",
-							"match_date":          "$$match.match_date",
-							"match_time":          "$$match.match_time",
-							"location":            "$$match.location",
-							"round":               "$$match.round",
-							"result_team_a_score": "$$match.result_team_a_score",
-							"result_team_b_score": "$$match.result_team_b_score",
-							"winner_team_id":      "$$match.winner_team_id",
-							"status":              "$$match.status",
+							"_id":                   "$$match._id",
+							"match_date":            "$$match.match_date",
+							"match_time":            "$$match.match_time",
+							"location":              "$$match.location",
+							"round":                 "$$match.round",
+							"result_team_a_score":   "$$match.result_team_a_score",
+							"result_team_b_score":   "$$match.result_team_b_score",
+							"winner_team_id":        "$$match.winner_team_id",
+							"status":                "$$match.status",
+							"outcome":               "$$match.outcome",
+							"forfeiting_team_id":    "$$match.forfeiting_team_id",
+							"overdue":               "$$match.overdue",
+							"bracket":               "$$match.bracket",
+							"bracket_round":         "$$match.bracket_round",
+							"bracket_position":      "$$match.bracket_position",
+							"next_match_id":         "$$match.next_match_id",
+							"next_match_slot":       "$$match.next_match_slot",
+							"loser_next_match_id":   "$$match.loser_next_match_id",
+							"loser_next_match_slot": "$$match.loser_next_match_slot",
+							"is_bye":                "$$match.is_bye",
 							"team_a": bson.M{
 								"$let": bson.M{
 									"vars": bson.M{
@@ -324,11 +365,15 @@ func GetTournamentWithDetailsByID(id string) (*model.TournamentWithDetails, erro
 		return nil, mongo.ErrNoDocuments
 	}
 
+	// Expose the bracket structure alongside the flat match list
+	results[0].Bracket = bracket.View(results[0].BracketFormat, results[0].Matches)
+
 	return &results[0], nil
 }
 
-// UpdateTournament updates a tournament
-func UpdateTournament(id string, update bson.M) error {
+// UpdateTournament updates a tournament. The update only applies while the tournament is still
+// at the given version, otherwise ErrStaleVersion is returned
+func UpdateTournament(id string, update bson.M, version int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -339,26 +384,69 @@ func UpdateTournament(id string, update bson.M) error {
 
 	update["updated_at"] = time.Now()
 
-	_, err = config.TournamentsCollection.UpdateOne(
+	result, err := config.TournamentsCollection.UpdateOne(
 		ctx,
-		bson.M{"_id": objectID},
-		bson.M{"$set": update},
+		notDeleted(versionFilter(objectID, version)),
+		bumpVersion(bson.M{"$set": update}),
 	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return staleOrMissing(ctx, config.TournamentsCollection, "Tournament", objectID)
+	}
 
-	return err
+	// Rosters are locked as the tournament starts
+	if update["status"] == scheduler.TournamentOngoing {
+		if _, err := LockTournamentRosters(ctx, objectID, nil); err != nil {
+			fmt.Printf("UpdateTournament - Lock Rosters: %v\n", err)
+			return err
+		}
+	}
+	return nil
 }
 
-// DeleteTournament deletes a tournament
-func DeleteTournament(id string) error {
+// DeleteTournament moves a tournament to the trash
+func DeleteTournament(id string, actor primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	err := moveToTrash(ctx, model.TrashTournament, id, actor)
+	if err != nil {
+		fmt.Printf("PurgeTournament: %v\n", err)
+	}
+	return err
+}
+
+// PurgeTournament permanently deletes a trashed tournament. Without cascade the purge is refused
+// while matches, registrations, rosters or stats still reference the tournament; with cascade
+// they are archived
+func PurgeTournament(ctx context.Context, id string, cascade bool) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid tournament ID format")
 	}
 
-	_, err = config.TournamentsCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+	reason := deleteReason("tournament", objectID)
+	byTournament := bson.M{"tournament_id": objectID}
+	refs := []reference{
+		{
+			collection: config.MatchesCollection,
+			field:      "tournament_id",
+			filter:     byTournament,
+			cascade: func(ctx context.Context) error {
+				return archiveMatches(ctx, byTournament, reason)
+			},
+		},
+		{config.RegistrationsCollection, "tournament_id", byTournament, archiver(config.RegistrationsCollection, byTournament, reason)},
+		{config.TournamentRostersCollection, "tournament_id", byTournament, archiver(config.TournamentRostersCollection, byTournament, reason)},
+		{config.PlayerStatsCollection, "tournament_id", byTournament, archiver(config.PlayerStatsCollection, byTournament, reason)},
+	}
+
+	err = purgeWithReferences(ctx, "Tournament", config.TournamentsCollection, objectID, refs, cascade)
+	if err != nil {
+		fmt.Printf("PurgeTournament: %v\n", err)
+	}
 	return err
 }
 
@@ -380,9 +468,9 @@ func ValidateTeamsExist(teamIDs []string) error {
 		objectIDs[i] = objectID
 	}
 
-	count, err := config.TeamsCollection.CountDocuments(ctx, bson.M{
+	count, err := config.TeamsCollection.CountDocuments(ctx, notDeleted(bson.M{
 		"_id": bson.M{"$in": objectIDs},
-	})
+	}))
 	if err != nil {
 		return err
 	}
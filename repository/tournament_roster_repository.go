@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/roster"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockTournamentRosters snapshots the current roster of every participating team. A team's
+// snapshot is only written once, so locking again only adds teams that joined since.
+// actor is nil when the lock is triggered by the tournament starting
+func LockTournamentRosters(ctx context.Context, tournamentID primitive.ObjectID, actor *primitive.ObjectID) (int, error) {
+	var tournament model.Tournament
+	err := config.TournamentsCollection.FindOne(ctx, notDeleted(bson.M{"_id": tournamentID})).Decode(&tournament)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, fmt.Errorf("Tournament dengan ID %s tidak ditemukan", tournamentID.Hex())
+		}
+		return 0, err
+	}
+
+	now := time.Now()
+	locked := 0
+	for _, teamID := range tournament.TeamsParticipating {
+		created, err := lockTeamRoster(ctx, tournament.ID, teamID, actor, now)
+		if err != nil {
+			return locked, err
+		}
+		if created {
+			locked++
+		}
+	}
+
+	_, err = config.TournamentsCollection.UpdateOne(ctx,
+		bson.M{"_id": tournament.ID, "rosters_locked_at": bson.M{"$exists": false}},
+		bumpVersion(bson.M{"$set": bson.M{"rosters_locked_at": now}}),
+	)
+	if err != nil {
+		fmt.Printf("LockTournamentRosters - Mark Tournament: %v\n", err)
+		return locked, err
+	}
+	return locked, nil
+}
+
+// lockTeamRoster stores the team's current roster for the tournament unless a snapshot exists
+func lockTeamRoster(ctx context.Context, tournamentID, teamID primitive.ObjectID, actor *primitive.ObjectID, now time.Time) (bool, error) {
+	var team model.Team
+	err := config.TeamsCollection.FindOne(ctx, notDeleted(bson.M{"_id": teamID})).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			fmt.Printf("lockTeamRoster - Team %s not found, skipped\n", teamID.Hex())
+			return false, nil
+		}
+		return false, err
+	}
+
+	snapshot := bson.M{
+		"tournament_id": tournamentID,
+		"team_id":       teamID,
+		"captain_id":    team.CaptainID,
+		"roster":        roster.Of(team),
+		"locked_at":     now,
+	}
+	if actor != nil {
+		snapshot["locked_by"] = *actor
+	}
+
+	result, err := config.TournamentRostersCollection.UpdateOne(ctx,
+		bson.M{"tournament_id": tournamentID, "team_id": teamID},
+		bson.M{"$setOnInsert": snapshot},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		fmt.Printf("lockTeamRoster: %v\n", err)
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// GetTournamentRosters returns the roster of every participating team: the locked snapshot
+// once the tournament has started, otherwise the team's current roster
+func GetTournamentRosters(ctx context.Context, tournament model.Tournament) ([]model.TournamentRoster, error) {
+	cursor, err := config.TournamentRostersCollection.Find(ctx, bson.M{"tournament_id": tournament.ID})
+	if err != nil {
+		fmt.Println("GetTournamentRosters (Find):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []model.TournamentRoster
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		fmt.Println("GetTournamentRosters (Decode):", err)
+		return nil, err
+	}
+	lockedByTeam := make(map[primitive.ObjectID]model.TournamentRoster, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshot.Locked = true
+		lockedByTeam[snapshot.TeamID] = snapshot
+	}
+
+	teams, err := getTeams(ctx, tournament.TeamsParticipating)
+	if err != nil {
+		return nil, err
+	}
+
+	rosters := make([]model.TournamentRoster, 0, len(tournament.TeamsParticipating))
+	for _, teamID := range tournament.TeamsParticipating {
+		team, exists := teams[teamID]
+		rosterEntry, locked := lockedByTeam[teamID]
+		if !locked {
+			if !exists {
+				continue
+			}
+			rosterEntry = model.TournamentRoster{
+				TournamentID: tournament.ID,
+				TeamID:       teamID,
+				CaptainID:    team.CaptainID,
+				Roster:       roster.Of(team),
+			}
+		}
+		if exists {
+			rosterEntry.Team = &model.TeamBasicInfo{ID: team.ID, TeamName: team.TeamName, LogoURL: team.LogoURL}
+		}
+		rosters = append(rosters, rosterEntry)
+	}
+	return rosters, nil
+}
+
+// getTeams loads the given teams keyed by team ID
+func getTeams(ctx context.Context, teamIDs []primitive.ObjectID) (map[primitive.ObjectID]model.Team, error) {
+	teams := make(map[primitive.ObjectID]model.Team, len(teamIDs))
+	if len(teamIDs) == 0 {
+		return teams, nil
+	}
+
+	cursor, err := config.TeamsCollection.Find(ctx, bson.M{"_id": bson.M{"$in": teamIDs}})
+	if err != nil {
+		fmt.Println("getTeams (Find):", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []model.Team
+	if err := cursor.All(ctx, &results); err != nil {
+		fmt.Println("getTeams (Decode):", err)
+		return nil, err
+	}
+	for _, team := range results {
+		teams[team.ID] = team
+	}
+	return teams, nil
+}
+
+// getTeamMembers returns the playing members (starters and substitutes) of each given team
+// for a tournament, keyed by team ID. Teams with a locked roster for the tournament use the
+// snapshot, so roster changes after the tournament started don't affect its matches
+func getTeamMembers(ctx context.Context, tournamentID primitive.ObjectID, teamIDs []primitive.ObjectID) (map[primitive.ObjectID]map[primitive.ObjectID]bool, error) {
+	rosters := make(map[primitive.ObjectID][]model.TeamMember, len(teamIDs))
+
+	cursor, err := config.TournamentRostersCollection.Find(ctx, bson.M{
+		"tournament_id": tournamentID,
+		"team_id":       bson.M{"$in": teamIDs},
+	})
+	if err != nil {
+		fmt.Println("getTeamMembers (Find Snapshots):", err)
+		return nil, err
+	}
+	var snapshots []model.TournamentRoster
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		fmt.Println("getTeamMembers (Decode Snapshots):", err)
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		rosters[snapshot.TeamID] = snapshot.Roster
+	}
+
+	var unlocked []primitive.ObjectID
+	for _, teamID := range teamIDs {
+		if _, ok := rosters[teamID]; !ok {
+			unlocked = append(unlocked, teamID)
+		}
+	}
+	teams, err := getTeams(ctx, unlocked)
+	if err != nil {
+		return nil, err
+	}
+	for teamID, team := range teams {
+		rosters[teamID] = roster.Of(team)
+	}
+
+	members := make(map[primitive.ObjectID]map[primitive.ObjectID]bool, len(rosters))
+	for teamID, teamRoster := range rosters {
+		members[teamID] = make(map[primitive.ObjectID]bool, len(teamRoster))
+		for _, playerID := range roster.Players(teamRoster) {
+			members[teamID][playerID] = true
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/roster"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TrashTypes lists the trash types in listing order
+var TrashTypes = []string{model.TrashUser, model.TrashPlayer, model.TrashTeam, model.TrashTournament, model.TrashMatch}
+
+// notDeleted adds the soft delete condition to a filter so trashed records are skipped
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// inTrash adds the soft delete condition to a filter so only trashed records match
+func inTrash(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": true}
+	return filter
+}
+
+// trashCollection returns the collection, display name field and entity name of a trash type
+func trashCollection(kind string) (*mongo.Collection, string, string, error) {
+	switch kind {
+	case model.TrashUser:
+		return config.UsersCollection, "username", "User", nil
+	case model.TrashPlayer:
+		return config.PlayersCollection, "ml_nickname", "Player", nil
+	case model.TrashTeam:
+		return config.TeamsCollection, "team_name", "Team", nil
+	case model.TrashTournament:
+		return config.TournamentsCollection, "name", "Tournament", nil
+	case model.TrashMatch:
+		return config.MatchesCollection, "round", "Match", nil
+	}
+	return nil, "", "", fmt.Errorf("invalid trash type %s", kind)
+}
+
+// moveToTrash soft deletes a record by stamping deleted_at and deleted_by
+func moveToTrash(ctx context.Context, kind string, id string, actor primitive.ObjectID) error {
+	collection, _, entity, err := trashCollection(kind)
+	if err != nil {
+		return err
+	}
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid %s ID format", kind)
+	}
+
+	now := time.Now()
+	result, err := collection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": objID}),
+		bumpVersion(bson.M{"$set": bson.M{"deleted_at": now, "deleted_by": actor, "updated_at": now}}),
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s dengan ID %s tidak ditemukan", entity, id)
+	}
+	return nil
+}
+
+// GetTrash lists soft deleted records, most recently deleted first. An empty kind lists every type
+func GetTrash(ctx context.Context, kind string) ([]model.TrashItem, error) {
+	kinds := TrashTypes
+	if kind != "" {
+		kinds = []string{kind}
+	}
+
+	items := []model.TrashItem{}
+	for _, kind := range kinds {
+		collection, nameField, _, err := trashCollection(kind)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline := []bson.M{
+			{"$match": inTrash(bson.M{})},
+			{"$project": bson.M{
+				"type":       bson.M{"$literal": kind},
+				"name":       "$" + nameField,
+				"deleted_at": 1,
+				"deleted_by": 1,
+			}},
+		}
+		cursor, err := collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			fmt.Println("GetTrash (Aggregate):", err)
+			return nil, err
+		}
+		var trashed []model.TrashItem
+		if err := cursor.All(ctx, &trashed); err != nil {
+			fmt.Println("GetTrash (Decode):", err)
+			return nil, err
+		}
+		items = append(items, trashed...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// RestoreFromTrash brings a soft deleted record back. Teams are only restored while none of
+// their players joined another team in the meantime
+func RestoreFromTrash(ctx context.Context, kind string, id string) error {
+	collection, _, entity, err := trashCollection(kind)
+	if err != nil {
+		return err
+	}
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid %s ID format", kind)
+	}
+
+	// A restored team rejoins with its roster, so the single team check, the restore and its
+	// membership periods are written in one transaction
+	return withTransaction(ctx, func(sc context.Context) error {
+		if kind == model.TrashTeam {
+			var team model.Team
+			err := config.TeamsCollection.FindOne(sc, inTrash(bson.M{"_id": objID})).Decode(&team)
+			if err == mongo.ErrNoDocuments {
+				return fmt.Errorf("%s dengan ID %s tidak ditemukan di trash", entity, id)
+			}
+			if err != nil {
+				fmt.Printf("RestoreFromTrash - Find Team: %v\n", err)
+				return err
+			}
+			players := roster.Players(roster.Of(team))
+			if err := lockPlayers(sc, players); err != nil {
+				return err
+			}
+			if err := ensureSingleTeam(sc, objID, players); err != nil {
+				return err
+			}
+		}
+
+		result, err := collection.UpdateOne(sc,
+			inTrash(bson.M{"_id": objID}),
+			bumpVersion(bson.M{
+				"$unset": bson.M{"deleted_at": "", "deleted_by": ""},
+				"$set":   bson.M{"updated_at": time.Now()},
+			}),
+		)
+		if err != nil {
+			fmt.Printf("RestoreFromTrash: %v\n", err)
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return fmt.Errorf("%s dengan ID %s tidak ditemukan di trash", entity, id)
+		}
+
+		if kind == model.TrashTeam {
+			return syncTeamMemberships(sc, objID)
+		}
+		return nil
+	})
+}
+
+// PurgeFromTrash permanently deletes a soft deleted record. Without cascade the purge is refused
+// while other records still reference it
+func PurgeFromTrash(ctx context.Context, kind string, id string, cascade bool) error {
+	switch kind {
+	case model.TrashUser:
+		return PurgeUser(ctx, id, cascade)
+	case model.TrashPlayer:
+		return PurgePlayer(ctx, id, cascade)
+	case model.TrashTeam:
+		return PurgeTeam(ctx, id, cascade)
+	case model.TrashTournament:
+		return PurgeTournament(ctx, id, cascade)
+	case model.TrashMatch:
+		return PurgeMatch(ctx, id, cascade)
+	}
+	return fmt.Errorf("invalid trash type %s", kind)
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/auth"
+	"embeck/pkg/totp"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RecoveryCodeCount is how many recovery codes are generated at a time
+const RecoveryCodeCount = 10
+
+// maxChallengeAttempts is how many wrong codes a login challenge accepts before it is spent
+const maxChallengeAttempts = 5
+
+// ErrInvalidTwoFactorCode is returned for wrong, reused or missing 2FA and recovery codes
+var ErrInvalidTwoFactorCode = errors.New("Kode 2FA atau recovery code tidak valid")
+
+// StartTwoFactorSetup stores a new pending TOTP secret for a user. The secret only becomes
+// active once EnableTwoFactor confirms it with a code
+func StartTwoFactorSetup(ctx context.Context, userID primitive.ObjectID, secret string) error {
+	result, err := config.UsersCollection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": userID, "two_factor.enabled": bson.M{"$ne": true}}),
+		bumpVersion(bson.M{"$set": bson.M{"two_factor.pending_secret": secret, "updated_at": time.Now()}}),
+	)
+	if err != nil {
+		fmt.Printf("StartTwoFactorSetup: %v\n", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("2FA sudah aktif untuk user ini")
+	}
+	return nil
+}
+
+// EnableTwoFactor activates the pending secret of a user after checking a code generated from it,
+// and returns the recovery codes. Other sessions of the user are ended since they were not
+// established with the second factor
+func EnableTwoFactor(ctx context.Context, user model.User, code string, sessionID primitive.ObjectID) ([]string, error) {
+	if user.TwoFactor == nil || user.TwoFactor.PendingSecret == "" {
+		return nil, fmt.Errorf("Belum ada setup 2FA, mulai dari /api/auth/2fa/setup")
+	}
+	step, ok := totp.Validate(user.TwoFactor.PendingSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result, err := config.UsersCollection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": user.ID, "two_factor.pending_secret": user.TwoFactor.PendingSecret}),
+		bumpVersion(bson.M{"$set": bson.M{
+			"two_factor": model.TwoFactor{
+				Enabled:            true,
+				Secret:             user.TwoFactor.PendingSecret,
+				EnabledAt:          &now,
+				LastUsedStep:       step,
+				RecoveryCodeHashes: hashes,
+			},
+			"updated_at": now,
+		}}),
+	)
+	if err != nil {
+		fmt.Printf("EnableTwoFactor: %v\n", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("Setup 2FA sudah berubah, mulai ulang dari /api/auth/2fa/setup")
+	}
+
+	err = revokeSessions(ctx, bson.M{"user_id": user.ID, "_id": bson.M{"$ne": sessionID}}, model.SessionTwoFactorEnabled)
+	if err != nil {
+		fmt.Printf("EnableTwoFactor - Revoke Sessions: %v\n", err)
+	}
+	return codes, nil
+}
+
+// VerifyTwoFactor checks a TOTP code or a recovery code of a user with 2FA enabled. A TOTP code
+// is accepted once and a recovery code is removed when used
+func VerifyTwoFactor(ctx context.Context, user model.User, code, recoveryCode string) error {
+	if user.TwoFactor == nil || !user.TwoFactor.Enabled {
+		return fmt.Errorf("2FA belum aktif untuk user ini")
+	}
+
+	var filter, update bson.M
+	switch {
+	case code != "":
+		step, ok := totp.Validate(user.TwoFactor.Secret, code, time.Now())
+		if !ok || step <= user.TwoFactor.LastUsedStep {
+			return ErrInvalidTwoFactorCode
+		}
+		// Filtering on the last step keeps the same code from being accepted twice concurrently
+		filter = bson.M{"_id": user.ID, "two_factor.last_used_step": user.TwoFactor.LastUsedStep}
+		if user.TwoFactor.LastUsedStep == 0 {
+			filter["two_factor.last_used_step"] = bson.M{"$exists": false}
+		}
+		update = bson.M{"$set": bson.M{"two_factor.last_used_step": step}}
+	case recoveryCode != "":
+		hash := auth.HashToken(totp.NormalizeRecoveryCode(recoveryCode))
+		filter = bson.M{"_id": user.ID, "two_factor.recovery_codes": hash}
+		update = bson.M{"$pull": bson.M{"two_factor.recovery_codes": hash}}
+	default:
+		return ErrInvalidTwoFactorCode
+	}
+
+	result, err := config.UsersCollection.UpdateOne(ctx, notDeleted(filter), update)
+	if err != nil {
+		fmt.Printf("VerifyTwoFactor: %v\n", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// DisableTwoFactor removes the 2FA enrollment of a user after checking a code
+func DisableTwoFactor(ctx context.Context, user model.User, code, recoveryCode string) error {
+	if err := VerifyTwoFactor(ctx, user, code, recoveryCode); err != nil {
+		return err
+	}
+
+	_, err := config.UsersCollection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": user.ID}),
+		bumpVersion(bson.M{"$unset": bson.M{"two_factor": ""}, "$set": bson.M{"updated_at": time.Now()}}),
+	)
+	if err != nil {
+		fmt.Printf("DisableTwoFactor: %v\n", err)
+	}
+	return err
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of a user after checking a TOTP code
+func RegenerateRecoveryCodes(ctx context.Context, user model.User, code string) ([]string, error) {
+	if err := VerifyTwoFactor(ctx, user, code, ""); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	_, err = config.UsersCollection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": user.ID, "two_factor.enabled": true}),
+		bumpVersion(bson.M{"$set": bson.M{"two_factor.recovery_codes": hashes, "updated_at": time.Now()}}),
+	)
+	if err != nil {
+		fmt.Printf("RegenerateRecoveryCodes: %v\n", err)
+		return nil, err
+	}
+	return codes, nil
+}
+
+// CompleteTwoFactorLogin checks the second login step and returns the user it belongs to. A wrong
+// code counts as a failed login for the account and IP like a wrong password, and while either
+// is throttled the throttle is returned instead of checking the code. The challenge is spent on
+// success or after too many wrong codes
+func CompleteTwoFactorLogin(ctx context.Context, challengeToken, code, recoveryCode, ip, userAgent string) (*model.User, *model.LoginThrottle, error) {
+	filter := bson.M{
+		"token_hash": auth.HashToken(challengeToken),
+		"purpose":    model.TokenTwoFactorLogin,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	var challenge model.UserToken
+	err := config.UserTokensCollection.FindOne(ctx, filter).Decode(&challenge)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, ErrInvalidUserToken
+	}
+	if err != nil {
+		fmt.Printf("CompleteTwoFactorLogin - Find Challenge: %v\n", err)
+		return nil, nil, err
+	}
+
+	var user model.User
+	err = config.UsersCollection.FindOne(ctx, notDeleted(bson.M{"_id": challenge.UserID})).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, ErrInvalidUserToken
+	}
+	if err != nil {
+		fmt.Printf("CompleteTwoFactorLogin - Find User: %v\n", err)
+		return nil, nil, err
+	}
+
+	email := strings.ToLower(user.Email)
+	throttle, err := CheckLoginThrottle(ctx, email, ip)
+	if err != nil {
+		return nil, nil, err
+	}
+	if throttle != nil {
+		return nil, throttle, nil
+	}
+
+	if err := VerifyTwoFactor(ctx, user, code, recoveryCode); err != nil {
+		if !errors.Is(err, ErrInvalidTwoFactorCode) {
+			return nil, nil, err
+		}
+		if err := RecordLoginFailure(ctx, email, user.ID, ip, userAgent); err != nil {
+			return nil, nil, err
+		}
+		update := bson.M{"$inc": bson.M{"attempts": 1}}
+		if challenge.Attempts+1 >= maxChallengeAttempts {
+			update["$set"] = bson.M{"used_at": time.Now()}
+		}
+		if _, err := config.UserTokensCollection.UpdateOne(ctx, bson.M{"_id": challenge.ID}, update); err != nil {
+			fmt.Printf("CompleteTwoFactorLogin - Count Attempt: %v\n", err)
+		}
+		return nil, nil, err
+	}
+
+	if _, err := consumeUserToken(ctx, challengeToken, model.TokenTwoFactorLogin); err != nil {
+		return nil, nil, err
+	}
+	return &user, nil, nil
+}
+
+// newRecoveryCodes generates recovery codes with the hashes stored for them
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := totp.GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashToken(code)
+	}
+	return codes, hashes, nil
+}
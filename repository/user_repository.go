@@ -53,7 +53,7 @@ func CreateUser(ctx context.Context, user model.User) (insertedID interface{}, e
 // GetUserByEmail retrieves user by email
 func GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	filter := bson.M{"email": email}
+	filter := notDeleted(bson.M{"email": email})
 	err := config.UsersCollection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -72,7 +72,7 @@ func GetUserByID(ctx context.Context, id string) (*model.User, error) {
 	}
 
 	var user model.User
-	filter := bson.M{"_id": objID}
+	filter := notDeleted(bson.M{"_id": objID})
 	err = config.UsersCollection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -86,7 +86,7 @@ func GetUserByID(ctx context.Context, id string) (*model.User, error) {
 // GetUserByUsername retrieves user by username
 func GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	filter := bson.M{"username": username}
+	filter := notDeleted(bson.M{"username": username})
 	err := config.UsersCollection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -99,7 +99,7 @@ func GetUserByUsername(ctx context.Context, username string) (*model.User, error
 
 // GetAllUsers retrieves all users (admin only)
 func GetAllUsers(ctx context.Context) ([]model.UserProfile, error) {
-	cursor, err := config.UsersCollection.Find(ctx, bson.M{})
+	cursor, err := config.UsersCollection.Find(ctx, notDeleted(bson.M{}))
 	if err != nil {
 		fmt.Println("GetAllUsers (Find):", err)
 		return nil, err
@@ -116,12 +116,16 @@ func GetAllUsers(ctx context.Context) ([]model.UserProfile, error) {
 	var userProfiles []model.UserProfile
 	for _, user := range users {
 		profile := model.UserProfile{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Role:      user.Role,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
+			ID:               user.ID,
+			Username:         user.Username,
+			Email:            user.Email,
+			Role:             user.Role,
+			PlayerID:         user.PlayerID,
+			EmailVerified:    user.EmailVerifiedAt != nil,
+			TwoFactorEnabled: user.TwoFactor != nil && user.TwoFactor.Enabled,
+			CreatedAt:        user.CreatedAt,
+			UpdatedAt:        user.UpdatedAt,
+			Version:          user.Version,
 		}
 		userProfiles = append(userProfiles, profile)
 	}
@@ -129,8 +133,9 @@ func GetAllUsers(ctx context.Context) ([]model.UserProfile, error) {
 	return userProfiles, nil
 }
 
-// UpdateUser updates user data
-func UpdateUser(ctx context.Context, id string, updateData bson.M) (updatedID string, err error) {
+// UpdateUser updates user data. The update only applies while the user is still at the given
+// version, otherwise ErrStaleVersion is returned
+func UpdateUser(ctx context.Context, id string, updateData bson.M, version int64) (updatedID string, err error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", fmt.Errorf("invalid user ID format")
@@ -171,40 +176,66 @@ func UpdateUser(ctx context.Context, id string, updateData bson.M) (updatedID st
 	// Set updated timestamp
 	updateData["updated_at"] = time.Now()
 
-	filter := bson.M{"_id": objID}
-	updatePayload := bson.M{"$set": updateData}
+	filter := notDeleted(versionFilter(objID, version))
+	updatePayload := bumpVersion(bson.M{"$set": updateData})
+
+	// A new email address has to be verified again
+	if _, ok := updateData["email"]; ok {
+		updatePayload["$unset"] = bson.M{"email_verified_at": ""}
+	}
 
 	result, err := config.UsersCollection.UpdateOne(ctx, filter, updatePayload)
 	if err != nil {
 		fmt.Printf("UpdateUser: %v\n", err)
 		return "", err
 	}
-	if result.ModifiedCount == 0 {
-		// Check if the user exists to return a more specific error
-		userExists, _ := GetUserByID(ctx, id)
-		if userExists == nil {
-			return "", fmt.Errorf("user with ID %s not found", id)
-		}
-		return "", fmt.Errorf("tidak ada data yang diupdate untuk User ID %s (data mungkin sama)", id)
+	if result.MatchedCount == 0 {
+		return "", staleOrMissing(ctx, config.UsersCollection, "User", objID)
 	}
 	return id, nil
 }
 
-// DeleteUser deletes user by ID
-func DeleteUser(ctx context.Context, id string) (deletedID string, err error) {
+// DeleteUser moves a user to the trash
+func DeleteUser(ctx context.Context, id string, actor primitive.ObjectID) (deletedID string, err error) {
+	if err := moveToTrash(ctx, model.TrashUser, id, actor); err != nil {
+		fmt.Printf("DeleteUser: %v\n", err)
+		return "", err
+	}
+
+	// Trashed users are logged out everywhere; access tokens are already refused by CheckSession
+	objID, _ := primitive.ObjectIDFromHex(id)
+	if err := RevokeUserSessions(ctx, objID, model.SessionUserDeleted); err != nil {
+		fmt.Printf("DeleteUser - Revoke Sessions: %v\n", err)
+	}
+	return id, nil
+}
+
+// PurgeUser permanently deletes a trashed user. Without cascade the purge is refused while
+// tickets or player links still reference the user; with cascade they are archived
+func PurgeUser(ctx context.Context, id string, cascade bool) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return "", fmt.Errorf("invalid user ID format")
+		return fmt.Errorf("invalid user ID format")
 	}
 
-	filter := bson.M{"_id": objID}
-	result, err := config.UsersCollection.DeleteOne(ctx, filter)
+	reason := deleteReason("user", objID)
+	byUser := bson.M{"user_id": objID}
+	refs := []reference{
+		{config.UserTicketsCollection, "user_id", byUser, archiver(config.UserTicketsCollection, byUser, reason)},
+		{config.PlayerLinksCollection, "user_id", byUser, archiver(config.PlayerLinksCollection, byUser, reason)},
+	}
+
+	err = purgeWithReferences(ctx, "User", config.UsersCollection, objID, refs, cascade)
 	if err != nil {
-		fmt.Printf("DeleteUser: %v\n", err)
-		return "", err
+		fmt.Printf("PurgeUser: %v\n", err)
+		return err
 	}
-	if result.DeletedCount == 0 {
-		return "", fmt.Errorf("tidak ada data yang dihapus untuk User ID %s", id)
+
+	// Sessions, email tokens and staff roles only exist for the account and go with the user
+	for _, collection := range []*mongo.Collection{config.SessionsCollection, config.UserTokensCollection, config.RoleAssignmentsCollection} {
+		if _, err := collection.DeleteMany(ctx, byUser); err != nil {
+			fmt.Printf("PurgeUser - Delete %s: %v\n", collection.Name(), err)
+		}
 	}
-	return id, nil
+	return nil
 }
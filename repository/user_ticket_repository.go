@@ -9,12 +9,14 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // PurchaseTicket creates a new ticket record for a user and match.
 func PurchaseTicket(ctx context.Context, userID, matchID primitive.ObjectID) (*model.UserTicket, error) {
 	// 1. Validate if the match exists
-	matchCount, err := config.MatchesCollection.CountDocuments(ctx, bson.M{"_id": matchID})
+	matchCount, err := config.MatchesCollection.CountDocuments(ctx, notDeleted(bson.M{"_id": matchID}))
 	if err != nil {
 		return nil, fmt.Errorf("error validating match: %w", err)
 	}
@@ -131,3 +133,84 @@ func GetTicketsByUserID(ctx context.Context, userID primitive.ObjectID) ([]model
 
 	return tickets, nil
 }
+
+// ScanTicket marks a valid ticket as used at the gate. The status filter lets a ticket through
+// only once, even when it is scanned at two gates at the same time
+func ScanTicket(ctx context.Context, id string, scannedBy primitive.ObjectID) (*model.UserTicket, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket ID format")
+	}
+
+	now := time.Now()
+	filter := bson.M{"_id": objID, "status": "valid"}
+	update := bson.M{"$set": bson.M{"status": "used", "scanned_at": now, "scanned_by": scannedBy}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var ticket model.UserTicket
+	err = config.UserTicketsCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&ticket)
+	if err == nil {
+		return &ticket, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to scan ticket: %w", err)
+	}
+
+	if err := config.UserTicketsCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&ticket); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("ticket not found")
+		}
+		return nil, fmt.Errorf("failed to scan ticket: %w", err)
+	}
+	return nil, fmt.Errorf("ticket already %s", ticket.Status)
+}
+
+// GetTournamentTicketSales counts the tickets sold and scanned for each match of a tournament
+func GetTournamentTicketSales(ctx context.Context, tournamentID primitive.ObjectID) (*model.TicketSalesReport, error) {
+	count, err := config.TournamentsCollection.CountDocuments(ctx, notDeleted(bson.M{"_id": tournamentID}))
+	if err != nil {
+		fmt.Printf("GetTournamentTicketSales - Count Tournament: %v\n", err)
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("Tournament dengan ID %s tidak ditemukan", tournamentID.Hex())
+	}
+
+	pipeline := []bson.M{
+		{
+			"$lookup": bson.M{
+				"from":         "matches",
+				"localField":   "match_id",
+				"foreignField": "_id",
+				"as":           "match",
+			},
+		},
+		{"$match": bson.M{"match.tournament_id": tournamentID}},
+		{
+			"$group": bson.M{
+				"_id":  "$match_id",
+				"sold": bson.M{"$sum": 1},
+				"used": bson.M{"$sum": bson.M{"$cond": []interface{}{bson.M{"$eq": []interface{}{"$status", "used"}}, 1, 0}}},
+			},
+		},
+		{"$sort": bson.M{"_id": 1}},
+	}
+
+	cursor, err := config.UserTicketsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Printf("GetTournamentTicketSales - Aggregate: %v\n", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	report := &model.TicketSalesReport{TournamentID: tournamentID, Matches: []model.MatchTicketSales{}}
+	if err := cursor.All(ctx, &report.Matches); err != nil {
+		fmt.Printf("GetTournamentTicketSales - Decode: %v\n", err)
+		return nil, err
+	}
+	for _, sales := range report.Matches {
+		report.Sold += sales.Sold
+		report.Used += sales.Used
+	}
+	return report, nil
+}
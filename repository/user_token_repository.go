@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"embeck/config"
+	"embeck/model"
+	"embeck/pkg/auth"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInvalidUserToken is returned for email tokens that are unknown, used or expired
+var ErrInvalidUserToken = errors.New("Token tidak valid, sudah dipakai atau sudah kedaluwarsa")
+
+// IssueUserToken creates a single-use token for a user and returns it. Earlier unused tokens
+// of the same purpose stop working so only the latest email link is valid
+func IssueUserToken(ctx context.Context, user model.User, purpose string, ttl time.Duration) (string, error) {
+	token, hash, err := auth.NewOneTimeToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = config.UserTokensCollection.DeleteMany(ctx, bson.M{
+		"user_id": user.ID,
+		"purpose": purpose,
+		"used_at": bson.M{"$exists": false},
+	})
+	if err != nil {
+		fmt.Printf("IssueUserToken - Drop Previous: %v\n", err)
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = config.UserTokensCollection.InsertOne(ctx, model.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Email:     user.Email,
+		TokenHash: hash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		fmt.Printf("IssueUserToken - Insert: %v\n", err)
+		return "", err
+	}
+	return token, nil
+}
+
+// consumeUserToken marks a valid token as used and returns it, so each token works only once
+func consumeUserToken(ctx context.Context, token string, purpose string) (*model.UserToken, error) {
+	filter := bson.M{
+		"token_hash": auth.HashToken(token),
+		"purpose":    purpose,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+
+	var userToken model.UserToken
+	err := config.UserTokensCollection.FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&userToken)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidUserToken
+	}
+	if err != nil {
+		fmt.Printf("consumeUserToken: %v\n", err)
+		return nil, err
+	}
+	return &userToken, nil
+}
+
+// VerifyUserEmail marks the email of a user as verified. The token only counts for the address
+// it was sent to, so changing the email in the meantime invalidates it
+func VerifyUserEmail(ctx context.Context, token string) error {
+	userToken, err := consumeUserToken(ctx, token, model.TokenEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result, err := config.UsersCollection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": userToken.UserID, "email": userToken.Email}),
+		bumpVersion(bson.M{"$set": bson.M{"email_verified_at": now, "updated_at": now}}),
+	)
+	if err != nil {
+		fmt.Printf("VerifyUserEmail: %v\n", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrInvalidUserToken
+	}
+	return nil
+}
+
+// ResetUserPassword sets a new password hash for the user of a reset token and ends all of
+// the user's sessions
+func ResetUserPassword(ctx context.Context, token string, hashedPassword string) error {
+	userToken, err := consumeUserToken(ctx, token, model.TokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	result, err := config.UsersCollection.UpdateOne(ctx,
+		notDeleted(bson.M{"_id": userToken.UserID}),
+		bumpVersion(bson.M{"$set": bson.M{"password": hashedPassword, "updated_at": time.Now()}}),
+	)
+	if err != nil {
+		fmt.Printf("ResetUserPassword: %v\n", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrInvalidUserToken
+	}
+
+	if err := RevokeUserSessions(ctx, userToken.UserID, model.SessionPasswordReset); err != nil {
+		fmt.Printf("ResetUserPassword - Revoke Sessions: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrStaleVersion is returned by versioned updates when the document changed since it was read
+var ErrStaleVersion = errors.New("data sudah diubah oleh request lain, muat ulang data lalu coba lagi")
+
+// bumpVersion adds the version increment to an update document. Every write to a versioned
+// collection goes through it so ETags handed out earlier become stale
+func bumpVersion(update bson.M) bson.M {
+	inc, ok := update["$inc"].(bson.M)
+	if !ok {
+		inc = bson.M{}
+	}
+	inc["version"] = 1
+	update["$inc"] = inc
+	return update
+}
+
+// versionFilter matches a document by ID at the given version. Documents written before
+// versioning have no version field and count as version 0
+func versionFilter(id primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		return bson.M{"_id": id, "$or": []bson.M{{"version": 0}, {"version": bson.M{"$exists": false}}}}
+	}
+	return bson.M{"_id": id, "version": version}
+}
+
+// staleOrMissing explains a versioned update that matched nothing: either the document is
+// gone or it has a newer version
+func staleOrMissing(ctx context.Context, collection *mongo.Collection, entity string, id primitive.ObjectID) error {
+	count, err := collection.CountDocuments(ctx, notDeleted(bson.M{"_id": id}))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%s dengan ID %s tidak ditemukan", entity, id.Hex())
+	}
+	return ErrStaleVersion
+}
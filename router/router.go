@@ -3,6 +3,7 @@ package router
 import (
 	"embeck/config/middleware"
 	"embeck/handler"
+	"embeck/pkg/permission"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
@@ -24,8 +25,23 @@ func SetupRoutes(app *fiber.App) {
 	public := api.Group("/")
 	public.Post("/auth/register", handler.Register)
 	public.Post("/auth/login", handler.Login)
+	public.Post("/auth/login/2fa", handler.LoginTwoFactor)
+	public.Post("/auth/refresh", handler.RefreshToken)
+	public.Post("/auth/logout", handler.Logout)
+	public.Post("/auth/verify-email", handler.VerifyEmail)
+	public.Post("/auth/resend-verification", handler.ResendVerification)
+	public.Post("/auth/forgot-password", handler.ForgotPassword)
+	public.Post("/auth/reset-password", handler.ResetPassword)
 	public.Get("/tournaments", handler.GetAllTournamentsPublic)
 	public.Get("/tournaments/:id", handler.GetTournamentWithDetailsByID)
+	public.Get("/tournaments/:id/standings", handler.GetTournamentStandings)
+	public.Get("/tournaments/:id/swiss/standings", handler.GetSwissStandings)
+	public.Get("/tournaments/:id/draft-stats", handler.GetTournamentDraftStats)
+	public.Get("/tournaments/:id/rosters", handler.GetTournamentRosters)
+	public.Get("/players/:id/stats", handler.GetPlayerStats)
+	public.Get("/players/:id/transfers", handler.GetPlayerTransfers)
+	public.Get("/teams/:id/transfers", handler.GetTeamTransfers)
+	public.Get("/transfers", handler.GetRecentTransfers)
 
 	// ==================
 	// Authenticated User Routes (User & Admin)
@@ -33,50 +49,138 @@ func SetupRoutes(app *fiber.App) {
 	authRequired := api.Group("/")
 	authRequired.Use(middleware.AuthMiddleware())
 	authRequired.Get("/auth/profile", handler.GetProfile) // Now requires auth
+	authRequired.Post("/auth/2fa/setup", handler.SetupTwoFactor)
+	authRequired.Post("/auth/2fa/enable", handler.EnableTwoFactor)
+	authRequired.Post("/auth/2fa/disable", handler.DisableTwoFactor)
+	authRequired.Post("/auth/2fa/recovery-codes", handler.RegenerateRecoveryCodes)
 	authRequired.Post("/tickets/purchase", handler.HandlePurchaseTicket)
 	authRequired.Get("/me/tickets", handler.HandleGetUserTickets)
+	authRequired.Get("/me/registrations", handler.GetMyRegistrations)
+	authRequired.Post("/me/player-link", handler.RequestPlayerLink)
+	authRequired.Get("/me/player-link", handler.GetMyPlayerLinks)
+	authRequired.Get("/me/invitations", handler.GetMyInvitations)
+	authRequired.Post("/me/invitations/:id/accept", handler.AcceptInvitation)
+	authRequired.Post("/me/invitations/:id/decline", handler.DeclineInvitation)
+	authRequired.Post("/me/join-requests/:id/cancel", handler.CancelJoinRequest)
+	authRequired.Post("/me/team/leave", handler.LeaveTeam)
+	authRequired.Post("/teams/:id/join-requests", handler.RequestToJoinTeam)
 
 	// ==================
-	// Admin Only Routes
+	// Team Captain Routes (verified captain or admin)
+	// ==================
+	captain := api.Group("/captain/teams/:id")
+	captain.Use(middleware.AuthMiddleware(), middleware.TeamCaptainMiddleware())
+	captain.Get("/", handler.GetCaptainTeam)
+	captain.Put("/roster", handler.UpdateCaptainRoster)
+	captain.Post("/logo", handler.UploadCaptainTeamLogo)
+	captain.Get("/registrations", handler.GetCaptainTeamRegistrations)
+	captain.Post("/registrations", handler.ApplyForTournament)
+	captain.Get("/invitations", handler.GetTeamInvitations)
+	captain.Post("/invitations", handler.InviteToTeam)
+	captain.Post("/invitations/:invitationId/cancel", handler.CancelTeamInvitation)
+	captain.Post("/join-requests/:invitationId/accept", handler.AcceptJoinRequest)
+	captain.Post("/join-requests/:invitationId/decline", handler.DeclineJoinRequest)
+
+	// ==================
+	// Staff Routes (admin, or a staff role granting the route's permission)
 	// ==================
 	admin := api.Group("/admin")
-	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	admin.Use(middleware.AuthMiddleware(), middleware.AuditMiddleware())
+
+	managePlayers := middleware.RequirePermission(permission.PlayerManage)
+	manageTeams := middleware.RequirePermission(permission.TeamManage)
+	manageTournaments := middleware.RequirePermission(permission.TournamentManage, middleware.TournamentParam)
+	reviewRegistrations := middleware.RequirePermission(permission.RegistrationReview, middleware.TournamentParam)
+	reviewRegistration := middleware.RequirePermission(permission.RegistrationReview, middleware.TournamentOf("registration"))
+	manageMatches := middleware.RequirePermission(permission.MatchManage, middleware.TournamentOf("match"))
+	scoreMatches := middleware.RequirePermission(permission.MatchScore, middleware.TournamentOf("match"))
+	manageUsers := middleware.RequirePermission(permission.UserManage)
 
 	// Player Management (Admin)
-	admin.Get("/players", handler.GetAllPlayers)
-	admin.Post("/players", handler.CreatePlayer)
-	admin.Get("/players/:id", handler.GetPlayerByID)
-	admin.Put("/players/:id", handler.UpdatePlayer)
-	admin.Delete("/players/:id", handler.DeletePlayer)
+	admin.Get("/players", managePlayers, handler.GetAllPlayers)
+	admin.Post("/players", managePlayers, handler.CreatePlayer)
+	admin.Get("/players/:id", managePlayers, handler.GetPlayerByID)
+	admin.Put("/players/:id", managePlayers, handler.UpdatePlayer)
+	admin.Delete("/players/:id", managePlayers, handler.DeletePlayer)
 
 	// Team Management (Admin)
-	admin.Get("/teams", handler.GetAllTeams)
-	admin.Post("/teams", handler.CreateTeam)
-	admin.Get("/teams/:id", handler.GetTeamByID)
-	admin.Put("/teams/:id", handler.UpdateTeam)
-	admin.Delete("/teams/:id", handler.DeleteTeam)
+	admin.Get("/teams", manageTeams, handler.GetAllTeams)
+	admin.Post("/teams", manageTeams, handler.CreateTeam)
+	admin.Get("/teams/:id", manageTeams, handler.GetTeamByID)
+	admin.Put("/teams/:id", manageTeams, handler.UpdateTeam)
+	admin.Delete("/teams/:id", manageTeams, handler.DeleteTeam)
 
 	// Tournament Management (Admin)
-	admin.Get("/tournaments", handler.GetAllTournaments)
-	admin.Post("/tournaments", handler.CreateTournament)
-	admin.Get("/tournaments/:id", handler.GetTournamentByID)
-	admin.Put("/tournaments/:id", handler.UpdateTournament)
-	admin.Delete("/tournaments/:id", handler.DeleteTournament)
+	admin.Get("/tournaments", middleware.RequirePermissionInAnyTournament(permission.TournamentManage), handler.GetAllTournaments)
+	admin.Post("/tournaments", manageTournaments, handler.CreateTournament)
+	admin.Get("/tournaments/:id", manageTournaments, handler.GetTournamentByID)
+	admin.Put("/tournaments/:id", manageTournaments, handler.UpdateTournament)
+	admin.Delete("/tournaments/:id", manageTournaments, handler.DeleteTournament)
+	admin.Post("/tournaments/:id/bracket", manageTournaments, handler.GenerateBracket)
+	admin.Post("/tournaments/:id/groups", manageTournaments, handler.GenerateGroupStage)
+	admin.Post("/tournaments/:id/swiss/next-round", manageTournaments, handler.GenerateSwissRound)
+	admin.Post("/tournaments/:id/disqualify", manageTournaments, handler.DisqualifyTeam)
+	admin.Post("/tournaments/:id/rosters/lock", manageTournaments, handler.LockTournamentRosters)
+	admin.Get("/tournaments/:id/registrations", reviewRegistrations, handler.GetTournamentRegistrations)
+	admin.Get("/tournaments/:id/ticket-sales", middleware.RequirePermission(permission.FinanceView, middleware.TournamentParam), handler.GetTournamentTicketSales)
+	admin.Post("/registrations/:id/approve", reviewRegistration, handler.ApproveRegistration)
+	admin.Post("/registrations/:id/reject", reviewRegistration, handler.RejectRegistration)
 
 	// Match Management (Admin)
-	admin.Get("/matches", handler.GetAllMatches)
-	admin.Post("/matches", handler.CreateMatch)
-	admin.Get("/matches/:id", handler.GetMatchByID)
-	admin.Put("/matches/:id", handler.UpdateMatch)
-	admin.Delete("/matches/:id", handler.DeleteMatch)
+	admin.Get("/matches", middleware.RequirePermission(permission.MatchManage, middleware.TournamentQuery), handler.GetAllMatches)
+	admin.Post("/matches", middleware.RequirePermission(permission.MatchManage, middleware.TournamentBody), handler.CreateMatch)
+	admin.Get("/matches/:id", scoreMatches, handler.GetMatchByID)
+	admin.Put("/matches/:id", manageMatches, handler.UpdateMatch)
+	admin.Delete("/matches/:id", manageMatches, handler.DeleteMatch)
+	admin.Post("/matches/:id/games", scoreMatches, handler.AddMatchGame)
+	admin.Put("/matches/:id/games/:gameId", scoreMatches, handler.UpdateMatchGame)
+	admin.Post("/matches/:id/games/:gameId/void", scoreMatches, handler.VoidMatchGame)
+	admin.Put("/matches/:id/games/:gameId/draft", scoreMatches, handler.SetGameDraft)
+	admin.Put("/matches/:id/games/:gameId/stats", scoreMatches, handler.SubmitGameStats)
+
+	// Match Status Transitions (Admin)
+	admin.Post("/matches/:id/check-in", scoreMatches, handler.CheckInMatch)
+	admin.Post("/matches/:id/start", scoreMatches, handler.StartMatch)
+	admin.Post("/matches/:id/complete", scoreMatches, handler.CompleteMatch)
+	admin.Post("/matches/:id/postpone", manageMatches, handler.PostponeMatch)
+	admin.Post("/matches/:id/reschedule", manageMatches, handler.RescheduleMatch)
+	admin.Post("/matches/:id/cancel", manageMatches, handler.CancelMatch)
+	admin.Post("/matches/:id/forfeit", manageMatches, handler.ForfeitMatch)
+
+	// Ticket Gate (Staff)
+	admin.Post("/tickets/:id/scan", middleware.RequirePermission(permission.TicketScan, middleware.TournamentOf("ticket")), handler.ScanTicket)
 
 	// User Management (Admin)
-	admin.Get("/users", handler.GetAllUsers)
-	admin.Get("/users/:id", handler.GetUserByID)
-	admin.Put("/users/:id", handler.UpdateUser)
-	admin.Delete("/users/:id", handler.DeleteUser)
+	admin.Get("/users", manageUsers, handler.GetAllUsers)
+	admin.Get("/users/:id", manageUsers, handler.GetUserByID)
+	admin.Put("/users/:id", manageUsers, handler.UpdateUser)
+	admin.Delete("/users/:id", manageUsers, handler.DeleteUser)
+	admin.Delete("/users/:id/player-link", manageUsers, handler.UnlinkPlayer)
+	admin.Post("/users/:id/unlock", manageUsers, handler.UnlockUser)
+	admin.Get("/users/:id/security-events", manageUsers, handler.GetUserSecurityEvents)
+
+	// Staff Roles (Admin)
+	manageRoles := middleware.RequirePermission(permission.RoleManage)
+	admin.Get("/roles", manageRoles, handler.GetRoles)
+	admin.Get("/role-assignments", manageRoles, handler.GetRoleAssignments)
+	admin.Post("/role-assignments", manageRoles, handler.CreateRoleAssignment)
+	admin.Delete("/role-assignments/:id", manageRoles, handler.DeleteRoleAssignment)
+
+	// Player Links (Admin)
+	admin.Get("/player-links", managePlayers, handler.GetPlayerLinks)
+	admin.Post("/player-links/:id/verify", managePlayers, handler.VerifyPlayerLink)
+	admin.Post("/player-links/:id/reject", managePlayers, handler.RejectPlayerLink)
+
+	// Trash (Admin)
+	manageTrash := middleware.RequirePermission(permission.TrashManage)
+	admin.Get("/trash", manageTrash, handler.GetTrash)
+	admin.Post("/trash/:type/:id/restore", manageTrash, handler.RestoreFromTrash)
+	admin.Delete("/trash/:type/:id", manageTrash, handler.PurgeFromTrash)
+
+	// Audit Logs (Admin)
+	admin.Get("/audit-logs", middleware.RequirePermission(permission.AuditView), handler.GetAuditLogs)
 
 	// Upload routes (Admin)
-	admin.Post("/upload/team-logo", handler.UploadTeamLogo)
-	admin.Post("/upload/player-avatar", handler.UploadPlayerAvatar)
+	admin.Post("/upload/team-logo", manageTeams, handler.UploadTeamLogo)
+	admin.Post("/upload/player-avatar", managePlayers, handler.UploadPlayerAvatar)
 }
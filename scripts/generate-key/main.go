@@ -0,0 +1,32 @@
+// Command generate-key membuat keypair Ed25519 baru untuk token PASETO dalam format hex yang
+// dibaca server.
+//
+// Rotasi key tanpa membuat semua user logout:
+//  1. Tambahkan public key baru ke PASETO_PUBLIC_KEYS di semua instance.
+//  2. Ganti PASETO_KEY_ID dan PASETO_PRIVATE_KEY ke key baru.
+//  3. Pindahkan public key lama ke PASETO_PUBLIC_KEYS dengan waktu pensiun setelah
+//     ACCESS_TOKEN_TTL berlalu, mis. "v1=ab12...@2026-11-01T00:00:00Z".
+//
+// Jalankan dengan: go run ./scripts/generate-key -id v2
+package main
+
+import (
+	"embeck/pkg/auth"
+	"flag"
+	"fmt"
+	"time"
+)
+
+func main() {
+	keyID := flag.String("id", "v"+time.Now().Format("20060102"), "ID key yang ditulis di footer token")
+	flag.Parse()
+
+	privateKey, publicKey := auth.GenerateKeyPair()
+
+	fmt.Printf("# Key penandatangan baru %q\n", *keyID)
+	fmt.Printf("PASETO_KEY_ID=%s\n", *keyID)
+	fmt.Printf("PASETO_PRIVATE_KEY=%s\n", privateKey)
+	fmt.Println()
+	fmt.Println("# Entri untuk PASETO_PUBLIC_KEYS, tambahkan ke semua instance sebelum key ini dipakai")
+	fmt.Printf("%s=%s\n", *keyID, publicKey)
+}